@@ -0,0 +1,6 @@
+package common_constants
+
+const (
+	LambdaRequestID = "lambdaRequestId"
+	AuditPrincipal  = "auditPrincipal"
+)
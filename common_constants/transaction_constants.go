@@ -1,7 +1,19 @@
 package common_constants
 
 const (
-	ReadTransaction        = "readTransaction"
-	WriteTransaction       = "writeTransaction"
-	ConditionalCheckFailed = "ConditionalCheckFailed"
+	ReadTransaction               = "readTransaction"
+	ReadTransactionResults        = "readTransactionResults"
+	ReadTransactionRollbackOnly   = "readTransactionRollbackOnly"
+	WriteTransaction              = "writeTransaction"
+	WriteTransactionItems         = "writeTransactionItems"
+	WriteTransactionSequence      = "writeTransactionSequence"
+	WriteTransactionScope         = "writeTransactionScope"
+	WriteTransactionBestEffort    = "writeTransactionBestEffort"
+	WriteTransactionRollbackOnly  = "writeTransactionRollbackOnly"
+	ConditionalCheckFailed        = "ConditionalCheckFailed"
+	IdempotentParameterMismatch   = "IdempotentParameterMismatch"
+	TransactionConflict           = "TransactionConflict"
+	ThrottlingError               = "ThrottlingError"
+	ProvisionedThroughputExceeded = "ProvisionedThroughputExceeded"
+	TransactionLimitExceeded      = "TransactionLimitExceeded"
 )
@@ -0,0 +1,95 @@
+package common_errors
+
+import "github.com/Drathveloper/lambda_commons/v2/common_constants"
+
+type TransactionError interface {
+	GenericApplicationError
+	Reason() string
+	ItemIndex() int
+	ItemLabel() string
+}
+
+type TransactionConflictError interface {
+	TransactionError
+	isTransactionConflict()
+}
+
+type TransactionThrottledError interface {
+	TransactionError
+	isTransactionThrottled()
+}
+
+type transactionError struct {
+	genericApplicationError
+	reason    string
+	itemIndex int
+	itemLabel string
+}
+
+func (error *transactionError) Reason() string {
+	return error.reason
+}
+
+func (error *transactionError) ItemIndex() int {
+	return error.itemIndex
+}
+
+func (error *transactionError) ItemLabel() string {
+	return error.itemLabel
+}
+
+type transactionConflictError struct {
+	transactionError
+}
+
+func (error *transactionConflictError) isTransactionConflict() {}
+
+type transactionThrottledError struct {
+	transactionError
+}
+
+func (error *transactionThrottledError) isTransactionThrottled() {}
+
+func NewTransactionError(httpStatus int, message string, reason string, itemIndex int, itemLabel string) TransactionError {
+	return newTransactionError(httpStatus, message, reason, itemIndex, itemLabel)
+}
+
+func newTransactionError(httpStatus int, message string, reason string, itemIndex int, itemLabel string) *transactionError {
+	return &transactionError{
+		genericApplicationError: genericApplicationError{
+			httpStatus: httpStatus,
+			message:    message,
+		},
+		reason:    reason,
+		itemIndex: itemIndex,
+		itemLabel: itemLabel,
+	}
+}
+
+func NewTransactionLimitExceededError(message string, itemIndex int, itemLabel string) TransactionError {
+	return newTransactionError(400, message, common_constants.TransactionLimitExceeded, itemIndex, itemLabel)
+}
+
+func NewTransactionConditionFailedError(message string, itemIndex int, itemLabel string) TransactionError {
+	return newTransactionError(403, message, common_constants.ConditionalCheckFailed, itemIndex, itemLabel)
+}
+
+func NewTransactionStaleVersionError(message string, itemIndex int, itemLabel string) TransactionError {
+	return newTransactionError(412, message, common_constants.ConditionalCheckFailed, itemIndex, itemLabel)
+}
+
+func NewTransactionConflictError(message string, itemIndex int, itemLabel string) TransactionConflictError {
+	return &transactionConflictError{
+		transactionError: *newTransactionError(409, message, common_constants.TransactionConflict, itemIndex, itemLabel),
+	}
+}
+
+func NewTransactionThrottledError(message string, reason string, itemIndex int, itemLabel string) TransactionThrottledError {
+	return &transactionThrottledError{
+		transactionError: *newTransactionError(503, message, reason, itemIndex, itemLabel),
+	}
+}
+
+func NewIdempotentParameterMismatchError(message string) TransactionError {
+	return newTransactionError(422, message, common_constants.IdempotentParameterMismatch, -1, "")
+}
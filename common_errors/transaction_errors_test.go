@@ -0,0 +1,65 @@
+package common_errors_test
+
+import (
+	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewTransactionError(t *testing.T) {
+	actual := common_errors.NewTransactionError(403, "someErr", "ConditionalCheckFailed", 2, "someLabel")
+	assert.Equal(t, "someErr", actual.Error())
+	assert.Equal(t, 403, actual.HttpStatus())
+	assert.Equal(t, "ConditionalCheckFailed", actual.Reason())
+	assert.Equal(t, 2, actual.ItemIndex())
+	assert.Equal(t, "someLabel", actual.ItemLabel())
+}
+
+func TestNewIdempotentParameterMismatchError(t *testing.T) {
+	actual := common_errors.NewIdempotentParameterMismatchError("someErr")
+	assert.Equal(t, "someErr", actual.Error())
+	assert.Equal(t, 422, actual.HttpStatus())
+	assert.Equal(t, "IdempotentParameterMismatch", actual.Reason())
+	assert.Equal(t, -1, actual.ItemIndex())
+}
+
+func TestNewTransactionConflictError(t *testing.T) {
+	var actual common_errors.TransactionError = common_errors.NewTransactionConflictError("someErr", 1, "someLabel")
+	_, isConflict := actual.(common_errors.TransactionConflictError)
+	_, isThrottled := actual.(common_errors.TransactionThrottledError)
+	assert.True(t, isConflict)
+	assert.False(t, isThrottled)
+	assert.Equal(t, 409, actual.HttpStatus())
+	assert.Equal(t, "TransactionConflict", actual.Reason())
+	assert.Equal(t, 1, actual.ItemIndex())
+	assert.Equal(t, "someLabel", actual.ItemLabel())
+}
+
+func TestNewTransactionThrottledError(t *testing.T) {
+	var actual common_errors.TransactionError = common_errors.NewTransactionThrottledError("someErr", "ProvisionedThroughputExceeded", 1, "someLabel")
+	_, isConflict := actual.(common_errors.TransactionConflictError)
+	_, isThrottled := actual.(common_errors.TransactionThrottledError)
+	assert.False(t, isConflict)
+	assert.True(t, isThrottled)
+	assert.Equal(t, 503, actual.HttpStatus())
+	assert.Equal(t, "ProvisionedThroughputExceeded", actual.Reason())
+}
+
+func TestNewTransactionConditionFailedError(t *testing.T) {
+	actual := common_errors.NewTransactionConditionFailedError("someErr", 2, "someLabel")
+	assert.Equal(t, 403, actual.HttpStatus())
+	assert.Equal(t, "ConditionalCheckFailed", actual.Reason())
+}
+
+func TestNewTransactionStaleVersionError(t *testing.T) {
+	actual := common_errors.NewTransactionStaleVersionError("someErr", 2, "someLabel")
+	assert.Equal(t, 412, actual.HttpStatus())
+	assert.Equal(t, "ConditionalCheckFailed", actual.Reason())
+}
+
+func TestNewTransactionLimitExceededError(t *testing.T) {
+	actual := common_errors.NewTransactionLimitExceededError("someErr", 3, "someLabel")
+	assert.Equal(t, 400, actual.HttpStatus())
+	assert.Equal(t, "TransactionLimitExceeded", actual.Reason())
+	assert.Equal(t, 3, actual.ItemIndex())
+}
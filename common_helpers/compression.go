@@ -0,0 +1,130 @@
+package common_helpers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/Drathveloper/lambda_commons/v2/common_models"
+	"github.com/klauspost/compress/zstd"
+	"io"
+)
+
+// Other algorithms plug in as a CompressionCodec with their own header byte.
+const (
+	GzipCompressionHeader  byte = 0x01
+	ZstdCompressionHeader  byte = 0x02
+	ZstdDefaultCompression      = 3
+)
+
+var (
+	gzipMagicNumber = []byte{0x1f, 0x8b}
+	zstdMagicNumber = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// Codecs implementing it confirm that a payload carrying their header really is theirs, so legacy binary values that
+// happen to start with the header byte are returned unchanged instead of failing to decompress.
+type compressionPayloadMatcher interface {
+	Matches(payload []byte) bool
+}
+
+type gzipCodec struct {
+	level int
+}
+
+func NewGzipCodec(level int) common_models.CompressionCodec {
+	return &gzipCodec{
+		level: level,
+	}
+}
+
+func (codec *gzipCodec) Header() byte {
+	return GzipCompressionHeader
+}
+
+func (codec *gzipCodec) Matches(payload []byte) bool {
+	return bytes.HasPrefix(payload, gzipMagicNumber)
+}
+
+func (codec *gzipCodec) Compress(data []byte) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer, err := gzip.NewWriterLevel(&buffer, codec.level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func (codec *gzipCodec) Decompress(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+type zstdCodec struct {
+	level zstd.EncoderLevel
+}
+
+func NewZstdCodec(level int) common_models.CompressionCodec {
+	return &zstdCodec{
+		level: zstd.EncoderLevelFromZstd(level),
+	}
+}
+
+func (codec *zstdCodec) Header() byte {
+	return ZstdCompressionHeader
+}
+
+func (codec *zstdCodec) Matches(payload []byte) bool {
+	return bytes.HasPrefix(payload, zstdMagicNumber)
+}
+
+func (codec *zstdCodec) Compress(data []byte) ([]byte, error) {
+	encoder, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(codec.level), zstd.WithEncoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	defer encoder.Close()
+	return encoder.EncodeAll(data, nil), nil
+}
+
+func (codec *zstdCodec) Decompress(data []byte) ([]byte, error) {
+	decoder, err := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	defer decoder.Close()
+	return decoder.DecodeAll(data, nil)
+}
+
+func CompressWithHeader(codec common_models.CompressionCodec, data []byte) ([]byte, error) {
+	compressed, err := codec.Compress(data)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{codec.Header()}, compressed...), nil
+}
+
+func DecompressWithHeader(data []byte, codecs ...common_models.CompressionCodec) ([]byte, bool, error) {
+	if len(data) == 0 {
+		return data, false, nil
+	}
+	for _, codec := range codecs {
+		if codec == nil || codec.Header() != data[0] {
+			continue
+		}
+		if matcher, ok := codec.(compressionPayloadMatcher); ok && !matcher.Matches(data[1:]) {
+			continue
+		}
+		decompressed, err := codec.Decompress(data[1:])
+		return decompressed, true, err
+	}
+	return data, false, nil
+}
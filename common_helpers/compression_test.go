@@ -0,0 +1,78 @@
+package common_helpers_test
+
+import (
+	"compress/gzip"
+	"github.com/Drathveloper/lambda_commons/v2/common_helpers"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCompressWithHeader_ShouldRoundTripWithGzip(t *testing.T) {
+	codec := common_helpers.NewGzipCodec(gzip.BestCompression)
+	data := []byte(`{"field":"value","field2":"value","field3":"value"}`)
+
+	compressed, err := common_helpers.CompressWithHeader(codec, data)
+	decompressed, isCompressed, decompressErr := common_helpers.DecompressWithHeader(compressed, codec)
+
+	assert.NoError(t, err)
+	assert.Equal(t, common_helpers.GzipCompressionHeader, compressed[0])
+	assert.NoError(t, decompressErr)
+	assert.True(t, isCompressed)
+	assert.Equal(t, data, decompressed)
+}
+
+func TestCompressWithHeader_ShouldRoundTripWithZstd(t *testing.T) {
+	codec := common_helpers.NewZstdCodec(common_helpers.ZstdDefaultCompression)
+	data := []byte(`{"field":"value","field2":"value","field3":"value"}`)
+
+	compressed, err := common_helpers.CompressWithHeader(codec, data)
+	decompressed, isCompressed, decompressErr := common_helpers.DecompressWithHeader(compressed, common_helpers.NewGzipCodec(gzip.DefaultCompression), codec)
+
+	assert.NoError(t, err)
+	assert.Equal(t, common_helpers.ZstdCompressionHeader, compressed[0])
+	assert.NoError(t, decompressErr)
+	assert.True(t, isCompressed)
+	assert.Equal(t, data, decompressed)
+}
+
+func TestDecompressWithHeader_ShouldReturnLegacyDataUnchanged(t *testing.T) {
+	data := []byte(`{"field":"value"}`)
+
+	decompressed, isCompressed, err := common_helpers.DecompressWithHeader(data, common_helpers.NewGzipCodec(gzip.DefaultCompression))
+
+	assert.NoError(t, err)
+	assert.False(t, isCompressed)
+	assert.Equal(t, data, decompressed)
+}
+
+func TestDecompressWithHeader_ShouldReturnLegacyBinaryStartingWithHeaderUnchanged(t *testing.T) {
+	data := []byte{common_helpers.GzipCompressionHeader, 0x00, 0x02}
+
+	decompressed, isCompressed, err := common_helpers.DecompressWithHeader(data, common_helpers.NewGzipCodec(gzip.DefaultCompression))
+
+	assert.NoError(t, err)
+	assert.False(t, isCompressed)
+	assert.Equal(t, data, decompressed)
+}
+
+func TestDecompressWithHeader_ShouldReturnLegacyBinaryStartingWithZstdHeaderUnchanged(t *testing.T) {
+	data := []byte{common_helpers.ZstdCompressionHeader, 0x00, 0x02}
+
+	decompressed, isCompressed, err := common_helpers.DecompressWithHeader(data, common_helpers.NewZstdCodec(common_helpers.ZstdDefaultCompression))
+
+	assert.NoError(t, err)
+	assert.False(t, isCompressed)
+	assert.Equal(t, data, decompressed)
+}
+
+func TestDecompressWithHeader_ShouldReturnErrorWhenZstdPayloadIsCorrupted(t *testing.T) {
+	_, _, err := common_helpers.DecompressWithHeader([]byte{common_helpers.ZstdCompressionHeader, 0x28, 0xb5, 0x2f, 0xfd, 0x00}, common_helpers.NewZstdCodec(common_helpers.ZstdDefaultCompression))
+
+	assert.Error(t, err)
+}
+
+func TestDecompressWithHeader_ShouldReturnErrorWhenPayloadIsCorrupted(t *testing.T) {
+	_, _, err := common_helpers.DecompressWithHeader([]byte{common_helpers.GzipCompressionHeader, 0x1f, 0x8b, 0x00}, common_helpers.NewGzipCodec(gzip.DefaultCompression))
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,224 @@
+package common_helpers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+type jsonAttributeValue struct {
+	S    *string                        `json:"S,omitempty"`
+	N    *string                        `json:"N,omitempty"`
+	B    *[]byte                        `json:"B,omitempty"`
+	BOOL *bool                          `json:"BOOL,omitempty"`
+	NULL *bool                          `json:"NULL,omitempty"`
+	M    *map[string]jsonAttributeValue `json:"M,omitempty"`
+	L    *[]jsonAttributeValue          `json:"L,omitempty"`
+	SS   []string                       `json:"SS,omitempty"`
+	NS   []string                       `json:"NS,omitempty"`
+	BS   [][]byte                       `json:"BS,omitempty"`
+}
+
+func MarshalAttributeValueMapToJSON(item map[string]types.AttributeValue) ([]byte, error) {
+	jsonItem, err := toJSONAttributeValueMap(item)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jsonItem)
+}
+
+func UnmarshalAttributeValueMapFromJSON(data []byte) (map[string]types.AttributeValue, error) {
+	jsonItem := make(map[string]jsonAttributeValue, 0)
+	if err := json.Unmarshal(data, &jsonItem); err != nil {
+		return nil, err
+	}
+	return fromJSONAttributeValueMap(jsonItem)
+}
+
+func EncodeDynamodbContinuationToken(lastEvaluatedKey map[string]types.AttributeValue) (string, common_errors.GenericApplicationError) {
+	if len(lastEvaluatedKey) == 0 {
+		return "", nil
+	}
+	marshaledKey, err := MarshalAttributeValueMapToJSON(lastEvaluatedKey)
+	if err != nil {
+		return "", common_errors.NewInternalServerError("error while encoding continuation token")
+	}
+	return base64.RawURLEncoding.EncodeToString(marshaledKey), nil
+}
+
+func DecodeDynamodbContinuationToken(continuationToken string) (map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+	if continuationToken == "" {
+		return nil, nil
+	}
+	marshaledKey, err := base64.RawURLEncoding.DecodeString(continuationToken)
+	if err != nil {
+		return nil, common_errors.NewBadRequestError("invalid continuation token")
+	}
+	exclusiveStartKey, err := UnmarshalAttributeValueMapFromJSON(marshaledKey)
+	if err != nil || len(exclusiveStartKey) == 0 {
+		return nil, common_errors.NewBadRequestError("invalid continuation token")
+	}
+	return exclusiveStartKey, nil
+}
+
+func EstimateAttributeValueMapSize(item map[string]types.AttributeValue) int {
+	size := 0
+	for key, value := range item {
+		size += len(key) + EstimateAttributeValueSize(value)
+	}
+	return size
+}
+
+func EstimateAttributeValueSize(value types.AttributeValue) int {
+	switch typedValue := value.(type) {
+	case *types.AttributeValueMemberS:
+		return len(typedValue.Value)
+	case *types.AttributeValueMemberN:
+		return estimateNumberSize(typedValue.Value)
+	case *types.AttributeValueMemberB:
+		return len(typedValue.Value)
+	case *types.AttributeValueMemberBOOL, *types.AttributeValueMemberNULL:
+		return 1
+	case *types.AttributeValueMemberM:
+		size := 3
+		for key, element := range typedValue.Value {
+			size += 1 + len(key) + EstimateAttributeValueSize(element)
+		}
+		return size
+	case *types.AttributeValueMemberL:
+		size := 3
+		for _, element := range typedValue.Value {
+			size += 1 + EstimateAttributeValueSize(element)
+		}
+		return size
+	case *types.AttributeValueMemberSS:
+		size := 0
+		for _, element := range typedValue.Value {
+			size += len(element)
+		}
+		return size
+	case *types.AttributeValueMemberNS:
+		size := 0
+		for _, element := range typedValue.Value {
+			size += estimateNumberSize(element)
+		}
+		return size
+	case *types.AttributeValueMemberBS:
+		size := 0
+		for _, element := range typedValue.Value {
+			size += len(element)
+		}
+		return size
+	default:
+		return 0
+	}
+}
+
+func estimateNumberSize(number string) int {
+	return (len(number)+1)/2 + 1
+}
+
+func toJSONAttributeValueMap(item map[string]types.AttributeValue) (map[string]jsonAttributeValue, error) {
+	result := make(map[string]jsonAttributeValue, len(item))
+	for key, value := range item {
+		jsonValue, err := toJSONAttributeValue(value)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = jsonValue
+	}
+	return result, nil
+}
+
+func toJSONAttributeValue(value types.AttributeValue) (jsonAttributeValue, error) {
+	switch typedValue := value.(type) {
+	case *types.AttributeValueMemberS:
+		return jsonAttributeValue{S: &typedValue.Value}, nil
+	case *types.AttributeValueMemberN:
+		return jsonAttributeValue{N: &typedValue.Value}, nil
+	case *types.AttributeValueMemberB:
+		return jsonAttributeValue{B: &typedValue.Value}, nil
+	case *types.AttributeValueMemberBOOL:
+		return jsonAttributeValue{BOOL: &typedValue.Value}, nil
+	case *types.AttributeValueMemberNULL:
+		return jsonAttributeValue{NULL: &typedValue.Value}, nil
+	case *types.AttributeValueMemberM:
+		jsonMap, err := toJSONAttributeValueMap(typedValue.Value)
+		if err != nil {
+			return jsonAttributeValue{}, err
+		}
+		return jsonAttributeValue{M: &jsonMap}, nil
+	case *types.AttributeValueMemberL:
+		jsonList := make([]jsonAttributeValue, 0, len(typedValue.Value))
+		for _, element := range typedValue.Value {
+			jsonElement, err := toJSONAttributeValue(element)
+			if err != nil {
+				return jsonAttributeValue{}, err
+			}
+			jsonList = append(jsonList, jsonElement)
+		}
+		return jsonAttributeValue{L: &jsonList}, nil
+	case *types.AttributeValueMemberSS:
+		return jsonAttributeValue{SS: typedValue.Value}, nil
+	case *types.AttributeValueMemberNS:
+		return jsonAttributeValue{NS: typedValue.Value}, nil
+	case *types.AttributeValueMemberBS:
+		return jsonAttributeValue{BS: typedValue.Value}, nil
+	default:
+		return jsonAttributeValue{}, fmt.Errorf("unsupported attribute value type %T", value)
+	}
+}
+
+func fromJSONAttributeValueMap(item map[string]jsonAttributeValue) (map[string]types.AttributeValue, error) {
+	result := make(map[string]types.AttributeValue, len(item))
+	for key, jsonValue := range item {
+		value, err := fromJSONAttributeValue(jsonValue)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = value
+	}
+	return result, nil
+}
+
+func fromJSONAttributeValue(jsonValue jsonAttributeValue) (types.AttributeValue, error) {
+	switch {
+	case jsonValue.S != nil:
+		return &types.AttributeValueMemberS{Value: *jsonValue.S}, nil
+	case jsonValue.N != nil:
+		return &types.AttributeValueMemberN{Value: *jsonValue.N}, nil
+	case jsonValue.B != nil:
+		return &types.AttributeValueMemberB{Value: *jsonValue.B}, nil
+	case jsonValue.BOOL != nil:
+		return &types.AttributeValueMemberBOOL{Value: *jsonValue.BOOL}, nil
+	case jsonValue.NULL != nil:
+		return &types.AttributeValueMemberNULL{Value: *jsonValue.NULL}, nil
+	case jsonValue.M != nil:
+		value, err := fromJSONAttributeValueMap(*jsonValue.M)
+		if err != nil {
+			return nil, err
+		}
+		return &types.AttributeValueMemberM{Value: value}, nil
+	case jsonValue.L != nil:
+		list := make([]types.AttributeValue, 0, len(*jsonValue.L))
+		for _, jsonElement := range *jsonValue.L {
+			element, err := fromJSONAttributeValue(jsonElement)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, element)
+		}
+		return &types.AttributeValueMemberL{Value: list}, nil
+	case jsonValue.SS != nil:
+		return &types.AttributeValueMemberSS{Value: jsonValue.SS}, nil
+	case jsonValue.NS != nil:
+		return &types.AttributeValueMemberNS{Value: jsonValue.NS}, nil
+	case jsonValue.BS != nil:
+		return &types.AttributeValueMemberBS{Value: jsonValue.BS}, nil
+	default:
+		return nil, errors.New("attribute value without type")
+	}
+}
@@ -0,0 +1,93 @@
+package common_helpers_test
+
+import (
+	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/Drathveloper/lambda_commons/v2/common_helpers"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMarshalAttributeValueMapToJSON_ShouldRoundTrip(t *testing.T) {
+	item := map[string]types.AttributeValue{
+		"string": &types.AttributeValueMemberS{Value: "someValue"},
+		"number": &types.AttributeValueMemberN{Value: "12.5"},
+		"binary": &types.AttributeValueMemberB{Value: []byte("someBytes")},
+		"bool":   &types.AttributeValueMemberBOOL{Value: true},
+		"null":   &types.AttributeValueMemberNULL{Value: true},
+		"map": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			"nested": &types.AttributeValueMemberS{Value: "nestedValue"},
+		}},
+		"emptyMap": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{}},
+		"list": &types.AttributeValueMemberL{Value: []types.AttributeValue{
+			&types.AttributeValueMemberN{Value: "1"},
+			&types.AttributeValueMemberS{Value: "two"},
+		}},
+		"stringSet": &types.AttributeValueMemberSS{Value: []string{"a", "b"}},
+		"numberSet": &types.AttributeValueMemberNS{Value: []string{"1", "2"}},
+		"binarySet": &types.AttributeValueMemberBS{Value: [][]byte{[]byte("a")}},
+	}
+
+	marshaled, err := common_helpers.MarshalAttributeValueMapToJSON(item)
+	assert.NoError(t, err)
+	actual, err := common_helpers.UnmarshalAttributeValueMapFromJSON(marshaled)
+
+	assert.NoError(t, err)
+	assert.Equal(t, item, actual)
+}
+
+func TestUnmarshalAttributeValueMapFromJSON_ShouldReturnErrorWhenAttributeHasNoType(t *testing.T) {
+	_, err := common_helpers.UnmarshalAttributeValueMapFromJSON([]byte(`{"someKey":{}}`))
+
+	assert.Error(t, err)
+}
+
+func TestEncodeDynamodbContinuationToken_ShouldRoundTrip(t *testing.T) {
+	lastEvaluatedKey := map[string]types.AttributeValue{
+		"pk": &types.AttributeValueMemberS{Value: "somePartition"},
+		"sk": &types.AttributeValueMemberN{Value: "3"},
+	}
+
+	token, appErr := common_helpers.EncodeDynamodbContinuationToken(lastEvaluatedKey)
+	assert.Nil(t, appErr)
+	actual, appErr := common_helpers.DecodeDynamodbContinuationToken(token)
+
+	assert.Nil(t, appErr)
+	assert.Equal(t, lastEvaluatedKey, actual)
+}
+
+func TestEncodeDynamodbContinuationToken_ShouldReturnEmptyTokenWhenNoLastEvaluatedKey(t *testing.T) {
+	token, appErr := common_helpers.EncodeDynamodbContinuationToken(nil)
+
+	assert.Nil(t, appErr)
+	assert.Equal(t, "", token)
+}
+
+func TestDecodeDynamodbContinuationToken_ShouldReturnNilWhenEmptyToken(t *testing.T) {
+	actual, appErr := common_helpers.DecodeDynamodbContinuationToken("")
+
+	assert.Nil(t, appErr)
+	assert.Nil(t, actual)
+}
+
+func TestDecodeDynamodbContinuationToken_ShouldReturnBadRequestErrorWhenTokenIsInvalid(t *testing.T) {
+	expectedAppErr := common_errors.NewBadRequestError("invalid continuation token")
+
+	_, appErr := common_helpers.DecodeDynamodbContinuationToken("e30")
+
+	assert.Equal(t, expectedAppErr, appErr)
+}
+
+func TestEstimateAttributeValueMapSize_ShouldAddNamesAndValues(t *testing.T) {
+	item := map[string]types.AttributeValue{
+		"name":  &types.AttributeValueMemberS{Value: "abc"},
+		"count": &types.AttributeValueMemberN{Value: "12345"},
+		"flags": &types.AttributeValueMemberL{Value: []types.AttributeValue{
+			&types.AttributeValueMemberBOOL{Value: true},
+		}},
+	}
+
+	actual := common_helpers.EstimateAttributeValueMapSize(item)
+
+	assert.Equal(t, 26, actual)
+}
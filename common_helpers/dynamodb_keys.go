@@ -0,0 +1,249 @@
+package common_helpers
+
+import (
+	"fmt"
+	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/Drathveloper/lambda_commons/v2/common_models"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultDynamodbKeyDelimiter = "#"
+
+func LiteralKeySegment(literal string) common_models.DynamodbKeySegment {
+	return common_models.DynamodbKeySegment{Type: common_models.DynamodbKeySegmentLiteral, Name: literal}
+}
+
+func StringKeySegment(name string) common_models.DynamodbKeySegment {
+	return common_models.DynamodbKeySegment{Type: common_models.DynamodbKeySegmentString, Name: name}
+}
+
+func IntegerKeySegment(name string, width int) common_models.DynamodbKeySegment {
+	return common_models.DynamodbKeySegment{Type: common_models.DynamodbKeySegmentInteger, Name: name, Width: width}
+}
+
+func TimeKeySegment(name string, layout string) common_models.DynamodbKeySegment {
+	return common_models.DynamodbKeySegment{Type: common_models.DynamodbKeySegmentTime, Name: name, Layout: layout}
+}
+
+func NewDynamodbKeyFormat(segments ...common_models.DynamodbKeySegment) (common_models.DynamodbKeyFormat, common_errors.GenericApplicationError) {
+	return NewDynamodbKeyFormatWithDelimiter(defaultDynamodbKeyDelimiter, segments...)
+}
+
+func NewDynamodbKeyFormatWithDelimiter(delimiter string, segments ...common_models.DynamodbKeySegment) (common_models.DynamodbKeyFormat, common_errors.GenericApplicationError) {
+	if delimiter == "" {
+		return common_models.DynamodbKeyFormat{}, common_errors.NewInternalServerError("key format requires a delimiter")
+	}
+	if len(segments) == 0 {
+		return common_models.DynamodbKeyFormat{}, common_errors.NewInternalServerError("key format requires at least one segment")
+	}
+	segmentNames := make(map[string]bool, len(segments))
+	for _, segment := range segments {
+		if segment.Name == "" {
+			return common_models.DynamodbKeyFormat{}, common_errors.NewInternalServerError("key segments require a name")
+		}
+		switch segment.Type {
+		case common_models.DynamodbKeySegmentLiteral:
+			if strings.Contains(segment.Name, delimiter) {
+				return common_models.DynamodbKeyFormat{}, common_errors.NewInternalServerError(fmt.Sprintf("literal key segment %s contains the delimiter", segment.Name))
+			}
+			continue
+		case common_models.DynamodbKeySegmentString, common_models.DynamodbKeySegmentInteger:
+		case common_models.DynamodbKeySegmentTime:
+			if strings.Contains(dynamodbKeyTimeLayout(segment), delimiter) {
+				return common_models.DynamodbKeyFormat{}, common_errors.NewInternalServerError(fmt.Sprintf("time key segment %s layout contains the delimiter", segment.Name))
+			}
+		default:
+			return common_models.DynamodbKeyFormat{}, common_errors.NewInternalServerError(fmt.Sprintf("unknown key segment type %s", segment.Type))
+		}
+		if segmentNames[segment.Name] {
+			return common_models.DynamodbKeyFormat{}, common_errors.NewInternalServerError(fmt.Sprintf("key segment %s is declared more than once", segment.Name))
+		}
+		segmentNames[segment.Name] = true
+	}
+	return common_models.DynamodbKeyFormat{Delimiter: delimiter, Segments: segments}, nil
+}
+
+func ComposeDynamodbKey(format common_models.DynamodbKeyFormat, values common_models.DynamodbKeyValues) (string, common_errors.GenericApplicationError) {
+	parts, isComplete, appErr := composeDynamodbKeyParts(format, values)
+	if appErr != nil {
+		return "", appErr
+	}
+	if !isComplete {
+		return "", common_errors.NewBadRequestError(fmt.Sprintf("key segment %s is missing", format.Segments[len(parts)].Name))
+	}
+	return strings.Join(parts, format.Delimiter), nil
+}
+
+// The prefix always ends with the delimiter after the last complete segment, so a value like 12 never matches keys
+// continuing with 123.
+func ComposeDynamodbKeyPrefix(format common_models.DynamodbKeyFormat, values common_models.DynamodbKeyValues) (string, common_errors.GenericApplicationError) {
+	parts, isComplete, appErr := composeDynamodbKeyParts(format, values)
+	if appErr != nil {
+		return "", appErr
+	}
+	if !isComplete {
+		missingSegment := format.Segments[len(parts)]
+		for _, segment := range format.Segments[len(parts)+1:] {
+			if value, exists := values[segment.Name]; exists && value != nil {
+				return "", common_errors.NewBadRequestError(fmt.Sprintf("key segment %s is missing but %s is set", missingSegment.Name, segment.Name))
+			}
+		}
+	}
+	return strings.Join(parts, format.Delimiter) + format.Delimiter, nil
+}
+
+func ParseDynamodbKey(format common_models.DynamodbKeyFormat, key string) (common_models.DynamodbKeyValues, common_errors.GenericApplicationError) {
+	parts := strings.Split(key, format.Delimiter)
+	if len(parts) != len(format.Segments) {
+		return nil, common_errors.NewInternalServerError(fmt.Sprintf("key %s does not match the key format", key))
+	}
+	values := make(common_models.DynamodbKeyValues, len(parts))
+	for index, segment := range format.Segments {
+		value, err := parseDynamodbKeySegment(segment, parts[index])
+		if err != nil {
+			return nil, common_errors.NewInternalServerError(fmt.Sprintf("key %s does not match the key format", key))
+		}
+		if segment.Type != common_models.DynamodbKeySegmentLiteral {
+			values[segment.Name] = value
+		}
+	}
+	return values, nil
+}
+
+func ComposeDynamodbPrimaryKey(keyName string, format common_models.DynamodbKeyFormat, values common_models.DynamodbKeyValues) (common_models.DynamodbSimplePrimaryKey, common_errors.GenericApplicationError) {
+	key, appErr := ComposeDynamodbKey(format, values)
+	if appErr != nil {
+		return common_models.DynamodbSimplePrimaryKey{}, appErr
+	}
+	return common_models.DynamodbSimplePrimaryKey{KeyName: keyName, Value: key}, nil
+}
+
+func ComposeDynamodbComplexPrimaryKey(partitionKeyName string, partitionKeyFormat common_models.DynamodbKeyFormat, sortKeyName string, sortKeyFormat common_models.DynamodbKeyFormat, values common_models.DynamodbKeyValues) (common_models.DynamodbComplexPrimaryKey, common_errors.GenericApplicationError) {
+	partitionKey, appErr := ComposeDynamodbPrimaryKey(partitionKeyName, partitionKeyFormat, values)
+	if appErr != nil {
+		return common_models.DynamodbComplexPrimaryKey{}, appErr
+	}
+	sortKey, appErr := ComposeDynamodbPrimaryKey(sortKeyName, sortKeyFormat, values)
+	if appErr != nil {
+		return common_models.DynamodbComplexPrimaryKey{}, appErr
+	}
+	return common_models.DynamodbComplexPrimaryKey{PartitionKey: partitionKey, SortKey: sortKey}, nil
+}
+
+func ComposeDynamodbBeginsWithCondition(keyName string, format common_models.DynamodbKeyFormat, values common_models.DynamodbKeyValues) (common_models.DynamodbSortKeyCondition, common_errors.GenericApplicationError) {
+	prefix, appErr := ComposeDynamodbKeyPrefix(format, values)
+	if appErr != nil {
+		return common_models.DynamodbSortKeyCondition{}, appErr
+	}
+	return common_models.DynamodbSortKeyCondition{
+		KeyName:  keyName,
+		Operator: common_models.DynamodbSortKeyBeginsWith,
+		Values:   []interface{}{prefix},
+	}, nil
+}
+
+func composeDynamodbKeyParts(format common_models.DynamodbKeyFormat, values common_models.DynamodbKeyValues) ([]string, bool, common_errors.GenericApplicationError) {
+	parts := make([]string, 0, len(format.Segments))
+	for _, segment := range format.Segments {
+		if segment.Type == common_models.DynamodbKeySegmentLiteral {
+			parts = append(parts, segment.Name)
+			continue
+		}
+		value, exists := values[segment.Name]
+		if !exists || value == nil {
+			return parts, false, nil
+		}
+		part, appErr := formatDynamodbKeySegment(segment, value)
+		if appErr != nil {
+			return nil, false, appErr
+		}
+		if strings.Contains(part, format.Delimiter) {
+			return nil, false, common_errors.NewBadRequestError(fmt.Sprintf("key segment %s contains the delimiter", segment.Name))
+		}
+		parts = append(parts, part)
+	}
+	return parts, true, nil
+}
+
+func formatDynamodbKeySegment(segment common_models.DynamodbKeySegment, value interface{}) (string, common_errors.GenericApplicationError) {
+	switch segment.Type {
+	case common_models.DynamodbKeySegmentString:
+		stringValue, isString := value.(string)
+		if !isString || stringValue == "" {
+			return "", common_errors.NewBadRequestError(fmt.Sprintf("key segment %s must be a non empty string", segment.Name))
+		}
+		return stringValue, nil
+	case common_models.DynamodbKeySegmentInteger:
+		integerValue, isInteger := toDynamodbKeyInteger(value)
+		if !isInteger || integerValue < 0 {
+			return "", common_errors.NewBadRequestError(fmt.Sprintf("key segment %s must be a non negative integer", segment.Name))
+		}
+		formattedValue := strconv.FormatInt(integerValue, 10)
+		if segment.Width > 0 && len(formattedValue) > segment.Width {
+			return "", common_errors.NewBadRequestError(fmt.Sprintf("key segment %s exceeds %d digits", segment.Name, segment.Width))
+		}
+		return fmt.Sprintf("%0*d", segment.Width, integerValue), nil
+	default:
+		timeValue, isTime := value.(time.Time)
+		if !isTime {
+			return "", common_errors.NewBadRequestError(fmt.Sprintf("key segment %s must be a time", segment.Name))
+		}
+		return timeValue.UTC().Format(dynamodbKeyTimeLayout(segment)), nil
+	}
+}
+
+func parseDynamodbKeySegment(segment common_models.DynamodbKeySegment, part string) (interface{}, error) {
+	switch segment.Type {
+	case common_models.DynamodbKeySegmentLiteral:
+		if part != segment.Name {
+			return nil, fmt.Errorf("unexpected literal %s", part)
+		}
+		return part, nil
+	case common_models.DynamodbKeySegmentString:
+		if part == "" {
+			return nil, fmt.Errorf("empty segment %s", segment.Name)
+		}
+		return part, nil
+	case common_models.DynamodbKeySegmentInteger:
+		return strconv.ParseInt(part, 10, 64)
+	default:
+		return time.Parse(dynamodbKeyTimeLayout(segment), part)
+	}
+}
+
+func toDynamodbKeyInteger(value interface{}) (int64, bool) {
+	switch typedValue := value.(type) {
+	case int:
+		return int64(typedValue), true
+	case int8:
+		return int64(typedValue), true
+	case int16:
+		return int64(typedValue), true
+	case int32:
+		return int64(typedValue), true
+	case int64:
+		return typedValue, true
+	case uint:
+		return int64(typedValue), uint64(typedValue) <= math.MaxInt64
+	case uint8:
+		return int64(typedValue), true
+	case uint16:
+		return int64(typedValue), true
+	case uint32:
+		return int64(typedValue), true
+	case uint64:
+		return int64(typedValue), typedValue <= math.MaxInt64
+	default:
+		return 0, false
+	}
+}
+
+func dynamodbKeyTimeLayout(segment common_models.DynamodbKeySegment) string {
+	if segment.Layout == "" {
+		return time.RFC3339
+	}
+	return segment.Layout
+}
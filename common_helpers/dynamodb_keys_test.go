@@ -0,0 +1,208 @@
+package common_helpers_test
+
+import (
+	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/Drathveloper/lambda_commons/v2/common_helpers"
+	"github.com/Drathveloper/lambda_commons/v2/common_models"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+	"time"
+)
+
+func newOrderKeyFormat(t *testing.T) common_models.DynamodbKeyFormat {
+	format, appErr := common_helpers.NewDynamodbKeyFormat(
+		common_helpers.LiteralKeySegment("ORDER"),
+		common_helpers.TimeKeySegment("date", "2006-01-02"),
+		common_helpers.IntegerKeySegment("sequence", 4),
+		common_helpers.StringKeySegment("id"),
+	)
+	assert.NoError(t, appErr)
+	return format
+}
+
+func TestComposeDynamodbKey_ShouldComposeTypedSegments(t *testing.T) {
+	format := newOrderKeyFormat(t)
+	values := common_models.DynamodbKeyValues{
+		"date":     time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+		"sequence": 7,
+		"id":       "abc",
+	}
+
+	key, appErr := common_helpers.ComposeDynamodbKey(format, values)
+
+	assert.NoError(t, appErr)
+	assert.Equal(t, "ORDER#2024-01-01#0007#abc", key)
+}
+
+func TestComposeDynamodbKey_ShouldReturnBadRequestErrorWhenSegmentIsMissing(t *testing.T) {
+	format := newOrderKeyFormat(t)
+	values := common_models.DynamodbKeyValues{
+		"date": time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	expectedAppErr := common_errors.NewBadRequestError("key segment sequence is missing")
+
+	_, appErr := common_helpers.ComposeDynamodbKey(format, values)
+
+	assert.Equal(t, expectedAppErr, appErr)
+}
+
+func TestComposeDynamodbKey_ShouldReturnBadRequestErrorWhenValueContainsDelimiter(t *testing.T) {
+	format := newOrderKeyFormat(t)
+	values := common_models.DynamodbKeyValues{
+		"date":     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		"sequence": 1,
+		"id":       "a#b",
+	}
+	expectedAppErr := common_errors.NewBadRequestError("key segment id contains the delimiter")
+
+	_, appErr := common_helpers.ComposeDynamodbKey(format, values)
+
+	assert.Equal(t, expectedAppErr, appErr)
+}
+
+func TestComposeDynamodbKey_ShouldReturnBadRequestErrorWhenIntegerExceedsWidth(t *testing.T) {
+	format := newOrderKeyFormat(t)
+	values := common_models.DynamodbKeyValues{
+		"date":     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		"sequence": 12345,
+		"id":       "abc",
+	}
+	expectedAppErr := common_errors.NewBadRequestError("key segment sequence exceeds 4 digits")
+
+	_, appErr := common_helpers.ComposeDynamodbKey(format, values)
+
+	assert.Equal(t, expectedAppErr, appErr)
+}
+
+func TestComposeDynamodbKeyPrefix_ShouldStopAtFirstMissingSegment(t *testing.T) {
+	format := newOrderKeyFormat(t)
+	values := common_models.DynamodbKeyValues{
+		"date": time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	prefix, appErr := common_helpers.ComposeDynamodbKeyPrefix(format, values)
+
+	assert.NoError(t, appErr)
+	assert.Equal(t, "ORDER#2024-01-01#", prefix)
+}
+
+func TestComposeDynamodbKeyPrefix_ShouldEndWithDelimiterWhenAllSegmentsAreSet(t *testing.T) {
+	format := newOrderKeyFormat(t)
+	values := common_models.DynamodbKeyValues{
+		"date":     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		"sequence": 7,
+		"id":       "abc",
+	}
+
+	prefix, appErr := common_helpers.ComposeDynamodbKeyPrefix(format, values)
+
+	assert.NoError(t, appErr)
+	assert.Equal(t, "ORDER#2024-01-01#0007#abc#", prefix)
+}
+
+func TestComposeDynamodbKeyPrefix_ShouldReturnBadRequestErrorWhenSegmentAfterMissingOneIsSet(t *testing.T) {
+	format := newOrderKeyFormat(t)
+	values := common_models.DynamodbKeyValues{
+		"date": time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		"id":   "abc",
+	}
+	expectedAppErr := common_errors.NewBadRequestError("key segment sequence is missing but id is set")
+
+	_, appErr := common_helpers.ComposeDynamodbKeyPrefix(format, values)
+
+	assert.Equal(t, expectedAppErr, appErr)
+}
+
+func TestComposeDynamodbKey_ShouldAcceptAllIntegerKinds(t *testing.T) {
+	format, _ := common_helpers.NewDynamodbKeyFormat(common_helpers.LiteralKeySegment("USER"), common_helpers.IntegerKeySegment("userId", 0))
+
+	for _, userID := range []interface{}{int8(7), int16(7), int32(7), int64(7), uint(7), uint8(7), uint16(7), uint32(7), uint64(7)} {
+		key, appErr := common_helpers.ComposeDynamodbKey(format, common_models.DynamodbKeyValues{"userId": userID})
+
+		assert.NoError(t, appErr)
+		assert.Equal(t, "USER#7", key)
+	}
+}
+
+func TestComposeDynamodbKey_ShouldReturnBadRequestErrorWhenUnsignedIntegerOverflows(t *testing.T) {
+	format, _ := common_helpers.NewDynamodbKeyFormat(common_helpers.LiteralKeySegment("USER"), common_helpers.IntegerKeySegment("userId", 0))
+	expectedAppErr := common_errors.NewBadRequestError("key segment userId must be a non negative integer")
+
+	_, appErr := common_helpers.ComposeDynamodbKey(format, common_models.DynamodbKeyValues{"userId": uint64(math.MaxUint64)})
+
+	assert.Equal(t, expectedAppErr, appErr)
+}
+
+func TestComposeDynamodbComplexPrimaryKey_ShouldComposeBothKeys(t *testing.T) {
+	userFormat, _ := common_helpers.NewDynamodbKeyFormat(common_helpers.LiteralKeySegment("USER"), common_helpers.IntegerKeySegment("userId", 0))
+	format := newOrderKeyFormat(t)
+	values := common_models.DynamodbKeyValues{
+		"userId":   123,
+		"date":     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		"sequence": 7,
+		"id":       "abc",
+	}
+	expectedKey := common_models.DynamodbComplexPrimaryKey{
+		PartitionKey: common_models.DynamodbSimplePrimaryKey{KeyName: "pk", Value: "USER#123"},
+		SortKey:      common_models.DynamodbSimplePrimaryKey{KeyName: "sk", Value: "ORDER#2024-01-01#0007#abc"},
+	}
+
+	key, appErr := common_helpers.ComposeDynamodbComplexPrimaryKey("pk", userFormat, "sk", format, values)
+
+	assert.NoError(t, appErr)
+	assert.Equal(t, expectedKey, key)
+}
+
+func TestParseDynamodbKey_ShouldReturnTypedValues(t *testing.T) {
+	format := newOrderKeyFormat(t)
+	expectedValues := common_models.DynamodbKeyValues{
+		"date":     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		"sequence": int64(7),
+		"id":       "abc",
+	}
+
+	values, appErr := common_helpers.ParseDynamodbKey(format, "ORDER#2024-01-01#0007#abc")
+
+	assert.NoError(t, appErr)
+	assert.Equal(t, expectedValues, values)
+}
+
+func TestParseDynamodbKey_ShouldReturnInternalServerErrorWhenLiteralDoesNotMatch(t *testing.T) {
+	format := newOrderKeyFormat(t)
+	expectedAppErr := common_errors.NewInternalServerError("key USER#2024-01-01#0007#abc does not match the key format")
+
+	_, appErr := common_helpers.ParseDynamodbKey(format, "USER#2024-01-01#0007#abc")
+
+	assert.Equal(t, expectedAppErr, appErr)
+}
+
+func TestNewDynamodbKeyFormat_ShouldReturnInternalServerErrorWhenSegmentIsDuplicated(t *testing.T) {
+	expectedAppErr := common_errors.NewInternalServerError("key segment id is declared more than once")
+
+	_, appErr := common_helpers.NewDynamodbKeyFormat(common_helpers.StringKeySegment("id"), common_helpers.StringKeySegment("id"))
+
+	assert.Equal(t, expectedAppErr, appErr)
+}
+
+func TestNewDynamodbKeyFormatWithDelimiter_ShouldReturnInternalServerErrorWhenDefaultTimeLayoutContainsDelimiter(t *testing.T) {
+	expectedAppErr := common_errors.NewInternalServerError("time key segment date layout contains the delimiter")
+
+	_, appErr := common_helpers.NewDynamodbKeyFormatWithDelimiter(":", common_helpers.TimeKeySegment("date", ""))
+
+	assert.Equal(t, expectedAppErr, appErr)
+}
+
+func TestComposeDynamodbBeginsWithCondition_ShouldBuildSortKeyCondition(t *testing.T) {
+	format := newOrderKeyFormat(t)
+	expectedCondition := common_models.DynamodbSortKeyCondition{
+		KeyName:  "sk",
+		Operator: common_models.DynamodbSortKeyBeginsWith,
+		Values:   []interface{}{"ORDER#"},
+	}
+
+	condition, appErr := common_helpers.ComposeDynamodbBeginsWithCondition("sk", format, common_models.DynamodbKeyValues{})
+
+	assert.NoError(t, appErr)
+	assert.Equal(t, expectedCondition, condition)
+}
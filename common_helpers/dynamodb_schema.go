@@ -0,0 +1,122 @@
+package common_helpers
+
+import (
+	"fmt"
+	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/Drathveloper/lambda_commons/v2/common_models"
+	"reflect"
+	"strings"
+)
+
+const (
+	dynamodbSchemaTag          = "dynamo"
+	dynamodbAttributeTag       = "dynamodbav"
+	dynamodbPartitionKeySuffix = "pk"
+	dynamodbSortKeySuffix      = "sk"
+	dynamodbEncryptedRole      = "encrypted"
+)
+
+func ParseDynamodbTableSchema(item interface{}) (common_models.DynamodbTableSchema, common_errors.GenericApplicationError) {
+	itemType := reflect.TypeOf(item)
+	for itemType != nil && itemType.Kind() == reflect.Ptr {
+		itemType = itemType.Elem()
+	}
+	if itemType == nil || itemType.Kind() != reflect.Struct {
+		return common_models.DynamodbTableSchema{}, common_errors.NewInternalServerError("table schema can only be parsed from a struct")
+	}
+	schema := common_models.DynamodbTableSchema{
+		Indexes: make(map[string]common_models.DynamodbIndexSchema, 0),
+	}
+	if appErr := parseDynamodbSchemaFields(itemType, &schema); appErr != nil {
+		return common_models.DynamodbTableSchema{}, appErr
+	}
+	if schema.PartitionKey == "" {
+		return common_models.DynamodbTableSchema{}, common_errors.NewInternalServerError("table schema requires a partition key")
+	}
+	for indexName, index := range schema.Indexes {
+		if index.PartitionKey == "" {
+			return common_models.DynamodbTableSchema{}, common_errors.NewInternalServerError(fmt.Sprintf("index %s requires a partition key", indexName))
+		}
+	}
+	return schema, nil
+}
+
+func parseDynamodbSchemaFields(itemType reflect.Type, schema *common_models.DynamodbTableSchema) common_errors.GenericApplicationError {
+	for fieldIndex := 0; fieldIndex < itemType.NumField(); fieldIndex++ {
+		field := itemType.Field(fieldIndex)
+		attributeName, isIgnored := dynamodbAttributeName(field)
+		if isIgnored {
+			continue
+		}
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && attributeName == "" && fieldType.Kind() == reflect.Struct {
+			if appErr := parseDynamodbSchemaFields(fieldType, schema); appErr != nil {
+				return appErr
+			}
+			continue
+		}
+		if attributeName == "" {
+			attributeName = field.Name
+		}
+		schemaTag, exists := field.Tag.Lookup(dynamodbSchemaTag)
+		if !exists {
+			continue
+		}
+		for _, role := range strings.Split(schemaTag, ",") {
+			if appErr := applyDynamodbSchemaRole(schema, strings.TrimSpace(role), attributeName); appErr != nil {
+				return appErr
+			}
+		}
+	}
+	return nil
+}
+
+func applyDynamodbSchemaRole(schema *common_models.DynamodbTableSchema, role string, attributeName string) common_errors.GenericApplicationError {
+	switch {
+	case role == dynamodbEncryptedRole:
+		schema.EncryptedAttributes = append(schema.EncryptedAttributes, attributeName)
+	case role == dynamodbPartitionKeySuffix:
+		return assignDynamodbSchemaKey(&schema.PartitionKey, attributeName, "table partition key")
+	case role == dynamodbSortKeySuffix:
+		return assignDynamodbSchemaKey(&schema.SortKey, attributeName, "table sort key")
+	case strings.HasSuffix(role, dynamodbPartitionKeySuffix):
+		indexName := strings.TrimSuffix(role, dynamodbPartitionKeySuffix)
+		index := schema.Indexes[indexName]
+		if appErr := assignDynamodbSchemaKey(&index.PartitionKey, attributeName, fmt.Sprintf("index %s partition key", indexName)); appErr != nil {
+			return appErr
+		}
+		schema.Indexes[indexName] = index
+	case strings.HasSuffix(role, dynamodbSortKeySuffix):
+		indexName := strings.TrimSuffix(role, dynamodbSortKeySuffix)
+		index := schema.Indexes[indexName]
+		if appErr := assignDynamodbSchemaKey(&index.SortKey, attributeName, fmt.Sprintf("index %s sort key", indexName)); appErr != nil {
+			return appErr
+		}
+		schema.Indexes[indexName] = index
+	default:
+		return common_errors.NewInternalServerError(fmt.Sprintf("unknown table schema role %s on attribute %s", role, attributeName))
+	}
+	return nil
+}
+
+func assignDynamodbSchemaKey(target *string, attributeName string, description string) common_errors.GenericApplicationError {
+	if *target != "" {
+		return common_errors.NewInternalServerError(fmt.Sprintf("%s is declared more than once", description))
+	}
+	*target = attributeName
+	return nil
+}
+
+func dynamodbAttributeName(field reflect.StructField) (string, bool) {
+	if field.PkgPath != "" && !field.Anonymous {
+		return "", true
+	}
+	attributeTag := field.Tag.Get(dynamodbAttributeTag)
+	if attributeTag == "-" {
+		return "", true
+	}
+	return strings.Split(attributeTag, ",")[0], false
+}
@@ -0,0 +1,111 @@
+package common_helpers_test
+
+import (
+	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/Drathveloper/lambda_commons/v2/common_helpers"
+	"github.com/Drathveloper/lambda_commons/v2/common_models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type schemaBase struct {
+	Tenant string `dynamodbav:"tenant" dynamo:"gsi1pk"`
+}
+
+type schemaItem struct {
+	schemaBase
+	ID       string `dynamodbav:"id" dynamo:"pk"`
+	Created  string `dynamodbav:"created,omitempty" dynamo:"sk,gsi1sk"`
+	Status   string `dynamo:"gsi2pk"`
+	Ignored  string `dynamodbav:"-" dynamo:"gsi3pk"`
+	internal string `dynamo:"gsi4pk"`
+}
+
+func TestParseDynamodbTableSchema_ShouldParseTableAndIndexKeys(t *testing.T) {
+	expectedSchema := common_models.DynamodbTableSchema{
+		PartitionKey: "id",
+		SortKey:      "created",
+		Indexes: map[string]common_models.DynamodbIndexSchema{
+			"gsi1": {PartitionKey: "tenant", SortKey: "created"},
+			"gsi2": {PartitionKey: "Status"},
+		},
+	}
+
+	schema, appErr := common_helpers.ParseDynamodbTableSchema(&schemaItem{})
+
+	assert.NoError(t, appErr)
+	assert.Equal(t, expectedSchema, schema)
+}
+
+func TestParseDynamodbTableSchema_ShouldFollowEmbeddedPointerStructs(t *testing.T) {
+	type pointerSchemaItem struct {
+		*schemaBase
+		ID string `dynamodbav:"id" dynamo:"pk"`
+	}
+	expectedSchema := common_models.DynamodbTableSchema{
+		PartitionKey: "id",
+		Indexes: map[string]common_models.DynamodbIndexSchema{
+			"gsi1": {PartitionKey: "tenant"},
+		},
+	}
+
+	schema, appErr := common_helpers.ParseDynamodbTableSchema(pointerSchemaItem{})
+
+	assert.NoError(t, appErr)
+	assert.Equal(t, expectedSchema, schema)
+}
+
+func TestParseDynamodbTableSchema_ShouldReturnErrorWhenPartitionKeyIsMissing(t *testing.T) {
+	type item struct {
+		Created string `dynamo:"sk"`
+	}
+	expectedAppErr := common_errors.NewInternalServerError("table schema requires a partition key")
+
+	_, appErr := common_helpers.ParseDynamodbTableSchema(item{})
+
+	assert.Equal(t, expectedAppErr, appErr)
+}
+
+func TestParseDynamodbTableSchema_ShouldReturnErrorWhenKeyIsDeclaredTwice(t *testing.T) {
+	type item struct {
+		ID    string `dynamo:"pk"`
+		Other string `dynamo:"pk"`
+	}
+	expectedAppErr := common_errors.NewInternalServerError("table partition key is declared more than once")
+
+	_, appErr := common_helpers.ParseDynamodbTableSchema(item{})
+
+	assert.Equal(t, expectedAppErr, appErr)
+}
+
+func TestParseDynamodbTableSchema_ShouldReturnErrorWhenIndexHasNoPartitionKey(t *testing.T) {
+	type item struct {
+		ID      string `dynamo:"pk"`
+		Created string `dynamo:"gsi1sk"`
+	}
+	expectedAppErr := common_errors.NewInternalServerError("index gsi1 requires a partition key")
+
+	_, appErr := common_helpers.ParseDynamodbTableSchema(item{})
+
+	assert.Equal(t, expectedAppErr, appErr)
+}
+
+func TestParseDynamodbTableSchema_ShouldReturnErrorWhenNotStruct(t *testing.T) {
+	expectedAppErr := common_errors.NewInternalServerError("table schema can only be parsed from a struct")
+
+	_, appErr := common_helpers.ParseDynamodbTableSchema("someValue")
+
+	assert.Equal(t, expectedAppErr, appErr)
+}
+
+func TestParseDynamodbTableSchema_ShouldCollectEncryptedAttributes(t *testing.T) {
+	type item struct {
+		ID    string `dynamodbav:"id" dynamo:"pk"`
+		Email string `dynamodbav:"email" dynamo:"encrypted"`
+	}
+
+	schema, appErr := common_helpers.ParseDynamodbTableSchema(item{})
+
+	assert.NoError(t, appErr)
+	assert.Equal(t, []string{"email"}, schema.EncryptedAttributes)
+}
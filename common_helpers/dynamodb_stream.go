@@ -0,0 +1,61 @@
+package common_helpers
+
+import (
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func ConvertDynamodbStreamImage(image map[string]events.DynamoDBAttributeValue) (map[string]types.AttributeValue, error) {
+	if image == nil {
+		return nil, nil
+	}
+	result := make(map[string]types.AttributeValue, len(image))
+	for key, value := range image {
+		attributeValue, err := ConvertDynamodbStreamAttributeValue(value)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = attributeValue
+	}
+	return result, nil
+}
+
+func ConvertDynamodbStreamAttributeValue(value events.DynamoDBAttributeValue) (types.AttributeValue, error) {
+	switch value.DataType() {
+	case events.DataTypeString:
+		return &types.AttributeValueMemberS{Value: value.String()}, nil
+	case events.DataTypeNumber:
+		return &types.AttributeValueMemberN{Value: value.Number()}, nil
+	case events.DataTypeBinary:
+		return &types.AttributeValueMemberB{Value: value.Binary()}, nil
+	case events.DataTypeBoolean:
+		return &types.AttributeValueMemberBOOL{Value: value.Boolean()}, nil
+	case events.DataTypeNull:
+		return &types.AttributeValueMemberNULL{Value: true}, nil
+	case events.DataTypeMap:
+		attributes, err := ConvertDynamodbStreamImage(value.Map())
+		if err != nil {
+			return nil, err
+		}
+		return &types.AttributeValueMemberM{Value: attributes}, nil
+	case events.DataTypeList:
+		list := make([]types.AttributeValue, 0, len(value.List()))
+		for _, element := range value.List() {
+			attributeValue, err := ConvertDynamodbStreamAttributeValue(element)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, attributeValue)
+		}
+		return &types.AttributeValueMemberL{Value: list}, nil
+	case events.DataTypeStringSet:
+		return &types.AttributeValueMemberSS{Value: value.StringSet()}, nil
+	case events.DataTypeNumberSet:
+		return &types.AttributeValueMemberNS{Value: value.NumberSet()}, nil
+	case events.DataTypeBinarySet:
+		return &types.AttributeValueMemberBS{Value: value.BinarySet()}, nil
+	default:
+		return nil, fmt.Errorf("unsupported stream attribute value type %d", value.DataType())
+	}
+}
@@ -0,0 +1,41 @@
+package common_helpers_test
+
+import (
+	"github.com/Drathveloper/lambda_commons/v2/common_helpers"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestConvertDynamodbStreamImage_ShouldConvertAllAttributeTypes(t *testing.T) {
+	image := map[string]events.DynamoDBAttributeValue{
+		"string":    events.NewStringAttribute("value"),
+		"number":    events.NewNumberAttribute("12.5"),
+		"binary":    events.NewBinaryAttribute([]byte{0x01}),
+		"boolean":   events.NewBooleanAttribute(true),
+		"null":      events.NewNullAttribute(),
+		"stringSet": events.NewStringSetAttribute([]string{"a"}),
+		"numberSet": events.NewNumberSetAttribute([]string{"1"}),
+		"binarySet": events.NewBinarySetAttribute([][]byte{{0x02}}),
+		"list":      events.NewListAttribute([]events.DynamoDBAttributeValue{events.NewStringAttribute("element")}),
+		"map":       events.NewMapAttribute(map[string]events.DynamoDBAttributeValue{"nested": events.NewNumberAttribute("1")}),
+	}
+	expected := map[string]types.AttributeValue{
+		"string":    &types.AttributeValueMemberS{Value: "value"},
+		"number":    &types.AttributeValueMemberN{Value: "12.5"},
+		"binary":    &types.AttributeValueMemberB{Value: []byte{0x01}},
+		"boolean":   &types.AttributeValueMemberBOOL{Value: true},
+		"null":      &types.AttributeValueMemberNULL{Value: true},
+		"stringSet": &types.AttributeValueMemberSS{Value: []string{"a"}},
+		"numberSet": &types.AttributeValueMemberNS{Value: []string{"1"}},
+		"binarySet": &types.AttributeValueMemberBS{Value: [][]byte{{0x02}}},
+		"list":      &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: "element"}}},
+		"map":       &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{"nested": &types.AttributeValueMemberN{Value: "1"}}},
+	}
+
+	result, err := common_helpers.ConvertDynamodbStreamImage(image)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
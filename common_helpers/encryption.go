@@ -0,0 +1,71 @@
+package common_helpers
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"github.com/Drathveloper/lambda_commons/v2/common_models"
+	"io"
+)
+
+const dataKeySize = 32
+
+type staticDataKeyProvider struct {
+	masterKey []byte
+}
+
+func NewStaticDataKeyProvider(masterKey []byte) common_models.DataKeyProviderAPI {
+	return &staticDataKeyProvider{
+		masterKey: masterKey,
+	}
+}
+
+func (provider *staticDataKeyProvider) GenerateDataKey(_ context.Context) ([]byte, []byte, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, nil, err
+	}
+	encryptedKey, err := SealAESGCM(provider.masterKey, dataKey, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return dataKey, encryptedKey, nil
+}
+
+func (provider *staticDataKeyProvider) DecryptDataKey(_ context.Context, encryptedKey []byte) ([]byte, error) {
+	return OpenAESGCM(provider.masterKey, encryptedKey, nil)
+}
+
+func SealAESGCM(key []byte, plaintext []byte, additionalData []byte) ([]byte, error) {
+	aead, err := newAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func OpenAESGCM(key []byte, ciphertext []byte, additionalData []byte) ([]byte, error) {
+	aead, err := newAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("ciphertext is too short")
+	}
+	nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, sealed, additionalData)
+}
+
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,34 @@
+package common_helpers_test
+
+import (
+	"context"
+	"github.com/Drathveloper/lambda_commons/v2/common_helpers"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestStaticDataKeyProvider_ShouldDecryptGeneratedDataKey(t *testing.T) {
+	provider := common_helpers.NewStaticDataKeyProvider([]byte("0123456789abcdef0123456789abcdef"))
+
+	dataKey, encryptedDataKey, err := provider.GenerateDataKey(context.Background())
+	decryptedDataKey, decryptErr := provider.DecryptDataKey(context.Background(), encryptedDataKey)
+
+	assert.NoError(t, err)
+	assert.NoError(t, decryptErr)
+	assert.Len(t, dataKey, 32)
+	assert.NotEqual(t, dataKey, encryptedDataKey)
+	assert.Equal(t, dataKey, decryptedDataKey)
+}
+
+func TestOpenAESGCM_ShouldRejectDifferentAdditionalData(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	ciphertext, err := common_helpers.SealAESGCM(key, []byte("someValue"), []byte("row1"))
+	assert.NoError(t, err)
+
+	plaintext, openErr := common_helpers.OpenAESGCM(key, ciphertext, []byte("row1"))
+	_, tamperedErr := common_helpers.OpenAESGCM(key, ciphertext, []byte("row2"))
+
+	assert.NoError(t, openErr)
+	assert.Equal(t, []byte("someValue"), plaintext)
+	assert.Error(t, tamperedErr)
+}
@@ -0,0 +1,81 @@
+package common_helpers
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const defaultEstimatedCapacityUnits = 1
+
+type CapacityRateLimiter interface {
+	Reserve(ctx context.Context) (float64, bool)
+	Settle(reservedUnits float64, consumedUnits float64)
+}
+
+type capacityRateLimiter struct {
+	mutex          sync.Mutex
+	unitsPerSecond float64
+	estimatedUnits float64
+	nextAvailable  time.Time
+}
+
+// Consumed capacity is only known after a request completes, so every request reserves the capacity consumed by the
+// previous one before it starts and settles the difference once its own consumption is known. Concurrent callers
+// queue behind each other's reservations instead of starting together.
+func NewCapacityRateLimiter(unitsPerSecond float64) CapacityRateLimiter {
+	return &capacityRateLimiter{
+		unitsPerSecond: unitsPerSecond,
+		estimatedUnits: defaultEstimatedCapacityUnits,
+	}
+}
+
+func (limiter *capacityRateLimiter) Reserve(ctx context.Context) (float64, bool) {
+	if ctx.Err() != nil {
+		return 0, false
+	}
+	if limiter.unitsPerSecond <= 0 {
+		return 0, true
+	}
+	limiter.mutex.Lock()
+	reservedUnits := limiter.estimatedUnits
+	start := time.Now()
+	if limiter.nextAvailable.After(start) {
+		start = limiter.nextAvailable
+	}
+	limiter.nextAvailable = start.Add(limiter.durationOf(reservedUnits))
+	limiter.mutex.Unlock()
+	delay := time.Until(start)
+	if delay <= 0 {
+		return reservedUnits, true
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+		limiter.Settle(reservedUnits, 0)
+		return 0, false
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		limiter.Settle(reservedUnits, 0)
+		return 0, false
+	case <-timer.C:
+		return reservedUnits, true
+	}
+}
+
+func (limiter *capacityRateLimiter) Settle(reservedUnits float64, consumedUnits float64) {
+	if limiter.unitsPerSecond <= 0 {
+		return
+	}
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+	if consumedUnits > 0 {
+		limiter.estimatedUnits = consumedUnits
+	}
+	limiter.nextAvailable = limiter.nextAvailable.Add(limiter.durationOf(consumedUnits - reservedUnits))
+}
+
+func (limiter *capacityRateLimiter) durationOf(units float64) time.Duration {
+	return time.Duration(units / limiter.unitsPerSecond * float64(time.Second))
+}
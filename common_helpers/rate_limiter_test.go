@@ -0,0 +1,86 @@
+package common_helpers_test
+
+import (
+	"context"
+	"github.com/Drathveloper/lambda_commons/v2/common_helpers"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCapacityRateLimiter_ShouldNotWaitBeforeConsumingCapacity(t *testing.T) {
+	limiter := common_helpers.NewCapacityRateLimiter(10)
+	start := time.Now()
+
+	_, allowed := limiter.Reserve(context.Background())
+
+	assert.True(t, allowed)
+	assert.Less(t, time.Since(start), 10*time.Millisecond)
+}
+
+func TestCapacityRateLimiter_ShouldWaitForConsumedCapacityToBeRepaid(t *testing.T) {
+	limiter := common_helpers.NewCapacityRateLimiter(1000)
+	start := time.Now()
+
+	reservedUnits, _ := limiter.Reserve(context.Background())
+	limiter.Settle(reservedUnits, 20)
+	_, allowed := limiter.Reserve(context.Background())
+
+	assert.True(t, allowed)
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+}
+
+func TestCapacityRateLimiter_ShouldReserveEstimatedCapacityBeforeEachRequest(t *testing.T) {
+	limiter := common_helpers.NewCapacityRateLimiter(1000)
+	reservedUnits, _ := limiter.Reserve(context.Background())
+	limiter.Settle(reservedUnits, 20)
+	start := time.Now()
+	reservations := make(chan float64, 2)
+
+	for index := 0; index < 2; index++ {
+		go func() {
+			reservedUnits, _ := limiter.Reserve(context.Background())
+			reservations <- reservedUnits
+		}()
+	}
+
+	assert.Equal(t, []float64{20, 20}, []float64{<-reservations, <-reservations})
+	assert.GreaterOrEqual(t, time.Since(start), 35*time.Millisecond)
+}
+
+func TestCapacityRateLimiter_ShouldRefundUnusedReservedCapacity(t *testing.T) {
+	limiter := common_helpers.NewCapacityRateLimiter(1000)
+	reservedUnits, _ := limiter.Reserve(context.Background())
+	limiter.Settle(reservedUnits, 200)
+	reservedUnits, _ = limiter.Reserve(context.Background())
+	start := time.Now()
+
+	limiter.Settle(reservedUnits, 1)
+	_, allowed := limiter.Reserve(context.Background())
+
+	assert.True(t, allowed)
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestCapacityRateLimiter_ShouldNotWaitWhenUnlimited(t *testing.T) {
+	limiter := common_helpers.NewCapacityRateLimiter(0)
+	start := time.Now()
+
+	limiter.Settle(0, 1000)
+	_, allowed := limiter.Reserve(context.Background())
+
+	assert.True(t, allowed)
+	assert.Less(t, time.Since(start), 10*time.Millisecond)
+}
+
+func TestCapacityRateLimiter_ShouldReturnFalseWhenDeadlineIsBeforeNextRequest(t *testing.T) {
+	limiter := common_helpers.NewCapacityRateLimiter(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	reservedUnits, _ := limiter.Reserve(ctx)
+	limiter.Settle(reservedUnits, 10)
+	_, allowed := limiter.Reserve(ctx)
+
+	assert.False(t, allowed)
+}
@@ -0,0 +1,45 @@
+package common_helpers
+
+import (
+	"context"
+	"github.com/Drathveloper/lambda_commons/v2/common_models"
+	"math/rand"
+	"time"
+)
+
+func NewDefaultRetryPolicy() common_models.RetryPolicy {
+	return common_models.RetryPolicy{
+		MaxAttempts: 10,
+		BaseDelay:   50 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+	}
+}
+
+func WaitForRetry(ctx context.Context, policy common_models.RetryPolicy, attempt int) bool {
+	if attempt+1 >= policy.MaxAttempts {
+		return false
+	}
+	delay := computeBackoffDelay(policy, attempt)
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+		return false
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func computeBackoffDelay(policy common_models.RetryPolicy, attempt int) time.Duration {
+	delay := policy.MaxDelay
+	if attempt < 32 && policy.BaseDelay<<uint(attempt) < policy.MaxDelay {
+		delay = policy.BaseDelay << uint(attempt)
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
@@ -0,0 +1,58 @@
+package common_helpers_test
+
+import (
+	"context"
+	"github.com/Drathveloper/lambda_commons/v2/common_helpers"
+	"github.com/Drathveloper/lambda_commons/v2/common_models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestWaitForRetry_ShouldWaitWhenAttemptsRemain(t *testing.T) {
+	ctx := common_models.NewLambdaContext()
+	policy := common_models.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    time.Millisecond,
+	}
+
+	assert.True(t, common_helpers.WaitForRetry(&ctx, policy, 1))
+}
+
+func TestWaitForRetry_ShouldNotWaitWhenAttemptsExhausted(t *testing.T) {
+	ctx := common_models.NewLambdaContext()
+	policy := common_models.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    time.Millisecond,
+	}
+
+	assert.False(t, common_helpers.WaitForRetry(&ctx, policy, 2))
+}
+
+func TestWaitForRetry_ShouldNotWaitBeyondDeadline(t *testing.T) {
+	parent, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	ctx := common_models.NewLambdaContextFromContext(parent)
+	policy := common_models.RetryPolicy{
+		MaxAttempts: 10,
+		BaseDelay:   time.Second,
+		MaxDelay:    time.Second,
+	}
+
+	assert.False(t, common_helpers.WaitForRetry(&ctx, policy, 0))
+}
+
+func TestWaitForRetry_ShouldNotWaitWhenContextIsDone(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	cancel()
+	ctx := common_models.NewLambdaContextFromContext(parent)
+	policy := common_models.RetryPolicy{
+		MaxAttempts: 10,
+		BaseDelay:   time.Second,
+		MaxDelay:    time.Second,
+	}
+
+	assert.False(t, common_helpers.WaitForRetry(&ctx, policy, 0))
+}
@@ -0,0 +1,13 @@
+package common_models
+
+type CompressionCodec interface {
+	Header() byte
+	Compress(data []byte) ([]byte, error)
+	Decompress(data []byte) ([]byte, error)
+}
+
+type DynamodbCompressionConfig struct {
+	Codec      CompressionCodec
+	Attributes []string
+	MinSize    int
+}
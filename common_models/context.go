@@ -1,11 +1,15 @@
 package common_models
 
 import (
+	"context"
+	"github.com/Drathveloper/lambda_commons/v2/common_constants"
+	"github.com/aws/aws-lambda-go/lambdacontext"
 	"time"
 )
 
 type LambdaContext struct {
-	keys map[string]interface{}
+	keys   map[string]interface{}
+	parent context.Context
 }
 
 func NewLambdaContext() LambdaContext {
@@ -14,6 +18,28 @@ func NewLambdaContext() LambdaContext {
 	}
 }
 
+func NewLambdaContextFromContext(parent context.Context) LambdaContext {
+	ctx := LambdaContext{
+		keys:   make(map[string]interface{}, 0),
+		parent: parent,
+	}
+	if lambdaContext, exists := lambdacontext.FromContext(parent); exists && lambdaContext.AwsRequestID != "" {
+		ctx.Set(common_constants.LambdaRequestID, lambdaContext.AwsRequestID)
+	}
+	return ctx
+}
+
+func (ctx *LambdaContext) NewChildContext() LambdaContext {
+	child := LambdaContext{
+		keys:   make(map[string]interface{}, len(ctx.keys)),
+		parent: ctx.parent,
+	}
+	for key, value := range ctx.keys {
+		child.keys[key] = value
+	}
+	return child
+}
+
 func (ctx *LambdaContext) Get(key string) (interface{}, bool) {
 	value, exists := ctx.keys[key]
 	return value, exists
@@ -28,14 +54,23 @@ func (ctx *LambdaContext) Exists(key string) bool {
 }
 
 func (ctx LambdaContext) Deadline() (deadline time.Time, ok bool) {
+	if ctx.parent != nil {
+		return ctx.parent.Deadline()
+	}
 	return
 }
 
 func (ctx LambdaContext) Done() <-chan struct{} {
+	if ctx.parent != nil {
+		return ctx.parent.Done()
+	}
 	return nil
 }
 
 func (ctx LambdaContext) Err() error {
+	if ctx.parent != nil {
+		return ctx.parent.Err()
+	}
 	return nil
 }
 
@@ -45,5 +80,8 @@ func (ctx LambdaContext) Value(key interface{}) interface{} {
 			return val
 		}
 	}
+	if ctx.parent != nil {
+		return ctx.parent.Value(key)
+	}
 	return nil
 }
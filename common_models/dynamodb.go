@@ -1,5 +1,33 @@
 package common_models
 
+import (
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+type DynamodbSortKeyOperator string
+
+const (
+	DynamodbSortKeyEqual              DynamodbSortKeyOperator = "EQ"
+	DynamodbSortKeyLessThan           DynamodbSortKeyOperator = "LT"
+	DynamodbSortKeyLessThanOrEqual    DynamodbSortKeyOperator = "LE"
+	DynamodbSortKeyGreaterThan        DynamodbSortKeyOperator = "GT"
+	DynamodbSortKeyGreaterThanOrEqual DynamodbSortKeyOperator = "GE"
+	DynamodbSortKeyBeginsWith         DynamodbSortKeyOperator = "BEGINS_WITH"
+	DynamodbSortKeyBetween            DynamodbSortKeyOperator = "BETWEEN"
+)
+
+type DynamodbUpdateAction string
+
+const (
+	DynamodbUpdateSet            DynamodbUpdateAction = "SET"
+	DynamodbUpdateSetIfNotExists DynamodbUpdateAction = "SET_IF_NOT_EXISTS"
+	DynamodbUpdateListAppend     DynamodbUpdateAction = "LIST_APPEND"
+	DynamodbUpdateRemove         DynamodbUpdateAction = "REMOVE"
+	DynamodbUpdateAdd            DynamodbUpdateAction = "ADD"
+	DynamodbUpdateDelete         DynamodbUpdateAction = "DELETE"
+)
+
 type DynamodbSimplePrimaryKey struct {
 	KeyName string
 	Value   interface{}
@@ -9,3 +37,64 @@ type DynamodbComplexPrimaryKey struct {
 	PartitionKey DynamodbSimplePrimaryKey
 	SortKey      DynamodbSimplePrimaryKey
 }
+
+type DynamodbSecondaryIndex struct {
+	IndexName string
+	IsGlobal  bool
+}
+
+type DynamodbSortKeyCondition struct {
+	KeyName  string
+	Operator DynamodbSortKeyOperator
+	Values   []interface{}
+}
+
+type DynamodbQuery struct {
+	Index               *DynamodbSecondaryIndex
+	PartitionKey        DynamodbSimplePrimaryKey
+	SortKeyCondition    *DynamodbSortKeyCondition
+	Filter              *expression.ConditionBuilder
+	ProjectedAttributes []string
+	IsDescending        bool
+	Limit               int32
+	ContinuationToken   string
+	IsConsistentRead    bool
+}
+
+type DynamodbQueryResult struct {
+	Items             []map[string]types.AttributeValue
+	ContinuationToken string
+}
+
+type DynamodbDeleteOptions struct {
+	Condition       *expression.ConditionBuilder
+	ReturnOldValues bool
+}
+
+type DynamodbUpdateOperation struct {
+	Action        DynamodbUpdateAction
+	AttributeName string
+	Value         interface{}
+}
+
+type DynamodbUpdate struct {
+	Operations      []DynamodbUpdateOperation
+	Condition       *expression.ConditionBuilder
+	ExpectedVersion *int64
+}
+
+type DynamodbPrimaryKey interface {
+	DynamodbSimplePrimaryKey | DynamodbComplexPrimaryKey
+}
+
+type DynamodbIndexSchema struct {
+	PartitionKey string
+	SortKey      string
+}
+
+type DynamodbTableSchema struct {
+	PartitionKey        string
+	SortKey             string
+	Indexes             map[string]DynamodbIndexSchema
+	EncryptedAttributes []string
+}
@@ -0,0 +1,12 @@
+package common_models
+
+import "time"
+
+type DynamodbAuditConfig struct {
+	CreatedAtAttribute string
+	UpdatedAtAttribute string
+	CreatedByAttribute string
+	UpdatedByAttribute string
+	DefaultPrincipal   string
+	Clock              func() time.Time
+}
@@ -0,0 +1,18 @@
+package common_models
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrBlobNotFound = errors.New("blob not found")
+
+type DynamodbBlobOffloadConfig struct {
+	BlobStore       BlobStoreAPI
+	Attributes      []string
+	SizeThreshold   int
+	KeyPrefix       string
+	KeyAttributes   []string
+	ExpirationDelay time.Duration
+	Clock           func() time.Time
+}
@@ -0,0 +1,11 @@
+package common_models
+
+type DynamodbCounterConfig struct {
+	ValueAttribute string
+	BlockSize      int64
+}
+
+type DynamodbCounterBlock struct {
+	First int64
+	Last  int64
+}
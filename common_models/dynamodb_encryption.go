@@ -0,0 +1,7 @@
+package common_models
+
+type DynamodbEncryptionConfig struct {
+	KeyProvider   DataKeyProviderAPI
+	Attributes    []string
+	KeyAttributes []string
+}
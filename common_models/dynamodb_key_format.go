@@ -0,0 +1,24 @@
+package common_models
+
+type DynamodbKeySegmentType string
+
+const (
+	DynamodbKeySegmentLiteral DynamodbKeySegmentType = "LITERAL"
+	DynamodbKeySegmentString  DynamodbKeySegmentType = "STRING"
+	DynamodbKeySegmentInteger DynamodbKeySegmentType = "INTEGER"
+	DynamodbKeySegmentTime    DynamodbKeySegmentType = "TIME"
+)
+
+type DynamodbKeySegment struct {
+	Type   DynamodbKeySegmentType
+	Name   string
+	Width  int
+	Layout string
+}
+
+type DynamodbKeyFormat struct {
+	Delimiter string
+	Segments  []DynamodbKeySegment
+}
+
+type DynamodbKeyValues map[string]interface{}
@@ -0,0 +1,40 @@
+package common_models
+
+import (
+	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"time"
+)
+
+type DynamodbScan struct {
+	Index                     *DynamodbSecondaryIndex
+	Filter                    *expression.ConditionBuilder
+	ProjectedAttributes       []string
+	TotalSegments             int32
+	MaxConcurrentSegments     int
+	PageSize                  int32
+	IsConsistentRead          bool
+	MaxCapacityUnitsPerSecond float64
+	DeadlineMargin            time.Duration
+	Checkpoint                *DynamodbScanCheckpoint
+}
+
+type DynamodbScanCheckpoint struct {
+	Segments []DynamodbScanSegmentCheckpoint `json:"segments"`
+}
+
+type DynamodbScanSegmentCheckpoint struct {
+	Segment           int32  `json:"segment"`
+	ContinuationToken string `json:"continuationToken,omitempty"`
+	IsCompleted       bool   `json:"isCompleted"`
+}
+
+type DynamodbScanResult struct {
+	Checkpoint       DynamodbScanCheckpoint
+	IsCompleted      bool
+	ProcessedItems   int64
+	ConsumedCapacity float64
+}
+
+type DynamodbScanCallback func(ctx *LambdaContext, item map[string]types.AttributeValue) common_errors.GenericApplicationError
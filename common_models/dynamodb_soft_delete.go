@@ -0,0 +1,10 @@
+package common_models
+
+import "time"
+
+type DynamodbSoftDeleteConfig struct {
+	DeletedAtAttribute  string
+	ExpirationAttribute string
+	Retention           time.Duration
+	Clock               func() time.Time
+}
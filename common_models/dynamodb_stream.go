@@ -0,0 +1,45 @@
+package common_models
+
+import (
+	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"time"
+)
+
+type DynamodbStreamEventName string
+
+const (
+	DynamodbStreamInsert DynamodbStreamEventName = "INSERT"
+	DynamodbStreamModify DynamodbStreamEventName = "MODIFY"
+	DynamodbStreamRemove DynamodbStreamEventName = "REMOVE"
+)
+
+type DynamodbStreamImageName string
+
+const (
+	DynamodbStreamOldImage DynamodbStreamImageName = "old"
+	DynamodbStreamNewImage DynamodbStreamImageName = "new"
+)
+
+type DynamodbStreamRecord[T any] struct {
+	EventID            string
+	EventName          DynamodbStreamEventName
+	EventSourceArn     string
+	SequenceNumber     string
+	CreationTime       time.Time
+	Keys               map[string]types.AttributeValue
+	OldImage           *T
+	NewImage           *T
+	IsTimeToLiveDelete bool
+}
+
+// Stream images carry attributes as stored, so compressed, encrypted or offloaded attributes only decode into T once
+// the owning repository has prepared them. DynamodbBaseRepository.PrepareStreamImage fits this signature.
+type DynamodbStreamImageDecoder func(ctx *LambdaContext, imageName DynamodbStreamImageName, image map[string]types.AttributeValue) common_errors.GenericApplicationError
+
+type DynamodbStreamHandlers[T any] struct {
+	ImageDecoder DynamodbStreamImageDecoder
+	OnInsert     func(ctx *LambdaContext, record DynamodbStreamRecord[T]) common_errors.GenericApplicationError
+	OnModify     func(ctx *LambdaContext, record DynamodbStreamRecord[T]) common_errors.GenericApplicationError
+	OnRemove     func(ctx *LambdaContext, record DynamodbStreamRecord[T]) common_errors.GenericApplicationError
+}
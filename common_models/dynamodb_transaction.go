@@ -0,0 +1,122 @@
+package common_models
+
+import (
+	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+type DynamodbTransactWriteItemMetadata struct {
+	IsVersionCheck bool
+	Label          string
+}
+
+type DynamodbConditionCheck struct {
+	Condition expression.ConditionBuilder
+	Label     string
+}
+
+type DynamodbTransactionPartReport struct {
+	FirstItemIndex int
+	ItemCount      int
+	IsCommitted    bool
+	Error          common_errors.GenericApplicationError
+}
+
+type DynamodbBestEffortWriteReport struct {
+	Parts []DynamodbTransactionPartReport
+}
+
+func (report DynamodbBestEffortWriteReport) IsFullyCommitted() bool {
+	for _, part := range report.Parts {
+		if !part.IsCommitted {
+			return false
+		}
+	}
+	return true
+}
+
+type DynamodbReadItemPreparer func(item map[string]types.AttributeValue) (map[string]types.AttributeValue, common_errors.GenericApplicationError)
+
+type DynamodbReadTransactionResults struct {
+	slots []*dynamodbReadSlot
+}
+
+type dynamodbReadSlot struct {
+	index       int
+	preparer    DynamodbReadItemPreparer
+	item        map[string]types.AttributeValue
+	isExecuted  bool
+	isReturned  bool
+	isDiscarded bool
+}
+
+type DynamodbReadHandle struct {
+	slot *dynamodbReadSlot
+}
+
+func NewDynamodbReadTransactionResults() *DynamodbReadTransactionResults {
+	return &DynamodbReadTransactionResults{
+		slots: make([]*dynamodbReadSlot, 0),
+	}
+}
+
+func (results *DynamodbReadTransactionResults) NewHandle(index int, preparer DynamodbReadItemPreparer) DynamodbReadHandle {
+	slot := &dynamodbReadSlot{
+		index:    index,
+		preparer: preparer,
+	}
+	results.slots = append(results.slots, slot)
+	return DynamodbReadHandle{
+		slot: slot,
+	}
+}
+
+// Runs each slot's preparer on its item before completing the handles; prepared items replace the raw ones in items.
+func (results *DynamodbReadTransactionResults) Complete(items []map[string]types.AttributeValue) common_errors.GenericApplicationError {
+	for _, slot := range results.slots {
+		if slot.index >= len(items) || slot.preparer == nil {
+			continue
+		}
+		item, appErr := slot.preparer(items[slot.index])
+		if appErr != nil {
+			return appErr
+		}
+		items[slot.index] = item
+	}
+	for _, slot := range results.slots {
+		slot.isExecuted = true
+		if slot.index < len(items) {
+			slot.item = items[slot.index]
+			slot.isReturned = true
+		}
+	}
+	return nil
+}
+
+func (results *DynamodbReadTransactionResults) DiscardFrom(index int) {
+	keptSlots := make([]*dynamodbReadSlot, 0, len(results.slots))
+	for _, slot := range results.slots {
+		if slot.index >= index {
+			slot.isDiscarded = true
+			continue
+		}
+		keptSlots = append(keptSlots, slot)
+	}
+	results.slots = keptSlots
+}
+
+func (handle DynamodbReadHandle) Item() (map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+	switch {
+	case handle.slot == nil:
+		return nil, common_errors.NewInternalServerError("read handle does not belong to a read transaction")
+	case handle.slot.isDiscarded:
+		return nil, common_errors.NewInternalServerError("read was discarded by a failed transaction scope")
+	case !handle.slot.isExecuted:
+		return nil, common_errors.NewInternalServerError("read transaction has not been executed yet")
+	case !handle.slot.isReturned:
+		return nil, common_errors.NewInternalServerError("read transaction did not return the requested item")
+	default:
+		return handle.slot.item, nil
+	}
+}
@@ -0,0 +1,14 @@
+package common_models
+
+import "time"
+
+type DynamodbEntity struct {
+	Item           interface{}
+	ExpirationTime time.Duration
+}
+
+type DynamodbTimeToLiveConfig struct {
+	Attribute          string
+	IsFilteringExpired bool
+	Clock              func() time.Time
+}
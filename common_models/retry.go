@@ -0,0 +1,9 @@
+package common_models
+
+import "time"
+
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
@@ -4,59 +4,147 @@ import (
 	"errors"
 	"github.com/Drathveloper/lambda_commons/v2/common_constants"
 	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/Drathveloper/lambda_commons/v2/common_helpers"
 	"github.com/Drathveloper/lambda_commons/v2/common_models"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"strconv"
 )
 
 type DynamodbBaseRepository interface {
 	FindBySimplePrimaryKey(ctx *common_models.LambdaContext, primaryKey common_models.DynamodbSimplePrimaryKey, isConsistentRead bool) (map[string]types.AttributeValue, common_errors.GenericApplicationError)
 	FindByComplexPrimaryKey(ctx *common_models.LambdaContext, primaryKey common_models.DynamodbComplexPrimaryKey, isConsistentRead bool) (map[string]types.AttributeValue, common_errors.GenericApplicationError)
+	FindBySimplePrimaryKeyInto(ctx *common_models.LambdaContext, primaryKey common_models.DynamodbSimplePrimaryKey, isConsistentRead bool, dest interface{}) (bool, common_errors.GenericApplicationError)
+	FindByComplexPrimaryKeyInto(ctx *common_models.LambdaContext, primaryKey common_models.DynamodbComplexPrimaryKey, isConsistentRead bool, dest interface{}) (bool, common_errors.GenericApplicationError)
+	DecodeItem(item map[string]types.AttributeValue, dest interface{}) common_errors.GenericApplicationError
+	PrepareStreamImage(ctx *common_models.LambdaContext, imageName common_models.DynamodbStreamImageName, image map[string]types.AttributeValue) common_errors.GenericApplicationError
+	FindBySimplePrimaryKeyInReadTransaction(ctx *common_models.LambdaContext, primaryKey common_models.DynamodbSimplePrimaryKey) (common_models.DynamodbReadHandle, common_errors.GenericApplicationError)
+	FindByComplexPrimaryKeyInReadTransaction(ctx *common_models.LambdaContext, primaryKey common_models.DynamodbComplexPrimaryKey) (common_models.DynamodbReadHandle, common_errors.GenericApplicationError)
+	ConditionCheckBySimplePrimaryKey(ctx *common_models.LambdaContext, primaryKey common_models.DynamodbSimplePrimaryKey, conditionCheck common_models.DynamodbConditionCheck) common_errors.GenericApplicationError
+	ConditionCheckByComplexPrimaryKey(ctx *common_models.LambdaContext, primaryKey common_models.DynamodbComplexPrimaryKey, conditionCheck common_models.DynamodbConditionCheck) common_errors.GenericApplicationError
 	SaveIfNotPresentWithSimplePrimaryKey(ctx *common_models.LambdaContext, primaryKey common_models.DynamodbSimplePrimaryKey, item interface{}) common_errors.GenericApplicationError
 	SaveIfNotPresentWithComplexPrimaryKey(ctx *common_models.LambdaContext, primaryKey common_models.DynamodbComplexPrimaryKey, item interface{}) common_errors.GenericApplicationError
+	SaveIfNotPresent(ctx *common_models.LambdaContext, item interface{}) common_errors.GenericApplicationError
 	Save(ctx *common_models.LambdaContext, item interface{}) common_errors.GenericApplicationError
+	UpdateBySimplePrimaryKey(ctx *common_models.LambdaContext, primaryKey common_models.DynamodbSimplePrimaryKey, update common_models.DynamodbUpdate) (map[string]types.AttributeValue, common_errors.GenericApplicationError)
+	UpdateByComplexPrimaryKey(ctx *common_models.LambdaContext, primaryKey common_models.DynamodbComplexPrimaryKey, update common_models.DynamodbUpdate) (map[string]types.AttributeValue, common_errors.GenericApplicationError)
+	DeleteBySimplePrimaryKey(ctx *common_models.LambdaContext, primaryKey common_models.DynamodbSimplePrimaryKey, options common_models.DynamodbDeleteOptions) (map[string]types.AttributeValue, common_errors.GenericApplicationError)
+	DeleteByComplexPrimaryKey(ctx *common_models.LambdaContext, primaryKey common_models.DynamodbComplexPrimaryKey, options common_models.DynamodbDeleteOptions) (map[string]types.AttributeValue, common_errors.GenericApplicationError)
+	DeleteByItem(ctx *common_models.LambdaContext, item interface{}, options common_models.DynamodbDeleteOptions) (map[string]types.AttributeValue, common_errors.GenericApplicationError)
+	ExtractPrimaryKey(item interface{}) (map[string]types.AttributeValue, common_errors.GenericApplicationError)
+	Query(ctx *common_models.LambdaContext, query common_models.DynamodbQuery) (common_models.DynamodbQueryResult, common_errors.GenericApplicationError)
+	Scan(ctx *common_models.LambdaContext, scan common_models.DynamodbScan, callback common_models.DynamodbScanCallback) (common_models.DynamodbScanResult, common_errors.GenericApplicationError)
+	BatchGetBySimplePrimaryKeys(ctx *common_models.LambdaContext, primaryKeys []common_models.DynamodbSimplePrimaryKey, isConsistentRead bool) (map[common_models.DynamodbSimplePrimaryKey]map[string]types.AttributeValue, common_errors.GenericApplicationError)
+	BatchGetByComplexPrimaryKeys(ctx *common_models.LambdaContext, primaryKeys []common_models.DynamodbComplexPrimaryKey, isConsistentRead bool) (map[common_models.DynamodbComplexPrimaryKey]map[string]types.AttributeValue, common_errors.GenericApplicationError)
+	BatchSave(ctx *common_models.LambdaContext, items []interface{}) common_errors.GenericApplicationError
+	BatchDeleteBySimplePrimaryKeys(ctx *common_models.LambdaContext, primaryKeys []common_models.DynamodbSimplePrimaryKey) common_errors.GenericApplicationError
+	BatchDeleteByComplexPrimaryKeys(ctx *common_models.LambdaContext, primaryKeys []common_models.DynamodbComplexPrimaryKey) common_errors.GenericApplicationError
+	FindByIndexSimpleKey(ctx *common_models.LambdaContext, index common_models.DynamodbSecondaryIndex, indexKey common_models.DynamodbSimplePrimaryKey, projectedAttributes []string, isConsistentRead bool) ([]map[string]types.AttributeValue, common_errors.GenericApplicationError)
+	FindByIndexComplexKey(ctx *common_models.LambdaContext, index common_models.DynamodbSecondaryIndex, indexKey common_models.DynamodbComplexPrimaryKey, projectedAttributes []string, isConsistentRead bool) ([]map[string]types.AttributeValue, common_errors.GenericApplicationError)
+	IncludingDeleted() DynamodbBaseRepository
 }
 
 type dynamodbBaseRepository struct {
-	tableName string
-	client    common_models.DynamodbClientAPI
+	tableName          string
+	client             common_models.DynamodbClientAPI
+	versionAttribute   string
+	batchRetryPolicy   common_models.RetryPolicy
+	decoderOptions     []func(*attributevalue.DecoderOptions)
+	tableSchema        *common_models.DynamodbTableSchema
+	auditConfig        *common_models.DynamodbAuditConfig
+	softDeleteConfig   *common_models.DynamodbSoftDeleteConfig
+	timeToLiveConfig   *common_models.DynamodbTimeToLiveConfig
+	blobOffloadConfig  *common_models.DynamodbBlobOffloadConfig
+	encryptionConfig   *common_models.DynamodbEncryptionConfig
+	compressionConfig  *common_models.DynamodbCompressionConfig
+	isIncludingDeleted bool
 }
 
-func NewDynamodbBaseRepository(client common_models.DynamodbClientAPI, tableName string) DynamodbBaseRepository {
-	return &dynamodbBaseRepository{
-		tableName: tableName,
-		client:    client,
+func NewDynamodbBaseRepository(client common_models.DynamodbClientAPI, tableName string, options ...DynamodbBaseRepositoryOption) DynamodbBaseRepository {
+	repository := &dynamodbBaseRepository{
+		tableName:        tableName,
+		client:           client,
+		batchRetryPolicy: common_helpers.NewDefaultRetryPolicy(),
 	}
+	for _, option := range options {
+		option(repository)
+	}
+	return repository
 }
 
 func (repository *dynamodbBaseRepository) FindBySimplePrimaryKey(ctx *common_models.LambdaContext, primaryKey common_models.DynamodbSimplePrimaryKey, isConsistentRead bool) (map[string]types.AttributeValue, common_errors.GenericApplicationError) {
-	value, err := attributevalue.Marshal(primaryKey.Value)
-	if err != nil {
-		return nil, common_errors.NewInternalServerError("error while marshaling database primary key")
-	}
-	keyValues := map[string]types.AttributeValue{
-		primaryKey.KeyName: value,
+	keyValues, appErr := marshalSimplePrimaryKey(primaryKey)
+	if appErr != nil {
+		return nil, appErr
 	}
 	return repository.findByPrimaryKey(ctx, keyValues, isConsistentRead)
 }
 
 func (repository *dynamodbBaseRepository) FindByComplexPrimaryKey(ctx *common_models.LambdaContext, primaryKey common_models.DynamodbComplexPrimaryKey, isConsistentRead bool) (map[string]types.AttributeValue, common_errors.GenericApplicationError) {
-	partitionKeyValue, err := attributevalue.Marshal(primaryKey.PartitionKey.Value)
-	if err != nil {
-		return nil, common_errors.NewInternalServerError("error while marshaling database partition key")
+	keyValues, appErr := marshalComplexPrimaryKey(primaryKey)
+	if appErr != nil {
+		return nil, appErr
 	}
-	sortKeyValue, err := attributevalue.Marshal(primaryKey.SortKey.Value)
-	if err != nil {
-		return nil, common_errors.NewInternalServerError("error while marshaling database sort key")
+	return repository.findByPrimaryKey(ctx, keyValues, isConsistentRead)
+}
+
+func (repository *dynamodbBaseRepository) FindBySimplePrimaryKeyInto(ctx *common_models.LambdaContext, primaryKey common_models.DynamodbSimplePrimaryKey, isConsistentRead bool, dest interface{}) (bool, common_errors.GenericApplicationError) {
+	keyValues, appErr := marshalSimplePrimaryKey(primaryKey)
+	if appErr != nil {
+		return false, appErr
 	}
-	keyValues := map[string]types.AttributeValue{
-		primaryKey.PartitionKey.KeyName: partitionKeyValue,
-		primaryKey.SortKey.KeyName:      sortKeyValue,
+	return repository.findByPrimaryKeyInto(ctx, keyValues, isConsistentRead, dest)
+}
+
+func (repository *dynamodbBaseRepository) FindByComplexPrimaryKeyInto(ctx *common_models.LambdaContext, primaryKey common_models.DynamodbComplexPrimaryKey, isConsistentRead bool, dest interface{}) (bool, common_errors.GenericApplicationError) {
+	keyValues, appErr := marshalComplexPrimaryKey(primaryKey)
+	if appErr != nil {
+		return false, appErr
 	}
-	return repository.findByPrimaryKey(ctx, keyValues, isConsistentRead)
+	return repository.findByPrimaryKeyInto(ctx, keyValues, isConsistentRead, dest)
+}
+
+func (repository *dynamodbBaseRepository) DecodeItem(item map[string]types.AttributeValue, dest interface{}) common_errors.GenericApplicationError {
+	decoder := attributevalue.NewDecoder(repository.decoderOptions...)
+	if err := decoder.Decode(&types.AttributeValueMemberM{Value: item}, dest); err != nil {
+		return common_errors.NewInternalServerError("error while decoding item")
+	}
+	return nil
+}
+
+func (repository *dynamodbBaseRepository) FindBySimplePrimaryKeyInReadTransaction(ctx *common_models.LambdaContext, primaryKey common_models.DynamodbSimplePrimaryKey) (common_models.DynamodbReadHandle, common_errors.GenericApplicationError) {
+	keyValues, appErr := marshalSimplePrimaryKey(primaryKey)
+	if appErr != nil {
+		return common_models.DynamodbReadHandle{}, appErr
+	}
+	return repository.findByPrimaryKeyInReadTransaction(ctx, keyValues)
+}
+
+func (repository *dynamodbBaseRepository) FindByComplexPrimaryKeyInReadTransaction(ctx *common_models.LambdaContext, primaryKey common_models.DynamodbComplexPrimaryKey) (common_models.DynamodbReadHandle, common_errors.GenericApplicationError) {
+	keyValues, appErr := marshalComplexPrimaryKey(primaryKey)
+	if appErr != nil {
+		return common_models.DynamodbReadHandle{}, appErr
+	}
+	return repository.findByPrimaryKeyInReadTransaction(ctx, keyValues)
+}
+
+func (repository *dynamodbBaseRepository) ConditionCheckBySimplePrimaryKey(ctx *common_models.LambdaContext, primaryKey common_models.DynamodbSimplePrimaryKey, conditionCheck common_models.DynamodbConditionCheck) common_errors.GenericApplicationError {
+	keyValues, appErr := marshalSimplePrimaryKey(primaryKey)
+	if appErr != nil {
+		return appErr
+	}
+	return repository.conditionCheck(ctx, keyValues, conditionCheck)
+}
+
+func (repository *dynamodbBaseRepository) ConditionCheckByComplexPrimaryKey(ctx *common_models.LambdaContext, primaryKey common_models.DynamodbComplexPrimaryKey, conditionCheck common_models.DynamodbConditionCheck) common_errors.GenericApplicationError {
+	keyValues, appErr := marshalComplexPrimaryKey(primaryKey)
+	if appErr != nil {
+		return appErr
+	}
+	return repository.conditionCheck(ctx, keyValues, conditionCheck)
 }
 
 func (repository *dynamodbBaseRepository) SaveIfNotPresentWithSimplePrimaryKey(ctx *common_models.LambdaContext, primaryKey common_models.DynamodbSimplePrimaryKey, item interface{}) common_errors.GenericApplicationError {
@@ -70,12 +158,13 @@ func (repository *dynamodbBaseRepository) SaveIfNotPresentWithSimplePrimaryKey(c
 	if err != nil {
 		return common_errors.NewInternalServerError("error while building save expression")
 	}
-	itemAttributeValue, err := attributevalue.MarshalMap(item)
-	if err != nil {
-		return common_errors.NewInternalServerError("error while marshaling item")
+	itemAttributeValue, appErr := repository.marshalItem(item)
+	if appErr != nil {
+		return appErr
 	}
 	itemAttributeValue[primaryKey.KeyName] = primaryKeyValue
-	return repository.save(ctx, builtExpression, itemAttributeValue)
+	repository.initializeVersion(itemAttributeValue)
+	return repository.save(ctx, builtExpression, itemAttributeValue, false)
 }
 
 func (repository *dynamodbBaseRepository) SaveIfNotPresentWithComplexPrimaryKey(ctx *common_models.LambdaContext, primaryKey common_models.DynamodbComplexPrimaryKey, item interface{}) common_errors.GenericApplicationError {
@@ -95,83 +184,710 @@ func (repository *dynamodbBaseRepository) SaveIfNotPresentWithComplexPrimaryKey(
 	if err != nil {
 		return common_errors.NewInternalServerError("error while building save expression")
 	}
-	itemAttributeValue, err := attributevalue.MarshalMap(item)
-	if err != nil {
-		return common_errors.NewInternalServerError("error while marshaling item")
+	itemAttributeValue, appErr := repository.marshalItem(item)
+	if appErr != nil {
+		return appErr
 	}
 	itemAttributeValue[primaryKey.PartitionKey.KeyName] = partitionKeyValue
 	itemAttributeValue[primaryKey.SortKey.KeyName] = sortKeyValue
-	return repository.save(ctx, builtExpression, itemAttributeValue)
+	repository.initializeVersion(itemAttributeValue)
+	return repository.save(ctx, builtExpression, itemAttributeValue, false)
 }
 
 func (repository *dynamodbBaseRepository) Save(ctx *common_models.LambdaContext, item interface{}) common_errors.GenericApplicationError {
-	itemAttributeValue, err := attributevalue.MarshalMap(item)
+	itemAttributeValue, appErr := repository.marshalItem(item)
+	if appErr != nil {
+		return appErr
+	}
+	if repository.versionAttribute == "" {
+		return repository.save(ctx, expression.Expression{}, itemAttributeValue, false)
+	}
+	condition, appErr := repository.incrementVersion(itemAttributeValue)
+	if appErr != nil {
+		return appErr
+	}
+	builtExpression, err := expression.NewBuilder().WithCondition(condition).Build()
 	if err != nil {
-		return common_errors.NewInternalServerError("error while marshaling item")
+		return common_errors.NewInternalServerError("error while building save expression")
+	}
+	return repository.save(ctx, builtExpression, itemAttributeValue, true)
+}
+
+func (repository *dynamodbBaseRepository) UpdateBySimplePrimaryKey(ctx *common_models.LambdaContext, primaryKey common_models.DynamodbSimplePrimaryKey, update common_models.DynamodbUpdate) (map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+	keyValues, appErr := marshalSimplePrimaryKey(primaryKey)
+	if appErr != nil {
+		return nil, appErr
+	}
+	return repository.update(ctx, keyValues, update)
+}
+
+func (repository *dynamodbBaseRepository) UpdateByComplexPrimaryKey(ctx *common_models.LambdaContext, primaryKey common_models.DynamodbComplexPrimaryKey, update common_models.DynamodbUpdate) (map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+	keyValues, appErr := marshalComplexPrimaryKey(primaryKey)
+	if appErr != nil {
+		return nil, appErr
+	}
+	return repository.update(ctx, keyValues, update)
+}
+
+func (repository *dynamodbBaseRepository) DeleteBySimplePrimaryKey(ctx *common_models.LambdaContext, primaryKey common_models.DynamodbSimplePrimaryKey, options common_models.DynamodbDeleteOptions) (map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+	keyValues, appErr := marshalSimplePrimaryKey(primaryKey)
+	if appErr != nil {
+		return nil, appErr
+	}
+	return repository.delete(ctx, keyValues, options)
+}
+
+func (repository *dynamodbBaseRepository) DeleteByComplexPrimaryKey(ctx *common_models.LambdaContext, primaryKey common_models.DynamodbComplexPrimaryKey, options common_models.DynamodbDeleteOptions) (map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+	keyValues, appErr := marshalComplexPrimaryKey(primaryKey)
+	if appErr != nil {
+		return nil, appErr
 	}
-	builtExpression := expression.Expression{}
-	return repository.save(ctx, builtExpression, itemAttributeValue)
+	return repository.delete(ctx, keyValues, options)
 }
 
-func (repository *dynamodbBaseRepository) save(ctx *common_models.LambdaContext, expression expression.Expression, item map[string]types.AttributeValue) common_errors.GenericApplicationError {
-	if input, exists := ctx.Get(common_constants.WriteTransaction); exists {
-		transactionInput := input.(dynamodb.TransactWriteItemsInput)
-		transactWriteItem := types.TransactWriteItem{
-			Put: &types.Put{
-				TableName:                 aws.String(repository.tableName),
-				ConditionExpression:       expression.Condition(),
-				ExpressionAttributeNames:  expression.Names(),
-				ExpressionAttributeValues: expression.Values(),
-				Item:                      item,
-			},
+func (repository *dynamodbBaseRepository) Query(ctx *common_models.LambdaContext, query common_models.DynamodbQuery) (common_models.DynamodbQueryResult, common_errors.GenericApplicationError) {
+	if appErr := validateSecondaryIndex(query.Index, query.IsConsistentRead); appErr != nil {
+		return common_models.DynamodbQueryResult{}, appErr
+	}
+	if appErr := repository.validateQueryKeys(query); appErr != nil {
+		return common_models.DynamodbQueryResult{}, appErr
+	}
+	keyCondition, appErr := buildKeyCondition(query)
+	if appErr != nil {
+		return common_models.DynamodbQueryResult{}, appErr
+	}
+	expressionBuilder := expression.NewBuilder().WithKeyCondition(keyCondition)
+	if filter := repository.applyExpirationToFilter(repository.applySoftDeleteToFilter(query.Filter)); filter != nil {
+		expressionBuilder = expressionBuilder.WithFilter(*filter)
+	}
+	if len(query.ProjectedAttributes) > 0 {
+		expressionBuilder = expressionBuilder.WithProjection(buildProjection(repository.expandProjectedAttributes(query.ProjectedAttributes)))
+	}
+	builtExpression, err := expressionBuilder.Build()
+	if err != nil {
+		return common_models.DynamodbQueryResult{}, common_errors.NewInternalServerError("error while building query expression")
+	}
+	exclusiveStartKey, appErr := common_helpers.DecodeDynamodbContinuationToken(query.ContinuationToken)
+	if appErr != nil {
+		return common_models.DynamodbQueryResult{}, appErr
+	}
+	queryInput := &dynamodb.QueryInput{
+		TableName:                 aws.String(repository.tableName),
+		ConsistentRead:            aws.Bool(query.IsConsistentRead),
+		KeyConditionExpression:    builtExpression.KeyCondition(),
+		FilterExpression:          builtExpression.Filter(),
+		ProjectionExpression:      builtExpression.Projection(),
+		ExpressionAttributeNames:  builtExpression.Names(),
+		ExpressionAttributeValues: builtExpression.Values(),
+		ScanIndexForward:          aws.Bool(!query.IsDescending),
+		ExclusiveStartKey:         exclusiveStartKey,
+	}
+	if query.Index != nil {
+		queryInput.IndexName = aws.String(query.Index.IndexName)
+	}
+	if query.Limit > 0 {
+		queryInput.Limit = aws.Int32(query.Limit)
+	}
+	queryOutput, err := repository.client.Query(ctx, queryInput)
+	if err != nil {
+		return common_models.DynamodbQueryResult{}, common_errors.NewInternalServerError("error while querying database")
+	}
+	if appErr := repository.prepareItemsForRead(ctx, queryOutput.Items); appErr != nil {
+		return common_models.DynamodbQueryResult{}, appErr
+	}
+	continuationToken, appErr := common_helpers.EncodeDynamodbContinuationToken(queryOutput.LastEvaluatedKey)
+	if appErr != nil {
+		return common_models.DynamodbQueryResult{}, appErr
+	}
+	return common_models.DynamodbQueryResult{
+		Items:             queryOutput.Items,
+		ContinuationToken: continuationToken,
+	}, nil
+}
+
+func (repository *dynamodbBaseRepository) FindByIndexSimpleKey(ctx *common_models.LambdaContext, index common_models.DynamodbSecondaryIndex, indexKey common_models.DynamodbSimplePrimaryKey, projectedAttributes []string, isConsistentRead bool) ([]map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+	query := common_models.DynamodbQuery{
+		Index:               &index,
+		PartitionKey:        indexKey,
+		ProjectedAttributes: projectedAttributes,
+		IsConsistentRead:    isConsistentRead,
+	}
+	return repository.queryAllPages(ctx, query)
+}
+
+func (repository *dynamodbBaseRepository) FindByIndexComplexKey(ctx *common_models.LambdaContext, index common_models.DynamodbSecondaryIndex, indexKey common_models.DynamodbComplexPrimaryKey, projectedAttributes []string, isConsistentRead bool) ([]map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+	query := common_models.DynamodbQuery{
+		Index:        &index,
+		PartitionKey: indexKey.PartitionKey,
+		SortKeyCondition: &common_models.DynamodbSortKeyCondition{
+			KeyName:  indexKey.SortKey.KeyName,
+			Operator: common_models.DynamodbSortKeyEqual,
+			Values:   []interface{}{indexKey.SortKey.Value},
+		},
+		ProjectedAttributes: projectedAttributes,
+		IsConsistentRead:    isConsistentRead,
+	}
+	return repository.queryAllPages(ctx, query)
+}
+
+func (repository *dynamodbBaseRepository) queryAllPages(ctx *common_models.LambdaContext, query common_models.DynamodbQuery) ([]map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+	items := make([]map[string]types.AttributeValue, 0)
+	for {
+		result, appErr := repository.Query(ctx, query)
+		if appErr != nil {
+			return nil, appErr
 		}
-		updatedTransactItems := append(transactionInput.TransactItems, transactWriteItem)
-		transactionInput.TransactItems = updatedTransactItems
-		ctx.Set(common_constants.WriteTransaction, transactionInput)
-	} else {
-		putItemInput := &dynamodb.PutItemInput{
+		items = append(items, result.Items...)
+		if result.ContinuationToken == "" {
+			return items, nil
+		}
+		query.ContinuationToken = result.ContinuationToken
+	}
+}
+
+func (repository *dynamodbBaseRepository) save(ctx *common_models.LambdaContext, expression expression.Expression, item map[string]types.AttributeValue, isVersionCheck bool) common_errors.GenericApplicationError {
+	if appErr := repository.validateItemKey(item); appErr != nil {
+		return appErr
+	}
+	if appErr := repository.prepareItemForWrite(ctx, item); appErr != nil {
+		return appErr
+	}
+	transactWriteItem := types.TransactWriteItem{
+		Put: &types.Put{
 			TableName:                 aws.String(repository.tableName),
 			ConditionExpression:       expression.Condition(),
 			ExpressionAttributeNames:  expression.Names(),
 			ExpressionAttributeValues: expression.Values(),
 			Item:                      item,
-		}
-		_, err := repository.client.PutItem(ctx, putItemInput)
-		if err != nil {
-			var dynamodbErr *types.ConditionalCheckFailedException
-			if errors.As(err, &dynamodbErr) {
-				return common_errors.NewForbiddenError("item already exists")
-			} else {
-				return common_errors.NewInternalServerError("error while writing into database")
+		},
+	}
+	itemMetadata := common_models.DynamodbTransactWriteItemMetadata{
+		IsVersionCheck: isVersionCheck,
+	}
+	if inTransaction, appErr := appendToWriteTransaction(ctx, transactWriteItem, itemMetadata); inTransaction {
+		return appErr
+	}
+	putItemInput := &dynamodb.PutItemInput{
+		TableName:                 aws.String(repository.tableName),
+		ConditionExpression:       expression.Condition(),
+		ExpressionAttributeNames:  expression.Names(),
+		ExpressionAttributeValues: expression.Values(),
+		Item:                      item,
+	}
+	if repository.blobOffloadConfig != nil {
+		putItemInput.ReturnValues = types.ReturnValueAllOld
+	}
+	putItemOutput, err := repository.client.PutItem(ctx, putItemInput)
+	if err != nil {
+		var dynamodbErr *types.ConditionalCheckFailedException
+		if errors.As(err, &dynamodbErr) {
+			if isVersionCheck {
+				return common_errors.NewPreconditionFailedError("item version is stale")
 			}
+			return common_errors.NewForbiddenError("item already exists")
 		}
+		return common_errors.NewInternalServerError("error while writing into database")
 	}
+	repository.expireReplacedBlobs(ctx, putItemOutput.Attributes, item)
 	return nil
 }
 
-func (repository *dynamodbBaseRepository) findByPrimaryKey(ctx *common_models.LambdaContext, keyValues map[string]types.AttributeValue, isConsistentRead bool) (map[string]types.AttributeValue, common_errors.GenericApplicationError) {
-	if input, exists := ctx.Get(common_constants.ReadTransaction); exists {
-		transactionInput := input.(dynamodb.TransactGetItemsInput)
-		transactGetItem := types.TransactGetItem{
-			Get: &types.Get{
-				TableName: aws.String(repository.tableName),
-				Key:       keyValues,
-			},
+func (repository *dynamodbBaseRepository) update(ctx *common_models.LambdaContext, keyValues map[string]types.AttributeValue, update common_models.DynamodbUpdate) (map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+	if appErr := repository.validatePrimaryKey(keyValues); appErr != nil {
+		return nil, appErr
+	}
+	if appErr := repository.validateEncryptedUpdate(update); appErr != nil {
+		return nil, appErr
+	}
+	if update.ExpectedVersion != nil && repository.versionAttribute == "" {
+		return nil, common_errors.NewInternalServerError("optimistic locking is not enabled for this repository")
+	}
+	update, offloadedAttributes, appErr := repository.offloadUpdateOperations(ctx, keyValues, update)
+	if appErr != nil {
+		return nil, appErr
+	}
+	builtExpression, appErr := buildUpdateExpression(repository.applyAuditToUpdate(ctx, repository.applyVersionToUpdate(update)))
+	if appErr != nil {
+		return nil, appErr
+	}
+	isVersionCheck := repository.versionAttribute != "" && update.ExpectedVersion != nil
+	transactWriteItem := types.TransactWriteItem{
+		Update: &types.Update{
+			TableName:                 aws.String(repository.tableName),
+			UpdateExpression:          builtExpression.Update(),
+			ConditionExpression:       builtExpression.Condition(),
+			ExpressionAttributeNames:  builtExpression.Names(),
+			ExpressionAttributeValues: builtExpression.Values(),
+			Key:                       keyValues,
+		},
+	}
+	itemMetadata := common_models.DynamodbTransactWriteItemMetadata{
+		IsVersionCheck: isVersionCheck,
+	}
+	if inTransaction, appErr := appendToWriteTransaction(ctx, transactWriteItem, itemMetadata); inTransaction {
+		return nil, appErr
+	}
+	oldBlobPointers, appErr := repository.findBlobPointers(ctx, keyValues, offloadedAttributes)
+	if appErr != nil {
+		return nil, appErr
+	}
+	updateItemInput := &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(repository.tableName),
+		UpdateExpression:          builtExpression.Update(),
+		ConditionExpression:       builtExpression.Condition(),
+		ExpressionAttributeNames:  builtExpression.Names(),
+		ExpressionAttributeValues: builtExpression.Values(),
+		Key:                       keyValues,
+		ReturnValues:              types.ReturnValueAllNew,
+	}
+	updateItemOutput, err := repository.client.UpdateItem(ctx, updateItemInput)
+	if err != nil {
+		var dynamodbErr *types.ConditionalCheckFailedException
+		if errors.As(err, &dynamodbErr) {
+			if isVersionCheck {
+				return nil, common_errors.NewPreconditionFailedError("item version is stale")
+			}
+			return nil, common_errors.NewForbiddenError("item does not satisfy update condition")
+		}
+		return nil, common_errors.NewInternalServerError("error while updating database")
+	}
+	repository.expireReplacedBlobs(ctx, oldBlobPointers, updateItemOutput.Attributes)
+	if appErr := repository.prepareItemForRead(ctx, updateItemOutput.Attributes); appErr != nil {
+		return nil, appErr
+	}
+	return updateItemOutput.Attributes, nil
+}
+
+func (repository *dynamodbBaseRepository) delete(ctx *common_models.LambdaContext, keyValues map[string]types.AttributeValue, options common_models.DynamodbDeleteOptions) (map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+	if appErr := repository.validatePrimaryKey(keyValues); appErr != nil {
+		return nil, appErr
+	}
+	if repository.softDeleteConfig != nil {
+		return repository.softDelete(ctx, keyValues, options)
+	}
+	builtExpression, appErr := buildConditionExpression(options.Condition)
+	if appErr != nil {
+		return nil, appErr
+	}
+	transactWriteItem := types.TransactWriteItem{
+		Delete: &types.Delete{
+			TableName:                 aws.String(repository.tableName),
+			ConditionExpression:       builtExpression.Condition(),
+			ExpressionAttributeNames:  builtExpression.Names(),
+			ExpressionAttributeValues: builtExpression.Values(),
+			Key:                       keyValues,
+		},
+	}
+	if inTransaction, appErr := appendToWriteTransaction(ctx, transactWriteItem, common_models.DynamodbTransactWriteItemMetadata{}); inTransaction {
+		return nil, appErr
+	}
+	deleteItemInput := &dynamodb.DeleteItemInput{
+		TableName:                 aws.String(repository.tableName),
+		ConditionExpression:       builtExpression.Condition(),
+		ExpressionAttributeNames:  builtExpression.Names(),
+		ExpressionAttributeValues: builtExpression.Values(),
+		Key:                       keyValues,
+	}
+	if options.ReturnOldValues || repository.blobOffloadConfig != nil {
+		deleteItemInput.ReturnValues = types.ReturnValueAllOld
+	}
+	deleteItemOutput, err := repository.client.DeleteItem(ctx, deleteItemInput)
+	if err != nil {
+		var dynamodbErr *types.ConditionalCheckFailedException
+		if errors.As(err, &dynamodbErr) {
+			return nil, common_errors.NewForbiddenError("item does not satisfy delete condition")
 		}
-		updatedTransactItems := append(transactionInput.TransactItems, transactGetItem)
-		transactionInput.TransactItems = updatedTransactItems
-		ctx.Set(common_constants.ReadTransaction, transactionInput)
+		return nil, common_errors.NewInternalServerError("error while deleting from database")
+	}
+	oldBlobPointers := extractBlobPointers(deleteItemOutput.Attributes)
+	if !options.ReturnOldValues {
+		repository.expireReplacedBlobs(ctx, oldBlobPointers, nil)
+		return nil, nil
+	}
+	if appErr := repository.prepareItemForRead(ctx, deleteItemOutput.Attributes); appErr != nil {
+		return nil, appErr
+	}
+	repository.expireReplacedBlobs(ctx, oldBlobPointers, nil)
+	return deleteItemOutput.Attributes, nil
+}
+
+func (repository *dynamodbBaseRepository) conditionCheck(ctx *common_models.LambdaContext, keyValues map[string]types.AttributeValue, conditionCheck common_models.DynamodbConditionCheck) common_errors.GenericApplicationError {
+	if appErr := repository.validatePrimaryKey(keyValues); appErr != nil {
+		return appErr
+	}
+	builtExpression, appErr := buildConditionExpression(&conditionCheck.Condition)
+	if appErr != nil {
+		return appErr
+	}
+	transactWriteItem := types.TransactWriteItem{
+		ConditionCheck: &types.ConditionCheck{
+			TableName:                 aws.String(repository.tableName),
+			ConditionExpression:       builtExpression.Condition(),
+			ExpressionAttributeNames:  builtExpression.Names(),
+			ExpressionAttributeValues: builtExpression.Values(),
+			Key:                       keyValues,
+		},
+	}
+	itemMetadata := common_models.DynamodbTransactWriteItemMetadata{
+		Label: conditionCheck.Label,
+	}
+	inTransaction, appErr := appendToWriteTransaction(ctx, transactWriteItem, itemMetadata)
+	if !inTransaction {
+		return common_errors.NewInternalServerError("there is no write transaction in progress")
+	}
+	return appErr
+}
+
+// Inside a read transaction the read is only enqueued and an empty item is returned, so callers of the deprecated
+// ExecuteReadTransaction keep working. New code should use the Find*InReadTransaction handles instead.
+func (repository *dynamodbBaseRepository) findByPrimaryKey(ctx *common_models.LambdaContext, keyValues map[string]types.AttributeValue, isConsistentRead bool) (map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+	if appErr := repository.validatePrimaryKey(keyValues); appErr != nil {
+		return nil, appErr
+	}
+	if _, exists := appendToReadTransaction(ctx, repository.buildTransactGetItem(keyValues), func(item map[string]types.AttributeValue) (map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+		return repository.prepareTransactionItemForRead(ctx, item)
+	}); exists {
+		return map[string]types.AttributeValue{}, nil
+	}
+	getItemInput := &dynamodb.GetItemInput{
+		TableName:      aws.String(repository.tableName),
+		ConsistentRead: aws.Bool(isConsistentRead),
+		Key:            keyValues,
+	}
+	itemOutput, err := repository.client.GetItem(ctx, getItemInput)
+	if err != nil {
+		return nil, common_errors.NewInternalServerError("error while reading from database")
+	}
+	if repository.isHiddenItem(itemOutput.Item) {
 		return map[string]types.AttributeValue{}, nil
-	} else {
-		getItemInput := &dynamodb.GetItemInput{
-			TableName:      aws.String(repository.tableName),
-			ConsistentRead: aws.Bool(isConsistentRead),
-			Key:            keyValues,
+	}
+	if appErr := repository.prepareItemForRead(ctx, itemOutput.Item); appErr != nil {
+		return nil, appErr
+	}
+	return itemOutput.Item, nil
+}
+
+func (repository *dynamodbBaseRepository) findByPrimaryKeyInto(ctx *common_models.LambdaContext, keyValues map[string]types.AttributeValue, isConsistentRead bool, dest interface{}) (bool, common_errors.GenericApplicationError) {
+	if ctx.Exists(common_constants.ReadTransaction) {
+		return false, common_errors.NewInternalServerError("typed finders cannot be used inside a read transaction, use read handles instead")
+	}
+	item, appErr := repository.findByPrimaryKey(ctx, keyValues, isConsistentRead)
+	if appErr != nil {
+		return false, appErr
+	}
+	if len(item) == 0 {
+		return false, nil
+	}
+	if appErr := repository.DecodeItem(item, dest); appErr != nil {
+		return false, appErr
+	}
+	return true, nil
+}
+
+func (repository *dynamodbBaseRepository) findByPrimaryKeyInReadTransaction(ctx *common_models.LambdaContext, keyValues map[string]types.AttributeValue) (common_models.DynamodbReadHandle, common_errors.GenericApplicationError) {
+	if appErr := repository.validatePrimaryKey(keyValues); appErr != nil {
+		return common_models.DynamodbReadHandle{}, appErr
+	}
+	handle, exists := appendToReadTransaction(ctx, repository.buildTransactGetItem(keyValues), func(item map[string]types.AttributeValue) (map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+		return repository.prepareTransactionItemForRead(ctx, item)
+	})
+	if !exists {
+		return common_models.DynamodbReadHandle{}, common_errors.NewInternalServerError("there is no read transaction in progress")
+	}
+	return handle, nil
+}
+
+func (repository *dynamodbBaseRepository) buildTransactGetItem(keyValues map[string]types.AttributeValue) types.TransactGetItem {
+	return types.TransactGetItem{
+		Get: &types.Get{
+			TableName: aws.String(repository.tableName),
+			Key:       keyValues,
+		},
+	}
+}
+
+func validateSecondaryIndex(index *common_models.DynamodbSecondaryIndex, isConsistentRead bool) common_errors.GenericApplicationError {
+	if index == nil {
+		return nil
+	}
+	if index.IndexName == "" {
+		return common_errors.NewInternalServerError("index name is required to read a secondary index")
+	}
+	if index.IsGlobal && isConsistentRead {
+		return common_errors.NewInternalServerError("consistent reads are not supported on global secondary indexes")
+	}
+	return nil
+}
+
+func (repository *dynamodbBaseRepository) expandProjectedAttributes(projectedAttributes []string) []string {
+	return repository.expandProjectedOffloadedAttributes(repository.expandProjectedEncryptedAttributes(projectedAttributes))
+}
+
+func buildProjection(projectedAttributes []string) expression.ProjectionBuilder {
+	names := make([]expression.NameBuilder, 0, len(projectedAttributes))
+	for _, attribute := range projectedAttributes[1:] {
+		names = append(names, expression.Name(attribute))
+	}
+	return expression.NamesList(expression.Name(projectedAttributes[0]), names...)
+}
+
+func buildKeyCondition(query common_models.DynamodbQuery) (expression.KeyConditionBuilder, common_errors.GenericApplicationError) {
+	keyCondition := expression.Key(query.PartitionKey.KeyName).Equal(expression.Value(query.PartitionKey.Value))
+	if query.SortKeyCondition == nil {
+		return keyCondition, nil
+	}
+	sortKeyCondition, appErr := buildSortKeyCondition(*query.SortKeyCondition)
+	if appErr != nil {
+		return expression.KeyConditionBuilder{}, appErr
+	}
+	return keyCondition.And(sortKeyCondition), nil
+}
+
+func buildSortKeyCondition(condition common_models.DynamodbSortKeyCondition) (expression.KeyConditionBuilder, common_errors.GenericApplicationError) {
+	key := expression.Key(condition.KeyName)
+	if condition.Operator == common_models.DynamodbSortKeyBetween {
+		if len(condition.Values) != 2 {
+			return expression.KeyConditionBuilder{}, common_errors.NewInternalServerError("between sort key condition requires two values")
+		}
+		return key.Between(expression.Value(condition.Values[0]), expression.Value(condition.Values[1])), nil
+	}
+	if len(condition.Values) != 1 {
+		return expression.KeyConditionBuilder{}, common_errors.NewInternalServerError("sort key condition requires exactly one value")
+	}
+	value := expression.Value(condition.Values[0])
+	switch condition.Operator {
+	case common_models.DynamodbSortKeyEqual:
+		return key.Equal(value), nil
+	case common_models.DynamodbSortKeyLessThan:
+		return key.LessThan(value), nil
+	case common_models.DynamodbSortKeyLessThanOrEqual:
+		return key.LessThanEqual(value), nil
+	case common_models.DynamodbSortKeyGreaterThan:
+		return key.GreaterThan(value), nil
+	case common_models.DynamodbSortKeyGreaterThanOrEqual:
+		return key.GreaterThanEqual(value), nil
+	case common_models.DynamodbSortKeyBeginsWith:
+		prefix, ok := condition.Values[0].(string)
+		if !ok {
+			return expression.KeyConditionBuilder{}, common_errors.NewInternalServerError("begins with sort key condition requires a string prefix")
+		}
+		return key.BeginsWith(prefix), nil
+	default:
+		return expression.KeyConditionBuilder{}, common_errors.NewInternalServerError("unsupported sort key condition operator")
+	}
+}
+
+func (repository *dynamodbBaseRepository) initializeVersion(item map[string]types.AttributeValue) {
+	if repository.versionAttribute != "" {
+		item[repository.versionAttribute] = &types.AttributeValueMemberN{Value: "1"}
+	}
+}
+
+func (repository *dynamodbBaseRepository) incrementVersion(item map[string]types.AttributeValue) (expression.ConditionBuilder, common_errors.GenericApplicationError) {
+	versionName := expression.Name(repository.versionAttribute)
+	currentVersion, appErr := readVersion(item[repository.versionAttribute])
+	if appErr != nil {
+		return expression.ConditionBuilder{}, appErr
+	}
+	item[repository.versionAttribute] = &types.AttributeValueMemberN{Value: strconv.FormatInt(currentVersion+1, 10)}
+	if currentVersion == 0 {
+		return expression.AttributeNotExists(versionName), nil
+	}
+	return versionName.Equal(expression.Value(currentVersion)), nil
+}
+
+func (repository *dynamodbBaseRepository) applyVersionToUpdate(update common_models.DynamodbUpdate) common_models.DynamodbUpdate {
+	if repository.versionAttribute == "" {
+		return update
+	}
+	operations := make([]common_models.DynamodbUpdateOperation, 0, len(update.Operations)+1)
+	operations = append(operations, update.Operations...)
+	operations = append(operations, common_models.DynamodbUpdateOperation{
+		Action:        common_models.DynamodbUpdateAdd,
+		AttributeName: repository.versionAttribute,
+		Value:         1,
+	})
+	versionedUpdate := common_models.DynamodbUpdate{
+		Operations:      operations,
+		Condition:       update.Condition,
+		ExpectedVersion: update.ExpectedVersion,
+	}
+	if update.ExpectedVersion != nil {
+		versionCondition := expression.Name(repository.versionAttribute).Equal(expression.Value(*update.ExpectedVersion))
+		if update.Condition != nil {
+			versionCondition = versionCondition.And(*update.Condition)
 		}
-		itemOutput, err := repository.client.GetItem(ctx, getItemInput)
+		versionedUpdate.Condition = &versionCondition
+	}
+	return versionedUpdate
+}
+
+func readVersion(value types.AttributeValue) (int64, common_errors.GenericApplicationError) {
+	switch typedValue := value.(type) {
+	case nil, *types.AttributeValueMemberNULL:
+		return 0, nil
+	case *types.AttributeValueMemberN:
+		version, err := strconv.ParseInt(typedValue.Value, 10, 64)
 		if err != nil {
-			return nil, common_errors.NewInternalServerError("error while reading from database")
+			return 0, common_errors.NewInternalServerError("version attribute must be an integer")
+		}
+		return version, nil
+	default:
+		return 0, common_errors.NewInternalServerError("version attribute must be an integer")
+	}
+}
+
+func marshalSimplePrimaryKey(primaryKey common_models.DynamodbSimplePrimaryKey) (map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+	value, err := attributevalue.Marshal(primaryKey.Value)
+	if err != nil {
+		return nil, common_errors.NewInternalServerError("error while marshaling database primary key")
+	}
+	return map[string]types.AttributeValue{
+		primaryKey.KeyName: value,
+	}, nil
+}
+
+func marshalComplexPrimaryKey(primaryKey common_models.DynamodbComplexPrimaryKey) (map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+	partitionKeyValue, err := attributevalue.Marshal(primaryKey.PartitionKey.Value)
+	if err != nil {
+		return nil, common_errors.NewInternalServerError("error while marshaling database partition key")
+	}
+	sortKeyValue, err := attributevalue.Marshal(primaryKey.SortKey.Value)
+	if err != nil {
+		return nil, common_errors.NewInternalServerError("error while marshaling database sort key")
+	}
+	return map[string]types.AttributeValue{
+		primaryKey.PartitionKey.KeyName: partitionKeyValue,
+		primaryKey.SortKey.KeyName:      sortKeyValue,
+	}, nil
+}
+
+func buildConditionExpression(condition *expression.ConditionBuilder) (expression.Expression, common_errors.GenericApplicationError) {
+	if condition == nil {
+		return expression.Expression{}, nil
+	}
+	builtExpression, err := expression.NewBuilder().WithCondition(*condition).Build()
+	if err != nil {
+		return expression.Expression{}, common_errors.NewInternalServerError("error while building condition expression")
+	}
+	return builtExpression, nil
+}
+
+func buildUpdateExpression(update common_models.DynamodbUpdate) (expression.Expression, common_errors.GenericApplicationError) {
+	if len(update.Operations) == 0 {
+		return expression.Expression{}, common_errors.NewInternalServerError("update requires at least one operation")
+	}
+	updateBuilder := expression.UpdateBuilder{}
+	for _, operation := range update.Operations {
+		name := expression.Name(operation.AttributeName)
+		value := expression.Value(wrapAttributeValue(operation.Value))
+		switch operation.Action {
+		case common_models.DynamodbUpdateSet:
+			updateBuilder = updateBuilder.Set(name, value)
+		case common_models.DynamodbUpdateSetIfNotExists:
+			updateBuilder = updateBuilder.Set(name, expression.IfNotExists(name, value))
+		case common_models.DynamodbUpdateListAppend:
+			emptyList := expression.Value([]interface{}{})
+			updateBuilder = updateBuilder.Set(name, expression.ListAppend(expression.IfNotExists(name, emptyList), value))
+		case common_models.DynamodbUpdateRemove:
+			updateBuilder = updateBuilder.Remove(name)
+		case common_models.DynamodbUpdateAdd:
+			updateBuilder = updateBuilder.Add(name, value)
+		case common_models.DynamodbUpdateDelete:
+			updateBuilder = updateBuilder.Delete(name, value)
+		default:
+			return expression.Expression{}, common_errors.NewInternalServerError("unsupported update action")
+		}
+	}
+	expressionBuilder := expression.NewBuilder().WithUpdate(updateBuilder)
+	if update.Condition != nil {
+		expressionBuilder = expressionBuilder.WithCondition(*update.Condition)
+	}
+	builtExpression, err := expressionBuilder.Build()
+	if err != nil {
+		return expression.Expression{}, common_errors.NewInternalServerError("error while building update expression")
+	}
+	return builtExpression, nil
+}
+
+type attributeValueMarshaler struct {
+	value types.AttributeValue
+}
+
+func (marshaler attributeValueMarshaler) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return marshaler.value, nil
+}
+
+func wrapAttributeValue(value interface{}) interface{} {
+	if attributeValue, ok := value.(types.AttributeValue); ok {
+		return attributeValueMarshaler{value: attributeValue}
+	}
+	return value
+}
+
+func (repository *dynamodbBaseRepository) isHiddenItem(item map[string]types.AttributeValue) bool {
+	return repository.isSoftDeleted(item) || repository.isExpired(item)
+}
+
+func (repository *dynamodbBaseRepository) excludeHiddenItems(items []map[string]types.AttributeValue) []map[string]types.AttributeValue {
+	if !repository.isHidingDeleted() && !repository.isFilteringExpired() {
+		return items
+	}
+	visibleItems := make([]map[string]types.AttributeValue, 0, len(items))
+	for _, item := range items {
+		if !repository.isHiddenItem(item) {
+			visibleItems = append(visibleItems, item)
+		}
+	}
+	return visibleItems
+}
+
+func (repository *dynamodbBaseRepository) prepareItemForWrite(ctx *common_models.LambdaContext, item map[string]types.AttributeValue) common_errors.GenericApplicationError {
+	repository.applyAuditToItem(ctx, item)
+	if appErr := repository.compressAttributes(item); appErr != nil {
+		return appErr
+	}
+	if appErr := repository.encryptAttributes(ctx, item); appErr != nil {
+		return appErr
+	}
+	return repository.offloadLargeAttributes(ctx, item)
+}
+
+func (repository *dynamodbBaseRepository) prepareItemForRead(ctx *common_models.LambdaContext, item map[string]types.AttributeValue) common_errors.GenericApplicationError {
+	return repository.prepareStoredItem(ctx, item, false)
+}
+
+func (repository *dynamodbBaseRepository) prepareStoredItem(ctx *common_models.LambdaContext, item map[string]types.AttributeValue, isMissingBlobTolerated bool) common_errors.GenericApplicationError {
+	if appErr := repository.rehydrateLargeAttributes(ctx, item, isMissingBlobTolerated); appErr != nil {
+		return appErr
+	}
+	if appErr := repository.decryptAttributes(ctx, item); appErr != nil {
+		return appErr
+	}
+	return repository.decompressAttributes(item)
+}
+
+// Runs the read pipeline on a stream image in place, so stream processors can decode items of this table. Offloaded
+// attributes whose blob already expired are left out of old images.
+func (repository *dynamodbBaseRepository) PrepareStreamImage(ctx *common_models.LambdaContext, imageName common_models.DynamodbStreamImageName, image map[string]types.AttributeValue) common_errors.GenericApplicationError {
+	return repository.prepareStoredItem(ctx, image, imageName == common_models.DynamodbStreamOldImage)
+}
+
+func (repository *dynamodbBaseRepository) prepareTransactionItemForRead(ctx *common_models.LambdaContext, item map[string]types.AttributeValue) (map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+	if len(item) == 0 || repository.isHiddenItem(item) {
+		return nil, nil
+	}
+	if appErr := repository.prepareItemForRead(ctx, item); appErr != nil {
+		return nil, appErr
+	}
+	return item, nil
+}
+
+func (repository *dynamodbBaseRepository) prepareItemsForRead(ctx *common_models.LambdaContext, items []map[string]types.AttributeValue) common_errors.GenericApplicationError {
+	for _, item := range items {
+		if appErr := repository.prepareItemForRead(ctx, item); appErr != nil {
+			return appErr
 		}
-		return itemOutput.Item, nil
 	}
+	return nil
 }
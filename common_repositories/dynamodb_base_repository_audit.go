@@ -0,0 +1,99 @@
+package common_repositories
+
+import (
+	"github.com/Drathveloper/lambda_commons/v2/common_constants"
+	"github.com/Drathveloper/lambda_commons/v2/common_models"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"time"
+)
+
+const (
+	defaultCreatedAtAttribute = "createdAt"
+	defaultUpdatedAtAttribute = "updatedAt"
+	defaultCreatedByAttribute = "createdBy"
+	defaultUpdatedByAttribute = "updatedBy"
+	defaultAuditPrincipal     = "system"
+	zeroAuditTimestamp        = "0001-01-01T00:00:00Z"
+)
+
+func newAuditConfig(config common_models.DynamodbAuditConfig) *common_models.DynamodbAuditConfig {
+	if config.CreatedAtAttribute == "" {
+		config.CreatedAtAttribute = defaultCreatedAtAttribute
+	}
+	if config.UpdatedAtAttribute == "" {
+		config.UpdatedAtAttribute = defaultUpdatedAtAttribute
+	}
+	if config.CreatedByAttribute == "" {
+		config.CreatedByAttribute = defaultCreatedByAttribute
+	}
+	if config.UpdatedByAttribute == "" {
+		config.UpdatedByAttribute = defaultUpdatedByAttribute
+	}
+	if config.DefaultPrincipal == "" {
+		config.DefaultPrincipal = defaultAuditPrincipal
+	}
+	if config.Clock == nil {
+		config.Clock = time.Now
+	}
+	return &config
+}
+
+// Puts replace the whole stored item, so creation stamps are only kept when the saved item carries them, as after a
+// read-modify-write. Blind overwrites restamp them; use the update methods to keep them on the server side.
+func (repository *dynamodbBaseRepository) applyAuditToItem(ctx *common_models.LambdaContext, item map[string]types.AttributeValue) {
+	if repository.auditConfig == nil {
+		return
+	}
+	timestamp, principal := repository.auditStamp(ctx)
+	if isZeroAuditValue(item[repository.auditConfig.CreatedAtAttribute]) {
+		item[repository.auditConfig.CreatedAtAttribute] = &types.AttributeValueMemberS{Value: timestamp}
+	}
+	if isZeroAuditValue(item[repository.auditConfig.CreatedByAttribute]) {
+		item[repository.auditConfig.CreatedByAttribute] = &types.AttributeValueMemberS{Value: principal}
+	}
+	item[repository.auditConfig.UpdatedAtAttribute] = &types.AttributeValueMemberS{Value: timestamp}
+	item[repository.auditConfig.UpdatedByAttribute] = &types.AttributeValueMemberS{Value: principal}
+}
+
+func (repository *dynamodbBaseRepository) applyAuditToUpdate(ctx *common_models.LambdaContext, update common_models.DynamodbUpdate) common_models.DynamodbUpdate {
+	if repository.auditConfig == nil {
+		return update
+	}
+	timestamp, principal := repository.auditStamp(ctx)
+	operations := make([]common_models.DynamodbUpdateOperation, 0, len(update.Operations)+4)
+	operations = append(operations, update.Operations...)
+	operations = append(operations,
+		common_models.DynamodbUpdateOperation{Action: common_models.DynamodbUpdateSetIfNotExists, AttributeName: repository.auditConfig.CreatedAtAttribute, Value: timestamp},
+		common_models.DynamodbUpdateOperation{Action: common_models.DynamodbUpdateSetIfNotExists, AttributeName: repository.auditConfig.CreatedByAttribute, Value: principal},
+		common_models.DynamodbUpdateOperation{Action: common_models.DynamodbUpdateSet, AttributeName: repository.auditConfig.UpdatedAtAttribute, Value: timestamp},
+		common_models.DynamodbUpdateOperation{Action: common_models.DynamodbUpdateSet, AttributeName: repository.auditConfig.UpdatedByAttribute, Value: principal},
+	)
+	return common_models.DynamodbUpdate{
+		Operations:      operations,
+		Condition:       update.Condition,
+		ExpectedVersion: update.ExpectedVersion,
+	}
+}
+
+func (repository *dynamodbBaseRepository) auditStamp(ctx *common_models.LambdaContext) (string, string) {
+	timestamp := repository.auditConfig.Clock().UTC().Format(time.RFC3339Nano)
+	principal := repository.auditConfig.DefaultPrincipal
+	if value, exists := ctx.Get(common_constants.AuditPrincipal); exists {
+		if contextPrincipal, isString := value.(string); isString && contextPrincipal != "" {
+			principal = contextPrincipal
+		}
+	}
+	return timestamp, principal
+}
+
+// Unset struct fields marshal as an empty string, a NULL or the zero time, none of which is a real creation stamp.
+func isZeroAuditValue(value types.AttributeValue) bool {
+	switch typedValue := value.(type) {
+	case nil, *types.AttributeValueMemberNULL:
+		return true
+	case *types.AttributeValueMemberS:
+		return typedValue.Value == "" || typedValue.Value == zeroAuditTimestamp
+	default:
+		return false
+	}
+}
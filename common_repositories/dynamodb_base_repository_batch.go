@@ -0,0 +1,357 @@
+package common_repositories
+
+import (
+	"encoding/base64"
+	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/Drathveloper/lambda_commons/v2/common_helpers"
+	"github.com/Drathveloper/lambda_commons/v2/common_models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"reflect"
+	"sync"
+)
+
+const (
+	maxBatchGetItems   = 100
+	maxBatchWriteItems = 25
+)
+
+func (repository *dynamodbBaseRepository) BatchGetBySimplePrimaryKeys(ctx *common_models.LambdaContext, primaryKeys []common_models.DynamodbSimplePrimaryKey, isConsistentRead bool) (map[common_models.DynamodbSimplePrimaryKey]map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+	requestedKeys := make(map[string]common_models.DynamodbSimplePrimaryKey, len(primaryKeys))
+	keyNames := make([]string, 0)
+	keyValues := make([]map[string]types.AttributeValue, 0, len(primaryKeys))
+	for _, primaryKey := range primaryKeys {
+		resultKey := primaryKey
+		var isComparable bool
+		if resultKey.Value, isComparable = toComparableKeyValue(primaryKey.Value); !isComparable {
+			return nil, common_errors.NewInternalServerError("batch primary key values must be comparable")
+		}
+		marshaledKey, appErr := marshalSimplePrimaryKey(primaryKey)
+		if appErr != nil {
+			return nil, appErr
+		}
+		canonicalKey, appErr := canonicalizeKey(marshaledKey)
+		if appErr != nil {
+			return nil, appErr
+		}
+		if _, exists := requestedKeys[canonicalKey]; !exists {
+			requestedKeys[canonicalKey] = resultKey
+			keyValues = append(keyValues, marshaledKey)
+			keyNames = []string{primaryKey.KeyName}
+		}
+	}
+	items, appErr := repository.batchGet(ctx, keyValues, isConsistentRead)
+	if appErr != nil {
+		return nil, appErr
+	}
+	result := make(map[common_models.DynamodbSimplePrimaryKey]map[string]types.AttributeValue, len(items))
+	for _, item := range items {
+		canonicalKey, appErr := canonicalizeKey(extractKey(item, keyNames))
+		if appErr != nil {
+			return nil, appErr
+		}
+		if primaryKey, exists := requestedKeys[canonicalKey]; exists {
+			result[primaryKey] = item
+		}
+	}
+	return result, nil
+}
+
+func (repository *dynamodbBaseRepository) BatchGetByComplexPrimaryKeys(ctx *common_models.LambdaContext, primaryKeys []common_models.DynamodbComplexPrimaryKey, isConsistentRead bool) (map[common_models.DynamodbComplexPrimaryKey]map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+	requestedKeys := make(map[string]common_models.DynamodbComplexPrimaryKey, len(primaryKeys))
+	keyNames := make([]string, 0)
+	keyValues := make([]map[string]types.AttributeValue, 0, len(primaryKeys))
+	for _, primaryKey := range primaryKeys {
+		resultKey := primaryKey
+		var isPartitionKeyComparable, isSortKeyComparable bool
+		resultKey.PartitionKey.Value, isPartitionKeyComparable = toComparableKeyValue(primaryKey.PartitionKey.Value)
+		resultKey.SortKey.Value, isSortKeyComparable = toComparableKeyValue(primaryKey.SortKey.Value)
+		if !isPartitionKeyComparable || !isSortKeyComparable {
+			return nil, common_errors.NewInternalServerError("batch primary key values must be comparable")
+		}
+		marshaledKey, appErr := marshalComplexPrimaryKey(primaryKey)
+		if appErr != nil {
+			return nil, appErr
+		}
+		canonicalKey, appErr := canonicalizeKey(marshaledKey)
+		if appErr != nil {
+			return nil, appErr
+		}
+		if _, exists := requestedKeys[canonicalKey]; !exists {
+			requestedKeys[canonicalKey] = resultKey
+			keyValues = append(keyValues, marshaledKey)
+			keyNames = []string{primaryKey.PartitionKey.KeyName, primaryKey.SortKey.KeyName}
+		}
+	}
+	items, appErr := repository.batchGet(ctx, keyValues, isConsistentRead)
+	if appErr != nil {
+		return nil, appErr
+	}
+	result := make(map[common_models.DynamodbComplexPrimaryKey]map[string]types.AttributeValue, len(items))
+	for _, item := range items {
+		canonicalKey, appErr := canonicalizeKey(extractKey(item, keyNames))
+		if appErr != nil {
+			return nil, appErr
+		}
+		if primaryKey, exists := requestedKeys[canonicalKey]; exists {
+			result[primaryKey] = item
+		}
+	}
+	return result, nil
+}
+
+func (repository *dynamodbBaseRepository) BatchSave(ctx *common_models.LambdaContext, items []interface{}) common_errors.GenericApplicationError {
+	if repository.versionAttribute != "" {
+		return common_errors.NewInternalServerError("batch writes are not supported with optimistic locking")
+	}
+	if isInWriteTransaction(ctx) {
+		return newBatchWriteInTransactionError()
+	}
+	writeRequests := make([]types.WriteRequest, 0, len(items))
+	for _, item := range items {
+		itemAttributeValue, appErr := repository.marshalItem(item)
+		if appErr != nil {
+			return appErr
+		}
+		if appErr := repository.prepareItemForWrite(ctx, itemAttributeValue); appErr != nil {
+			return appErr
+		}
+		writeRequests = append(writeRequests, types.WriteRequest{
+			PutRequest: &types.PutRequest{Item: itemAttributeValue},
+		})
+	}
+	return repository.batchWrite(ctx, writeRequests)
+}
+
+func (repository *dynamodbBaseRepository) BatchDeleteBySimplePrimaryKeys(ctx *common_models.LambdaContext, primaryKeys []common_models.DynamodbSimplePrimaryKey) common_errors.GenericApplicationError {
+	if repository.softDeleteConfig != nil {
+		return common_errors.NewInternalServerError("batch deletes are not supported with soft delete")
+	}
+	writeRequests := make([]types.WriteRequest, 0, len(primaryKeys))
+	for _, primaryKey := range primaryKeys {
+		keyValues, appErr := marshalSimplePrimaryKey(primaryKey)
+		if appErr != nil {
+			return appErr
+		}
+		writeRequests = append(writeRequests, types.WriteRequest{
+			DeleteRequest: &types.DeleteRequest{Key: keyValues},
+		})
+	}
+	return repository.batchWrite(ctx, writeRequests)
+}
+
+func (repository *dynamodbBaseRepository) BatchDeleteByComplexPrimaryKeys(ctx *common_models.LambdaContext, primaryKeys []common_models.DynamodbComplexPrimaryKey) common_errors.GenericApplicationError {
+	if repository.softDeleteConfig != nil {
+		return common_errors.NewInternalServerError("batch deletes are not supported with soft delete")
+	}
+	writeRequests := make([]types.WriteRequest, 0, len(primaryKeys))
+	for _, primaryKey := range primaryKeys {
+		keyValues, appErr := marshalComplexPrimaryKey(primaryKey)
+		if appErr != nil {
+			return appErr
+		}
+		writeRequests = append(writeRequests, types.WriteRequest{
+			DeleteRequest: &types.DeleteRequest{Key: keyValues},
+		})
+	}
+	return repository.batchWrite(ctx, writeRequests)
+}
+
+func (repository *dynamodbBaseRepository) batchGet(ctx *common_models.LambdaContext, keyValues []map[string]types.AttributeValue, isConsistentRead bool) ([]map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+	for _, keyValue := range keyValues {
+		if appErr := repository.validatePrimaryKey(keyValue); appErr != nil {
+			return nil, appErr
+		}
+	}
+	items, appErr := repository.batchGetItems(ctx, keyValues, types.KeysAndAttributes{ConsistentRead: aws.Bool(isConsistentRead)})
+	if appErr != nil {
+		return nil, appErr
+	}
+	visibleItems := repository.excludeHiddenItems(items)
+	if appErr := repository.prepareItemsForRead(ctx, visibleItems); appErr != nil {
+		return nil, appErr
+	}
+	return visibleItems, nil
+}
+
+// Reads the given keys in parallel chunks without running the read pipeline. The template carries the read options
+// shared by every chunk.
+func (repository *dynamodbBaseRepository) batchGetItems(ctx *common_models.LambdaContext, keyValues []map[string]types.AttributeValue, template types.KeysAndAttributes) ([]map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+	chunks := make([][]map[string]types.AttributeValue, 0)
+	for start := 0; start < len(keyValues); start += maxBatchGetItems {
+		end := start + maxBatchGetItems
+		if end > len(keyValues) {
+			end = len(keyValues)
+		}
+		chunks = append(chunks, keyValues[start:end])
+	}
+	chunkItems := make([][]map[string]types.AttributeValue, len(chunks))
+	chunkErrors := make([]common_errors.GenericApplicationError, len(chunks))
+	var waitGroup sync.WaitGroup
+	for index, chunk := range chunks {
+		waitGroup.Add(1)
+		go func(index int, chunk []map[string]types.AttributeValue) {
+			defer waitGroup.Done()
+			chunkItems[index], chunkErrors[index] = repository.batchGetChunk(ctx, chunk, template)
+		}(index, chunk)
+	}
+	waitGroup.Wait()
+	items := make([]map[string]types.AttributeValue, 0, len(keyValues))
+	for index := range chunks {
+		if chunkErrors[index] != nil {
+			return nil, chunkErrors[index]
+		}
+		items = append(items, chunkItems[index]...)
+	}
+	return items, nil
+}
+
+func (repository *dynamodbBaseRepository) batchGetChunk(ctx *common_models.LambdaContext, keyValues []map[string]types.AttributeValue, template types.KeysAndAttributes) ([]map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+	items := make([]map[string]types.AttributeValue, 0, len(keyValues))
+	template.Keys = keyValues
+	requestItems := map[string]types.KeysAndAttributes{
+		repository.tableName: template,
+	}
+	for attempt := 0; ; attempt++ {
+		batchGetItemOutput, err := repository.client.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{
+			RequestItems: requestItems,
+		})
+		if err != nil {
+			return nil, common_errors.NewInternalServerError("error while batch reading from database")
+		}
+		items = append(items, batchGetItemOutput.Responses[repository.tableName]...)
+		if len(batchGetItemOutput.UnprocessedKeys[repository.tableName].Keys) == 0 {
+			return items, nil
+		}
+		if !common_helpers.WaitForRetry(ctx, repository.batchRetryPolicy, attempt) {
+			return nil, common_errors.NewInternalServerError("unprocessed keys remaining after batch reading from database")
+		}
+		requestItems = batchGetItemOutput.UnprocessedKeys
+	}
+}
+
+func (repository *dynamodbBaseRepository) batchWrite(ctx *common_models.LambdaContext, writeRequests []types.WriteRequest) common_errors.GenericApplicationError {
+	if isInWriteTransaction(ctx) {
+		return newBatchWriteInTransactionError()
+	}
+	for _, writeRequest := range writeRequests {
+		var appErr common_errors.GenericApplicationError
+		if writeRequest.PutRequest != nil {
+			appErr = repository.validateItemKey(writeRequest.PutRequest.Item)
+		} else if writeRequest.DeleteRequest != nil {
+			appErr = repository.validatePrimaryKey(writeRequest.DeleteRequest.Key)
+		}
+		if appErr != nil {
+			return appErr
+		}
+	}
+	if appErr := repository.validateUniqueWriteKeys(writeRequests); appErr != nil {
+		return appErr
+	}
+	blobPointers, appErr := repository.findBatchBlobPointers(ctx, writeRequests)
+	if appErr != nil {
+		return appErr
+	}
+	chunks := make([][]types.WriteRequest, 0)
+	for start := 0; start < len(writeRequests); start += maxBatchWriteItems {
+		end := start + maxBatchWriteItems
+		if end > len(writeRequests) {
+			end = len(writeRequests)
+		}
+		chunks = append(chunks, writeRequests[start:end])
+	}
+	chunkErrors := make([]common_errors.GenericApplicationError, len(chunks))
+	var waitGroup sync.WaitGroup
+	for index, chunk := range chunks {
+		waitGroup.Add(1)
+		go func(index int, chunk []types.WriteRequest) {
+			defer waitGroup.Done()
+			chunkErrors[index] = repository.batchWriteChunk(ctx, chunk)
+		}(index, chunk)
+	}
+	waitGroup.Wait()
+	for _, appErr := range chunkErrors {
+		if appErr != nil {
+			return appErr
+		}
+	}
+	repository.expireBatchReplacedBlobs(ctx, writeRequests, blobPointers)
+	return nil
+}
+
+func (repository *dynamodbBaseRepository) batchWriteChunk(ctx *common_models.LambdaContext, writeRequests []types.WriteRequest) common_errors.GenericApplicationError {
+	requestItems := map[string][]types.WriteRequest{
+		repository.tableName: writeRequests,
+	}
+	for attempt := 0; ; attempt++ {
+		batchWriteItemOutput, err := repository.client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: requestItems,
+		})
+		if err != nil {
+			return common_errors.NewInternalServerError("error while batch writing into database")
+		}
+		if len(batchWriteItemOutput.UnprocessedItems[repository.tableName]) == 0 {
+			return nil
+		}
+		if !common_helpers.WaitForRetry(ctx, repository.batchRetryPolicy, attempt) {
+			return common_errors.NewInternalServerError("unprocessed items remaining after batch writing into database")
+		}
+		requestItems = batchWriteItemOutput.UnprocessedItems
+	}
+}
+
+// Batch writes bypass the write transaction, so running them inside one would silently commit outside of it.
+func newBatchWriteInTransactionError() common_errors.GenericApplicationError {
+	return common_errors.NewInternalServerError("batch writes cannot take part in a write transaction")
+}
+
+// BatchWriteItem rejects a whole request that touches the same key twice. Put keys can only be checked when the
+// repository knows its table schema.
+func (repository *dynamodbBaseRepository) validateUniqueWriteKeys(writeRequests []types.WriteRequest) common_errors.GenericApplicationError {
+	seenKeys := make(map[string]bool, len(writeRequests))
+	for _, writeRequest := range writeRequests {
+		var keyValues map[string]types.AttributeValue
+		if writeRequest.DeleteRequest != nil {
+			keyValues = writeRequest.DeleteRequest.Key
+		} else if writeRequest.PutRequest != nil && repository.tableSchema != nil {
+			keyValues = extractKey(writeRequest.PutRequest.Item, repository.primaryKeyNames())
+		} else {
+			continue
+		}
+		canonicalKey, appErr := canonicalizeKey(keyValues)
+		if appErr != nil {
+			return appErr
+		}
+		if seenKeys[canonicalKey] {
+			return common_errors.NewBadRequestError("batch write contains the same primary key more than once")
+		}
+		seenKeys[canonicalKey] = true
+	}
+	return nil
+}
+
+// Batch get results are keyed by the requested primary keys, so binary key values, which cannot be map keys, are
+// returned base64 encoded.
+func toComparableKeyValue(value interface{}) (interface{}, bool) {
+	if binaryValue, isBinary := value.([]byte); isBinary {
+		return base64.StdEncoding.EncodeToString(binaryValue), true
+	}
+	return value, value == nil || reflect.TypeOf(value).Comparable()
+}
+
+func extractKey(item map[string]types.AttributeValue, keyNames []string) map[string]types.AttributeValue {
+	keyValues := make(map[string]types.AttributeValue, len(keyNames))
+	for _, keyName := range keyNames {
+		keyValues[keyName] = item[keyName]
+	}
+	return keyValues
+}
+
+func canonicalizeKey(keyValues map[string]types.AttributeValue) (string, common_errors.GenericApplicationError) {
+	canonicalKey, err := common_helpers.MarshalAttributeValueMapToJSON(keyValues)
+	if err != nil {
+		return "", common_errors.NewInternalServerError("error while marshaling database primary key")
+	}
+	return string(canonicalKey), nil
+}
@@ -0,0 +1,364 @@
+package common_repositories_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Drathveloper/lambda_commons/v2/common_constants"
+	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/Drathveloper/lambda_commons/v2/common_models"
+	"github.com/Drathveloper/lambda_commons/v2/common_repositories"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/golang/mock/gomock"
+	"time"
+)
+
+func (suite *DynamodbBaseRepositoryTestSuite) newBatchRepository(maxAttempts int) common_repositories.DynamodbBaseRepository {
+	retryPolicy := common_models.RetryPolicy{
+		MaxAttempts: maxAttempts,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    time.Millisecond,
+	}
+	return common_repositories.NewDynamodbBaseRepository(suite.dynamodbClient, "someTable", common_repositories.WithBatchRetryPolicy(retryPolicy))
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestBatchGetBySimplePrimaryKeys_ShouldRetryUnprocessedKeys() {
+	context := common_models.NewLambdaContext()
+	repository := suite.newBatchRepository(3)
+	firstKey := common_models.DynamodbSimplePrimaryKey{KeyName: "pk", Value: "first"}
+	secondKey := common_models.DynamodbSimplePrimaryKey{KeyName: "pk", Value: "second"}
+	firstKeyValues := map[string]types.AttributeValue{"pk": &types.AttributeValueMemberS{Value: "first"}}
+	secondKeyValues := map[string]types.AttributeValue{"pk": &types.AttributeValueMemberS{Value: "second"}}
+	firstItem := map[string]types.AttributeValue{
+		"pk":   &types.AttributeValueMemberS{Value: "first"},
+		"name": &types.AttributeValueMemberS{Value: "someName"},
+	}
+	secondItem := map[string]types.AttributeValue{
+		"pk":   &types.AttributeValueMemberS{Value: "second"},
+		"name": &types.AttributeValueMemberS{Value: "anotherName"},
+	}
+	firstInput := dynamodb.BatchGetItemInput{
+		RequestItems: map[string]types.KeysAndAttributes{
+			"someTable": {
+				ConsistentRead: aws.Bool(true),
+				Keys:           []map[string]types.AttributeValue{firstKeyValues, secondKeyValues},
+			},
+		},
+	}
+	unprocessedKeys := map[string]types.KeysAndAttributes{
+		"someTable": {
+			ConsistentRead: aws.Bool(true),
+			Keys:           []map[string]types.AttributeValue{secondKeyValues},
+		},
+	}
+	secondInput := dynamodb.BatchGetItemInput{
+		RequestItems: unprocessedKeys,
+	}
+	expectedResult := map[common_models.DynamodbSimplePrimaryKey]map[string]types.AttributeValue{
+		firstKey:  firstItem,
+		secondKey: secondItem,
+	}
+
+	gomock.InOrder(
+		suite.dynamodbClient.EXPECT().BatchGetItem(&context, &firstInput).Return(&dynamodb.BatchGetItemOutput{
+			Responses:       map[string][]map[string]types.AttributeValue{"someTable": {firstItem}},
+			UnprocessedKeys: unprocessedKeys,
+		}, nil),
+		suite.dynamodbClient.EXPECT().BatchGetItem(&context, &secondInput).Return(&dynamodb.BatchGetItemOutput{
+			Responses: map[string][]map[string]types.AttributeValue{"someTable": {secondItem}},
+		}, nil),
+	)
+
+	result, appErr := repository.BatchGetBySimplePrimaryKeys(&context, []common_models.DynamodbSimplePrimaryKey{firstKey, secondKey, firstKey}, true)
+
+	suite.NoError(appErr)
+	suite.Equal(expectedResult, result)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestBatchGetByComplexPrimaryKeys_ShouldSplitRequestsInChunks() {
+	context := common_models.NewLambdaContext()
+	repository := suite.newBatchRepository(3)
+	primaryKeys := make([]common_models.DynamodbComplexPrimaryKey, 0)
+	for index := 0; index < 150; index++ {
+		primaryKeys = append(primaryKeys, common_models.DynamodbComplexPrimaryKey{
+			PartitionKey: common_models.DynamodbSimplePrimaryKey{KeyName: "pk", Value: "somePartition"},
+			SortKey:      common_models.DynamodbSimplePrimaryKey{KeyName: "sk", Value: index},
+		})
+	}
+	requestSizes := make(chan int, 2)
+
+	suite.dynamodbClient.EXPECT().BatchGetItem(&context, gomock.Any()).Times(2).DoAndReturn(
+		func(_ *common_models.LambdaContext, input *dynamodb.BatchGetItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error) {
+			keys := input.RequestItems["someTable"].Keys
+			requestSizes <- len(keys)
+			return &dynamodb.BatchGetItemOutput{
+				Responses: map[string][]map[string]types.AttributeValue{"someTable": keys},
+			}, nil
+		})
+
+	result, appErr := repository.BatchGetByComplexPrimaryKeys(&context, primaryKeys, false)
+
+	suite.NoError(appErr)
+	suite.Len(result, 150)
+	suite.ElementsMatch([]int{100, 50}, []int{<-requestSizes, <-requestSizes})
+	suite.Equal(&types.AttributeValueMemberN{Value: "42"}, result[primaryKeys[42]]["sk"])
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestBatchGetBySimplePrimaryKeys_ShouldReturnInternalServerErrorWhenBatchGetItemFailed() {
+	context := common_models.NewLambdaContext()
+	repository := suite.newBatchRepository(3)
+	primaryKeys := []common_models.DynamodbSimplePrimaryKey{{KeyName: "pk", Value: "first"}}
+	cause := errors.New("someErr")
+	expectedAppErr := common_errors.NewInternalServerError("error while batch reading from database")
+
+	suite.dynamodbClient.EXPECT().BatchGetItem(&context, gomock.Any()).Return(nil, cause)
+
+	_, appErr := repository.BatchGetBySimplePrimaryKeys(&context, primaryKeys, false)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestBatchGetBySimplePrimaryKeys_ShouldReturnInternalServerErrorWhenKeyIsNotComparable() {
+	context := common_models.NewLambdaContext()
+	primaryKeys := []common_models.DynamodbSimplePrimaryKey{{KeyName: "pk", Value: []string{"first"}}}
+	expectedAppErr := common_errors.NewInternalServerError("batch primary key values must be comparable")
+
+	_, appErr := suite.baseRepository.BatchGetBySimplePrimaryKeys(&context, primaryKeys, false)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestBatchGetBySimplePrimaryKeys_ShouldKeyBinaryValuesByTheirBase64Encoding() {
+	context := common_models.NewLambdaContext()
+	primaryKeys := []common_models.DynamodbSimplePrimaryKey{{KeyName: "pk", Value: []byte{0x01, 0x02}}}
+	item := map[string]types.AttributeValue{
+		"pk":   &types.AttributeValueMemberB{Value: []byte{0x01, 0x02}},
+		"name": &types.AttributeValueMemberS{Value: "someName"},
+	}
+	expectedResult := map[common_models.DynamodbSimplePrimaryKey]map[string]types.AttributeValue{
+		{KeyName: "pk", Value: "AQI="}: item,
+	}
+
+	suite.dynamodbClient.EXPECT().BatchGetItem(&context, gomock.Any()).DoAndReturn(func(_ *common_models.LambdaContext, input *dynamodb.BatchGetItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error) {
+		suite.Equal([]map[string]types.AttributeValue{{"pk": &types.AttributeValueMemberB{Value: []byte{0x01, 0x02}}}}, input.RequestItems["someTable"].Keys)
+		return &dynamodb.BatchGetItemOutput{
+			Responses: map[string][]map[string]types.AttributeValue{"someTable": {item}},
+		}, nil
+	})
+
+	result, appErr := suite.baseRepository.BatchGetBySimplePrimaryKeys(&context, primaryKeys, false)
+
+	suite.NoError(appErr)
+	suite.Equal(expectedResult, result)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestBatchSave_ShouldSplitRequestsInChunks() {
+	context := common_models.NewLambdaContext()
+	repository := suite.newBatchRepository(3)
+	items := make([]interface{}, 0)
+	for index := 0; index < 30; index++ {
+		items = append(items, DummyItem{Key1: fmt.Sprintf("key%d", index), Key2: "bar"})
+	}
+	requestSizes := make(chan int, 2)
+
+	suite.dynamodbClient.EXPECT().BatchWriteItem(&context, gomock.Any()).Times(2).DoAndReturn(
+		func(_ *common_models.LambdaContext, input *dynamodb.BatchWriteItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+			requestSizes <- len(input.RequestItems["someTable"])
+			return &dynamodb.BatchWriteItemOutput{}, nil
+		})
+
+	appErr := repository.BatchSave(&context, items)
+
+	suite.NoError(appErr)
+	suite.ElementsMatch([]int{25, 5}, []int{<-requestSizes, <-requestSizes})
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestBatchSave_ShouldReturnInternalServerErrorWhenUnprocessedItemsRemain() {
+	context := common_models.NewLambdaContext()
+	repository := suite.newBatchRepository(2)
+	items := []interface{}{DummyItem{Key1: "foo", Key2: "bar"}}
+	unprocessedItems := map[string][]types.WriteRequest{
+		"someTable": {
+			{
+				PutRequest: &types.PutRequest{
+					Item: map[string]types.AttributeValue{
+						"key1": &types.AttributeValueMemberS{Value: "foo"},
+						"key2": &types.AttributeValueMemberS{Value: "bar"},
+					},
+				},
+			},
+		},
+	}
+	expectedAppErr := common_errors.NewInternalServerError("unprocessed items remaining after batch writing into database")
+
+	suite.dynamodbClient.EXPECT().BatchWriteItem(&context, &dynamodb.BatchWriteItemInput{RequestItems: unprocessedItems}).Times(2).Return(&dynamodb.BatchWriteItemOutput{
+		UnprocessedItems: unprocessedItems,
+	}, nil)
+
+	appErr := repository.BatchSave(&context, items)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestBatchSave_ShouldReturnInternalServerErrorWhenVersioned() {
+	context := common_models.NewLambdaContext()
+	items := []interface{}{VersionedDummyItem{Key1: "foo"}}
+	expectedAppErr := common_errors.NewInternalServerError("batch writes are not supported with optimistic locking")
+
+	appErr := suite.versionedRepository.BatchSave(&context, items)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestBatchDeleteByComplexPrimaryKeys_ShouldSucceed() {
+	context := common_models.NewLambdaContext()
+	primaryKeys := []common_models.DynamodbComplexPrimaryKey{
+		{
+			PartitionKey: common_models.DynamodbSimplePrimaryKey{KeyName: "pk", Value: "somePartition"},
+			SortKey:      common_models.DynamodbSimplePrimaryKey{KeyName: "sk", Value: "someSort"},
+		},
+	}
+	batchWriteItemInput := dynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]types.WriteRequest{
+			"someTable": {
+				{
+					DeleteRequest: &types.DeleteRequest{
+						Key: map[string]types.AttributeValue{
+							"pk": &types.AttributeValueMemberS{Value: "somePartition"},
+							"sk": &types.AttributeValueMemberS{Value: "someSort"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	suite.dynamodbClient.EXPECT().BatchWriteItem(&context, &batchWriteItemInput).Return(&dynamodb.BatchWriteItemOutput{}, nil)
+
+	appErr := suite.baseRepository.BatchDeleteByComplexPrimaryKeys(&context, primaryKeys)
+
+	suite.NoError(appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestBatchDeleteBySimplePrimaryKeys_ShouldReturnInternalServerErrorWhenBatchWriteItemFailed() {
+	context := common_models.NewLambdaContext()
+	primaryKeys := []common_models.DynamodbSimplePrimaryKey{{KeyName: "pk", Value: "first"}}
+	cause := errors.New("someErr")
+	expectedAppErr := common_errors.NewInternalServerError("error while batch writing into database")
+
+	suite.dynamodbClient.EXPECT().BatchWriteItem(&context, gomock.Any()).Return(nil, cause)
+
+	appErr := suite.baseRepository.BatchDeleteBySimplePrimaryKeys(&context, primaryKeys)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestBatchSave_ShouldReturnInternalServerErrorWhenInWriteTransaction() {
+	context := common_models.NewLambdaContext()
+	items := []interface{}{DummyItem{Key1: "foo", Key2: "bar"}}
+	expectedAppErr := common_errors.NewInternalServerError("batch writes cannot take part in a write transaction")
+	context.Set(common_constants.WriteTransaction, dynamodb.TransactWriteItemsInput{})
+
+	appErr := suite.baseRepository.BatchSave(&context, items)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestBatchDeleteBySimplePrimaryKeys_ShouldReturnInternalServerErrorWhenInWriteTransaction() {
+	context := common_models.NewLambdaContext()
+	primaryKeys := []common_models.DynamodbSimplePrimaryKey{{KeyName: "pk", Value: "first"}}
+	expectedAppErr := common_errors.NewInternalServerError("batch writes cannot take part in a write transaction")
+	context.Set(common_constants.WriteTransaction, dynamodb.TransactWriteItemsInput{})
+
+	appErr := suite.baseRepository.BatchDeleteBySimplePrimaryKeys(&context, primaryKeys)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestBatchDeleteBySimplePrimaryKeys_ShouldReturnBadRequestErrorWhenKeyIsDuplicated() {
+	context := common_models.NewLambdaContext()
+	primaryKeys := []common_models.DynamodbSimplePrimaryKey{{KeyName: "pk", Value: "first"}, {KeyName: "pk", Value: "first"}}
+	expectedAppErr := common_errors.NewBadRequestError("batch write contains the same primary key more than once")
+
+	appErr := suite.baseRepository.BatchDeleteBySimplePrimaryKeys(&context, primaryKeys)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestBatchSave_ShouldReturnBadRequestErrorWhenKeyIsDuplicated() {
+	context := common_models.NewLambdaContext()
+	items := []interface{}{
+		SchemaDummyItem{Key1: "foo", Key2: "bar", Value: "first"},
+		SchemaDummyItem{Key1: "foo", Key2: "bar", Value: "second"},
+	}
+	expectedAppErr := common_errors.NewBadRequestError("batch write contains the same primary key more than once")
+
+	appErr := suite.schemaRepository.BatchSave(&context, items)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestBatchSave_ShouldExpireReplacedBlobs() {
+	context := common_models.NewLambdaContext()
+	blobData := []byte(`{"payload":{"S":"abcdefghij"}}`)
+	var blobKey string
+	oldItem := map[string]types.AttributeValue{
+		"key1":                  &types.AttributeValueMemberS{Value: "foo"},
+		"__blobPointer#payload": &types.AttributeValueMemberS{Value: "blobs/someTable/oldBlob"},
+	}
+
+	suite.expectPutBlob(&context, blobData, &blobKey)
+	suite.dynamodbClient.EXPECT().BatchGetItem(&context, gomock.Any()).DoAndReturn(func(_ *common_models.LambdaContext, input *dynamodb.BatchGetItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error) {
+		suite.True(*input.RequestItems["someTable"].ConsistentRead)
+		suite.Equal([]map[string]types.AttributeValue{{"key1": &types.AttributeValueMemberS{Value: "foo"}}}, input.RequestItems["someTable"].Keys)
+		suite.Equal(map[string]string{"#0": "key1", "#1": "__blobPointer#payload"}, input.RequestItems["someTable"].ExpressionAttributeNames)
+		return &dynamodb.BatchGetItemOutput{
+			Responses: map[string][]map[string]types.AttributeValue{"someTable": {oldItem}},
+		}, nil
+	})
+	suite.dynamodbClient.EXPECT().BatchWriteItem(&context, gomock.Any()).Return(&dynamodb.BatchWriteItemOutput{}, nil)
+	suite.blobStore.EXPECT().ExpireBlob(&context, "blobs/someTable/oldBlob", offloadBlobExpiresAt).Return(nil)
+
+	appErr := suite.offloadRepository.BatchSave(&context, []interface{}{map[string]string{"key1": "foo", "payload": "abcdefghij"}})
+
+	suite.NoError(appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestBatchDeleteBySimplePrimaryKeys_ShouldExpireOffloadedBlobs() {
+	context := common_models.NewLambdaContext()
+	primaryKeys := []common_models.DynamodbSimplePrimaryKey{{KeyName: "key1", Value: "foo"}, {KeyName: "key1", Value: "bar"}}
+	oldItem := map[string]types.AttributeValue{
+		"key1":                  &types.AttributeValueMemberS{Value: "foo"},
+		"__blobPointer#payload": &types.AttributeValueMemberS{Value: "blobs/someTable/oldBlob"},
+	}
+
+	suite.dynamodbClient.EXPECT().BatchGetItem(&context, gomock.Any()).Return(&dynamodb.BatchGetItemOutput{
+		Responses: map[string][]map[string]types.AttributeValue{"someTable": {oldItem}},
+	}, nil)
+	suite.dynamodbClient.EXPECT().BatchWriteItem(&context, gomock.Any()).Return(&dynamodb.BatchWriteItemOutput{}, nil)
+	suite.blobStore.EXPECT().ExpireBlob(&context, "blobs/someTable/oldBlob", offloadBlobExpiresAt).Return(nil)
+
+	appErr := suite.offloadRepository.BatchDeleteBySimplePrimaryKeys(&context, primaryKeys)
+
+	suite.NoError(appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestBatchDeleteBySimplePrimaryKeys_ShouldKeepOffloadedBlobsWhenBatchWriteItemFailed() {
+	context := common_models.NewLambdaContext()
+	primaryKeys := []common_models.DynamodbSimplePrimaryKey{{KeyName: "key1", Value: "foo"}}
+	oldItem := map[string]types.AttributeValue{
+		"key1":                  &types.AttributeValueMemberS{Value: "foo"},
+		"__blobPointer#payload": &types.AttributeValueMemberS{Value: "blobs/someTable/oldBlob"},
+	}
+	expectedAppErr := common_errors.NewInternalServerError("error while batch writing into database")
+
+	suite.dynamodbClient.EXPECT().BatchGetItem(&context, gomock.Any()).Return(&dynamodb.BatchGetItemOutput{
+		Responses: map[string][]map[string]types.AttributeValue{"someTable": {oldItem}},
+	}, nil)
+	suite.dynamodbClient.EXPECT().BatchWriteItem(&context, gomock.Any()).Return(nil, errors.New("someErr"))
+
+	appErr := suite.offloadRepository.BatchDeleteBySimplePrimaryKeys(&context, primaryKeys)
+
+	suite.Equal(expectedAppErr, appErr)
+}
@@ -0,0 +1,326 @@
+package common_repositories
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/Drathveloper/lambda_commons/v2/common_helpers"
+	"github.com/Drathveloper/lambda_commons/v2/common_models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+const (
+	defaultBlobOffloadSizeThreshold    = 100 * 1024
+	defaultBlobExpirationDelay         = 48 * time.Hour
+	dynamodbBlobPointerAttributePrefix = "__blobPointer#"
+)
+
+// The default expiration delay outlives the 24 hour stream retention, so stream images keep resolving their blobs.
+func newBlobOffloadConfig(config common_models.DynamodbBlobOffloadConfig) *common_models.DynamodbBlobOffloadConfig {
+	if config.SizeThreshold <= 0 {
+		config.SizeThreshold = defaultBlobOffloadSizeThreshold
+	}
+	if config.ExpirationDelay <= 0 {
+		config.ExpirationDelay = defaultBlobExpirationDelay
+	}
+	if config.Clock == nil {
+		config.Clock = time.Now
+	}
+	return &config
+}
+
+// An offloaded attribute is removed from the item and its blob key is stored in a sidecar attribute, so user values
+// are never mistaken for pointers.
+func blobPointerAttribute(attributeName string) string {
+	return dynamodbBlobPointerAttributePrefix + attributeName
+}
+
+func (repository *dynamodbBaseRepository) offloadLargeAttributes(ctx *common_models.LambdaContext, item map[string]types.AttributeValue) common_errors.GenericApplicationError {
+	if repository.blobOffloadConfig == nil {
+		return nil
+	}
+	for _, attributeName := range repository.blobOffloadConfig.Attributes {
+		value, exists := item[attributeName]
+		if !exists || common_helpers.EstimateAttributeValueSize(value) <= repository.blobOffloadConfig.SizeThreshold {
+			continue
+		}
+		blobKey, appErr := repository.storeBlob(ctx, item, attributeName, value)
+		if appErr != nil {
+			return appErr
+		}
+		delete(item, attributeName)
+		item[blobPointerAttribute(attributeName)] = &types.AttributeValueMemberS{Value: blobKey}
+	}
+	return nil
+}
+
+// Old stream images may point at blobs that expired after the item was replaced; with isMissingBlobTolerated those
+// attributes are left out instead of failing the record.
+func (repository *dynamodbBaseRepository) rehydrateLargeAttributes(ctx *common_models.LambdaContext, item map[string]types.AttributeValue, isMissingBlobTolerated bool) common_errors.GenericApplicationError {
+	if repository.blobOffloadConfig == nil {
+		return nil
+	}
+	for _, attributeName := range repository.blobOffloadConfig.Attributes {
+		blobKey, isPointer := item[blobPointerAttribute(attributeName)].(*types.AttributeValueMemberS)
+		if !isPointer {
+			continue
+		}
+		data, err := repository.blobOffloadConfig.BlobStore.GetBlob(ctx, blobKey.Value)
+		if err != nil && isMissingBlobTolerated && errors.Is(err, common_models.ErrBlobNotFound) {
+			delete(item, blobPointerAttribute(attributeName))
+			continue
+		}
+		if err != nil {
+			return common_errors.NewInternalServerError("error while loading large attribute")
+		}
+		blobItem, err := common_helpers.UnmarshalAttributeValueMapFromJSON(data)
+		if err != nil || blobItem[attributeName] == nil {
+			return common_errors.NewInternalServerError("error while decoding large attribute")
+		}
+		item[attributeName] = blobItem[attributeName]
+		delete(item, blobPointerAttribute(attributeName))
+	}
+	return nil
+}
+
+// Every write stores its blob under a fresh key scoped to the item key and the attribute name, so a blob is referenced
+// by at most one item version and expiring it once that version is replaced cannot affect concurrent writers.
+func (repository *dynamodbBaseRepository) storeBlob(ctx *common_models.LambdaContext, item map[string]types.AttributeValue, attributeName string, value types.AttributeValue) (string, common_errors.GenericApplicationError) {
+	canonicalKey, appErr := repository.canonicalizeBlobOffloadKey(item)
+	if appErr != nil {
+		return "", appErr
+	}
+	data, err := common_helpers.MarshalAttributeValueMapToJSON(map[string]types.AttributeValue{attributeName: value})
+	if err != nil {
+		return "", common_errors.NewInternalServerError("error while marshaling large attribute")
+	}
+	keyChecksum := sha256.Sum256([]byte(canonicalKey))
+	blobKey := repository.blobOffloadConfig.KeyPrefix + repository.tableName + "/" + hex.EncodeToString(keyChecksum[:]) + "/" + attributeName + "/" + uuid.NewString()
+	if err := repository.blobOffloadConfig.BlobStore.PutBlob(ctx, blobKey, data); err != nil {
+		return "", common_errors.NewInternalServerError("error while storing large attribute")
+	}
+	return blobKey, nil
+}
+
+func (repository *dynamodbBaseRepository) blobOffloadKeyNames() ([]string, common_errors.GenericApplicationError) {
+	keyNames := repository.blobOffloadConfig.KeyAttributes
+	if len(keyNames) == 0 && repository.tableSchema != nil {
+		keyNames = repository.primaryKeyNames()
+	}
+	if len(keyNames) == 0 {
+		return nil, common_errors.NewInternalServerError("blob offload requires the primary key attributes")
+	}
+	return keyNames, nil
+}
+
+func (repository *dynamodbBaseRepository) canonicalizeBlobOffloadKey(item map[string]types.AttributeValue) (string, common_errors.GenericApplicationError) {
+	keyNames, appErr := repository.blobOffloadKeyNames()
+	if appErr != nil {
+		return "", appErr
+	}
+	for _, keyName := range keyNames {
+		if _, exists := item[keyName]; !exists {
+			return "", common_errors.NewInternalServerError(fmt.Sprintf("offloaded item does not contain key attribute %s", keyName))
+		}
+	}
+	return canonicalizeKey(extractKey(item, keyNames))
+}
+
+// Rewrites set and remove operations on offloaded attributes so large values go to the blob store and the sidecar
+// pointer always matches the stored value. Other actions cannot be applied to a value that may live in the blob store.
+func (repository *dynamodbBaseRepository) offloadUpdateOperations(ctx *common_models.LambdaContext, keyValues map[string]types.AttributeValue, update common_models.DynamodbUpdate) (common_models.DynamodbUpdate, []string, common_errors.GenericApplicationError) {
+	if repository.blobOffloadConfig == nil {
+		return update, nil, nil
+	}
+	operations := make([]common_models.DynamodbUpdateOperation, 0, len(update.Operations))
+	offloadedAttributes := make([]string, 0)
+	for _, operation := range update.Operations {
+		if !containsString(repository.blobOffloadConfig.Attributes, operation.AttributeName) {
+			operations = append(operations, operation)
+			continue
+		}
+		offloadedAttributes = append(offloadedAttributes, operation.AttributeName)
+		pointerAttribute := blobPointerAttribute(operation.AttributeName)
+		switch operation.Action {
+		case common_models.DynamodbUpdateSet:
+			value, err := attributevalue.Marshal(wrapAttributeValue(operation.Value))
+			if err != nil {
+				return update, nil, common_errors.NewInternalServerError("error while marshaling large attribute")
+			}
+			if common_helpers.EstimateAttributeValueSize(value) <= repository.blobOffloadConfig.SizeThreshold {
+				operations = append(operations, operation, removeOperation(pointerAttribute))
+				continue
+			}
+			blobKey, appErr := repository.storeBlob(ctx, keyValues, operation.AttributeName, value)
+			if appErr != nil {
+				return update, nil, appErr
+			}
+			operations = append(operations, removeOperation(operation.AttributeName), common_models.DynamodbUpdateOperation{
+				Action:        common_models.DynamodbUpdateSet,
+				AttributeName: pointerAttribute,
+				Value:         &types.AttributeValueMemberS{Value: blobKey},
+			})
+		case common_models.DynamodbUpdateRemove:
+			operations = append(operations, operation, removeOperation(pointerAttribute))
+		default:
+			return update, nil, common_errors.NewInternalServerError(fmt.Sprintf("offloaded attribute %s only supports set and remove updates", operation.AttributeName))
+		}
+	}
+	update.Operations = operations
+	return update, offloadedAttributes, nil
+}
+
+func removeOperation(attributeName string) common_models.DynamodbUpdateOperation {
+	return common_models.DynamodbUpdateOperation{
+		Action:        common_models.DynamodbUpdateRemove,
+		AttributeName: attributeName,
+	}
+}
+
+// Reads the current blob pointers of the given attributes so the blobs they reference can be expired once an update
+// replaces them. A concurrent update between this read and the write can leave its blob unexpired, which only wastes
+// storage.
+func (repository *dynamodbBaseRepository) findBlobPointers(ctx *common_models.LambdaContext, keyValues map[string]types.AttributeValue, attributeNames []string) (map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+	if len(attributeNames) == 0 {
+		return nil, nil
+	}
+	pointerAttributes := make([]string, 0, len(attributeNames))
+	for _, attributeName := range attributeNames {
+		pointerAttributes = append(pointerAttributes, blobPointerAttribute(attributeName))
+	}
+	builtExpression, err := expression.NewBuilder().WithProjection(buildProjection(pointerAttributes)).Build()
+	if err != nil {
+		return nil, common_errors.NewInternalServerError("error while building projection expression")
+	}
+	getItemOutput, err := repository.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:                aws.String(repository.tableName),
+		Key:                      keyValues,
+		ProjectionExpression:     builtExpression.Projection(),
+		ExpressionAttributeNames: builtExpression.Names(),
+		ConsistentRead:           aws.Bool(true),
+	})
+	if err != nil {
+		return nil, common_errors.NewInternalServerError("error while reading from database")
+	}
+	return getItemOutput.Item, nil
+}
+
+// Replaced blobs are only scheduled for expiration, since stream records and readers that fetched the old item may
+// still resolve them. Expiring is best effort: the item write already succeeded, and a leftover blob is only wasted
+// storage. Writes enqueued in transactions and soft deletes keep their blobs, since the outcome is not known here.
+func (repository *dynamodbBaseRepository) expireReplacedBlobs(ctx *common_models.LambdaContext, oldItem map[string]types.AttributeValue, newItem map[string]types.AttributeValue) {
+	if repository.blobOffloadConfig == nil {
+		return
+	}
+	expiresAt := repository.blobOffloadConfig.Clock().Add(repository.blobOffloadConfig.ExpirationDelay)
+	for _, attributeName := range repository.blobOffloadConfig.Attributes {
+		oldBlobKey, isPointer := oldItem[blobPointerAttribute(attributeName)].(*types.AttributeValueMemberS)
+		if !isPointer {
+			continue
+		}
+		if newBlobKey, isPointer := newItem[blobPointerAttribute(attributeName)].(*types.AttributeValueMemberS); isPointer && newBlobKey.Value == oldBlobKey.Value {
+			continue
+		}
+		_ = repository.blobOffloadConfig.BlobStore.ExpireBlob(ctx, oldBlobKey.Value, expiresAt)
+	}
+}
+
+// Batch writes cannot return the old items, so the blob pointers of every written key are read before the write. As with
+// updates, a concurrent write in between can leave a blob unexpired.
+func (repository *dynamodbBaseRepository) findBatchBlobPointers(ctx *common_models.LambdaContext, writeRequests []types.WriteRequest) (map[string]map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+	if repository.blobOffloadConfig == nil || len(writeRequests) == 0 {
+		return nil, nil
+	}
+	keyNames, appErr := repository.blobOffloadKeyNames()
+	if appErr != nil {
+		return nil, appErr
+	}
+	keyValues := make([]map[string]types.AttributeValue, 0, len(writeRequests))
+	for _, writeRequest := range writeRequests {
+		if writeRequest.DeleteRequest != nil {
+			keyValues = append(keyValues, writeRequest.DeleteRequest.Key)
+			continue
+		}
+		if _, appErr := repository.canonicalizeBlobOffloadKey(writeRequest.PutRequest.Item); appErr != nil {
+			return nil, appErr
+		}
+		keyValues = append(keyValues, extractKey(writeRequest.PutRequest.Item, keyNames))
+	}
+	projectedAttributes := append(make([]string, 0, len(keyNames)+len(repository.blobOffloadConfig.Attributes)), keyNames...)
+	for _, attributeName := range repository.blobOffloadConfig.Attributes {
+		projectedAttributes = append(projectedAttributes, blobPointerAttribute(attributeName))
+	}
+	builtExpression, err := expression.NewBuilder().WithProjection(buildProjection(projectedAttributes)).Build()
+	if err != nil {
+		return nil, common_errors.NewInternalServerError("error while building projection expression")
+	}
+	items, appErr := repository.batchGetItems(ctx, keyValues, types.KeysAndAttributes{
+		ConsistentRead:           aws.Bool(true),
+		ProjectionExpression:     builtExpression.Projection(),
+		ExpressionAttributeNames: builtExpression.Names(),
+	})
+	if appErr != nil {
+		return nil, appErr
+	}
+	blobPointers := make(map[string]map[string]types.AttributeValue, len(items))
+	for _, item := range items {
+		canonicalKey, appErr := repository.canonicalizeBlobOffloadKey(item)
+		if appErr != nil {
+			return nil, appErr
+		}
+		blobPointers[canonicalKey] = extractBlobPointers(item)
+	}
+	return blobPointers, nil
+}
+
+// Only runs once the whole batch was written, since a failed chunk leaves it unknown which items were replaced.
+func (repository *dynamodbBaseRepository) expireBatchReplacedBlobs(ctx *common_models.LambdaContext, writeRequests []types.WriteRequest, blobPointers map[string]map[string]types.AttributeValue) {
+	if repository.blobOffloadConfig == nil {
+		return
+	}
+	for _, writeRequest := range writeRequests {
+		var keyValues, newItem map[string]types.AttributeValue
+		if writeRequest.DeleteRequest != nil {
+			keyValues = writeRequest.DeleteRequest.Key
+		} else {
+			keyValues, newItem = writeRequest.PutRequest.Item, writeRequest.PutRequest.Item
+		}
+		canonicalKey, appErr := repository.canonicalizeBlobOffloadKey(keyValues)
+		if appErr != nil {
+			continue
+		}
+		repository.expireReplacedBlobs(ctx, blobPointers[canonicalKey], newItem)
+	}
+}
+
+func (repository *dynamodbBaseRepository) expandProjectedOffloadedAttributes(projectedAttributes []string) []string {
+	if repository.blobOffloadConfig == nil || len(projectedAttributes) == 0 {
+		return projectedAttributes
+	}
+	expandedAttributes := append(make([]string, 0, len(projectedAttributes)), projectedAttributes...)
+	for _, attributeName := range repository.blobOffloadConfig.Attributes {
+		if containsString(projectedAttributes, attributeName) {
+			expandedAttributes = append(expandedAttributes, blobPointerAttribute(attributeName))
+		}
+	}
+	return expandedAttributes
+}
+
+func extractBlobPointers(item map[string]types.AttributeValue) map[string]types.AttributeValue {
+	pointers := make(map[string]types.AttributeValue)
+	for attributeName, value := range item {
+		if strings.HasPrefix(attributeName, dynamodbBlobPointerAttributePrefix) {
+			pointers[attributeName] = value
+		}
+	}
+	return pointers
+}
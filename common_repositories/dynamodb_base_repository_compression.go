@@ -0,0 +1,71 @@
+package common_repositories
+
+import (
+	"compress/gzip"
+	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/Drathveloper/lambda_commons/v2/common_helpers"
+	"github.com/Drathveloper/lambda_commons/v2/common_models"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const defaultCompressionMinSize = 1024
+
+func newCompressionConfig(config common_models.DynamodbCompressionConfig) *common_models.DynamodbCompressionConfig {
+	if config.Codec == nil {
+		config.Codec = common_helpers.NewGzipCodec(gzip.DefaultCompression)
+	}
+	if config.MinSize <= 0 {
+		config.MinSize = defaultCompressionMinSize
+	}
+	return &config
+}
+
+func (repository *dynamodbBaseRepository) compressAttributes(item map[string]types.AttributeValue) common_errors.GenericApplicationError {
+	if repository.compressionConfig == nil {
+		return nil
+	}
+	for _, attributeName := range repository.compressionConfig.Attributes {
+		value, exists := item[attributeName]
+		if !exists {
+			continue
+		}
+		data, err := common_helpers.MarshalAttributeValueMapToJSON(map[string]types.AttributeValue{attributeName: value})
+		if err != nil {
+			return common_errors.NewInternalServerError("error while marshaling compressed attribute")
+		}
+		if len(data) < repository.compressionConfig.MinSize {
+			continue
+		}
+		compressed, err := common_helpers.CompressWithHeader(repository.compressionConfig.Codec, data)
+		if err != nil {
+			return common_errors.NewInternalServerError("error while compressing attribute")
+		}
+		item[attributeName] = &types.AttributeValueMemberB{Value: compressed}
+	}
+	return nil
+}
+
+func (repository *dynamodbBaseRepository) decompressAttributes(item map[string]types.AttributeValue) common_errors.GenericApplicationError {
+	if repository.compressionConfig == nil {
+		return nil
+	}
+	for _, attributeName := range repository.compressionConfig.Attributes {
+		value, isBinary := item[attributeName].(*types.AttributeValueMemberB)
+		if !isBinary {
+			continue
+		}
+		data, isCompressed, err := common_helpers.DecompressWithHeader(value.Value, repository.compressionConfig.Codec, common_helpers.NewGzipCodec(gzip.DefaultCompression), common_helpers.NewZstdCodec(common_helpers.ZstdDefaultCompression))
+		if err != nil {
+			return common_errors.NewInternalServerError("error while decompressing attribute")
+		}
+		if !isCompressed {
+			continue
+		}
+		decompressedItem, err := common_helpers.UnmarshalAttributeValueMapFromJSON(data)
+		if err != nil || decompressedItem[attributeName] == nil {
+			return common_errors.NewInternalServerError("error while decompressing attribute")
+		}
+		item[attributeName] = decompressedItem[attributeName]
+	}
+	return nil
+}
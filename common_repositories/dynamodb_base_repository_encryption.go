@@ -0,0 +1,164 @@
+package common_repositories
+
+import (
+	"fmt"
+	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/Drathveloper/lambda_commons/v2/common_helpers"
+	"github.com/Drathveloper/lambda_commons/v2/common_models"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const dynamodbEncryptedDataKeyAttribute = "__encryptedDataKey"
+
+func (repository *dynamodbBaseRepository) encryptAttributes(ctx *common_models.LambdaContext, item map[string]types.AttributeValue) common_errors.GenericApplicationError {
+	attributeNames := repository.presentEncryptedAttributes(item)
+	if len(attributeNames) == 0 {
+		return nil
+	}
+	canonicalKey, appErr := repository.canonicalizeEncryptionKey(item)
+	if appErr != nil {
+		return appErr
+	}
+	dataKey, encryptedDataKey, err := repository.encryptionConfig.KeyProvider.GenerateDataKey(ctx)
+	if err != nil {
+		return common_errors.NewInternalServerError("error while generating data key")
+	}
+	for _, attributeName := range attributeNames {
+		plaintext, err := common_helpers.MarshalAttributeValueMapToJSON(map[string]types.AttributeValue{attributeName: item[attributeName]})
+		if err != nil {
+			return common_errors.NewInternalServerError("error while marshaling encrypted attribute")
+		}
+		ciphertext, err := common_helpers.SealAESGCM(dataKey, plaintext, buildEncryptionContext(canonicalKey, attributeName))
+		if err != nil {
+			return common_errors.NewInternalServerError("error while encrypting attribute")
+		}
+		item[attributeName] = &types.AttributeValueMemberB{Value: ciphertext}
+	}
+	item[dynamodbEncryptedDataKeyAttribute] = &types.AttributeValueMemberB{Value: encryptedDataKey}
+	return nil
+}
+
+func (repository *dynamodbBaseRepository) decryptAttributes(ctx *common_models.LambdaContext, item map[string]types.AttributeValue) common_errors.GenericApplicationError {
+	attributeNames := repository.presentEncryptedAttributes(item)
+	if len(attributeNames) == 0 {
+		return nil
+	}
+	encryptedDataKey, exists := item[dynamodbEncryptedDataKeyAttribute].(*types.AttributeValueMemberB)
+	if !exists {
+		return common_errors.NewInternalServerError("encrypted item does not contain a data key")
+	}
+	canonicalKey, appErr := repository.canonicalizeEncryptionKey(item)
+	if appErr != nil {
+		return appErr
+	}
+	dataKey, err := repository.encryptionConfig.KeyProvider.DecryptDataKey(ctx, encryptedDataKey.Value)
+	if err != nil {
+		return common_errors.NewInternalServerError("error while decrypting data key")
+	}
+	for _, attributeName := range attributeNames {
+		ciphertext, isBinary := item[attributeName].(*types.AttributeValueMemberB)
+		if !isBinary {
+			return common_errors.NewInternalServerError(fmt.Sprintf("attribute %s is not encrypted", attributeName))
+		}
+		plaintext, err := common_helpers.OpenAESGCM(dataKey, ciphertext.Value, buildEncryptionContext(canonicalKey, attributeName))
+		if err != nil {
+			return common_errors.NewInternalServerError(fmt.Sprintf("error while decrypting attribute %s", attributeName))
+		}
+		decryptedItem, err := common_helpers.UnmarshalAttributeValueMapFromJSON(plaintext)
+		if err != nil || decryptedItem[attributeName] == nil {
+			return common_errors.NewInternalServerError(fmt.Sprintf("error while decrypting attribute %s", attributeName))
+		}
+		item[attributeName] = decryptedItem[attributeName]
+	}
+	delete(item, dynamodbEncryptedDataKeyAttribute)
+	return nil
+}
+
+func (repository *dynamodbBaseRepository) validateEncryptedUpdate(update common_models.DynamodbUpdate) common_errors.GenericApplicationError {
+	if repository.encryptionConfig == nil {
+		return nil
+	}
+	for _, operation := range update.Operations {
+		if containsString(repository.encryptedAttributes(), operation.AttributeName) {
+			return common_errors.NewInternalServerError(fmt.Sprintf("encrypted attribute %s cannot be updated in place", operation.AttributeName))
+		}
+	}
+	return nil
+}
+
+func (repository *dynamodbBaseRepository) encryptedAttributes() []string {
+	attributeNames := make([]string, 0, len(repository.encryptionConfig.Attributes))
+	attributeNames = append(attributeNames, repository.encryptionConfig.Attributes...)
+	if repository.tableSchema != nil {
+		for _, attributeName := range repository.tableSchema.EncryptedAttributes {
+			if !containsString(attributeNames, attributeName) {
+				attributeNames = append(attributeNames, attributeName)
+			}
+		}
+	}
+	return attributeNames
+}
+
+// Decrypting a projected attribute needs the data key and the key attributes bound into the encryption context, so
+// they are projected as well whenever an encrypted attribute is.
+func (repository *dynamodbBaseRepository) expandProjectedEncryptedAttributes(projectedAttributes []string) []string {
+	if !repository.isProjectingEncryptedAttribute(projectedAttributes) {
+		return projectedAttributes
+	}
+	keyNames := repository.encryptionConfig.KeyAttributes
+	if len(keyNames) == 0 && repository.tableSchema != nil {
+		keyNames = repository.primaryKeyNames()
+	}
+	expandedAttributes := append(make([]string, 0, len(projectedAttributes)+len(keyNames)+1), projectedAttributes...)
+	for _, attributeName := range append([]string{dynamodbEncryptedDataKeyAttribute}, keyNames...) {
+		if !containsString(expandedAttributes, attributeName) {
+			expandedAttributes = append(expandedAttributes, attributeName)
+		}
+	}
+	return expandedAttributes
+}
+
+func (repository *dynamodbBaseRepository) isProjectingEncryptedAttribute(projectedAttributes []string) bool {
+	if repository.encryptionConfig == nil {
+		return false
+	}
+	for _, attributeName := range repository.encryptedAttributes() {
+		if containsString(projectedAttributes, attributeName) {
+			return true
+		}
+	}
+	return false
+}
+
+func (repository *dynamodbBaseRepository) presentEncryptedAttributes(item map[string]types.AttributeValue) []string {
+	if repository.encryptionConfig == nil {
+		return nil
+	}
+	attributeNames := make([]string, 0)
+	for _, attributeName := range repository.encryptedAttributes() {
+		if _, exists := item[attributeName]; exists {
+			attributeNames = append(attributeNames, attributeName)
+		}
+	}
+	return attributeNames
+}
+
+func (repository *dynamodbBaseRepository) canonicalizeEncryptionKey(item map[string]types.AttributeValue) (string, common_errors.GenericApplicationError) {
+	keyNames := repository.encryptionConfig.KeyAttributes
+	if len(keyNames) == 0 && repository.tableSchema != nil {
+		keyNames = repository.primaryKeyNames()
+	}
+	if len(keyNames) == 0 {
+		return "", common_errors.NewInternalServerError("attribute encryption requires the primary key attributes")
+	}
+	for _, keyName := range keyNames {
+		if _, exists := item[keyName]; !exists {
+			return "", common_errors.NewInternalServerError(fmt.Sprintf("encrypted item does not contain key attribute %s", keyName))
+		}
+	}
+	return canonicalizeKey(extractKey(item, keyNames))
+}
+
+func buildEncryptionContext(canonicalKey string, attributeName string) []byte {
+	return []byte(canonicalKey + "#" + attributeName)
+}
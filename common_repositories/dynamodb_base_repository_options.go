@@ -0,0 +1,68 @@
+package common_repositories
+
+import (
+	"github.com/Drathveloper/lambda_commons/v2/common_models"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+)
+
+type DynamodbBaseRepositoryOption func(repository *dynamodbBaseRepository)
+
+func WithVersionAttribute(attributeName string) DynamodbBaseRepositoryOption {
+	return func(repository *dynamodbBaseRepository) {
+		repository.versionAttribute = attributeName
+	}
+}
+
+func WithBatchRetryPolicy(policy common_models.RetryPolicy) DynamodbBaseRepositoryOption {
+	return func(repository *dynamodbBaseRepository) {
+		repository.batchRetryPolicy = policy
+	}
+}
+
+func WithDecoderOptions(optFns ...func(*attributevalue.DecoderOptions)) DynamodbBaseRepositoryOption {
+	return func(repository *dynamodbBaseRepository) {
+		repository.decoderOptions = append(repository.decoderOptions, optFns...)
+	}
+}
+
+func WithTableSchema(schema common_models.DynamodbTableSchema) DynamodbBaseRepositoryOption {
+	return func(repository *dynamodbBaseRepository) {
+		repository.tableSchema = &schema
+	}
+}
+
+func WithAudit(config common_models.DynamodbAuditConfig) DynamodbBaseRepositoryOption {
+	return func(repository *dynamodbBaseRepository) {
+		repository.auditConfig = newAuditConfig(config)
+	}
+}
+
+func WithSoftDelete(config common_models.DynamodbSoftDeleteConfig) DynamodbBaseRepositoryOption {
+	return func(repository *dynamodbBaseRepository) {
+		repository.softDeleteConfig = newSoftDeleteConfig(config)
+	}
+}
+
+func WithTimeToLive(config common_models.DynamodbTimeToLiveConfig) DynamodbBaseRepositoryOption {
+	return func(repository *dynamodbBaseRepository) {
+		repository.timeToLiveConfig = newTimeToLiveConfig(config)
+	}
+}
+
+func WithBlobOffload(config common_models.DynamodbBlobOffloadConfig) DynamodbBaseRepositoryOption {
+	return func(repository *dynamodbBaseRepository) {
+		repository.blobOffloadConfig = newBlobOffloadConfig(config)
+	}
+}
+
+func WithEncryption(config common_models.DynamodbEncryptionConfig) DynamodbBaseRepositoryOption {
+	return func(repository *dynamodbBaseRepository) {
+		repository.encryptionConfig = &config
+	}
+}
+
+func WithCompression(config common_models.DynamodbCompressionConfig) DynamodbBaseRepositoryOption {
+	return func(repository *dynamodbBaseRepository) {
+		repository.compressionConfig = newCompressionConfig(config)
+	}
+}
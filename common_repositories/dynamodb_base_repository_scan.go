@@ -0,0 +1,261 @@
+package common_repositories
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Drathveloper/lambda_commons/v2/common_constants"
+	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/Drathveloper/lambda_commons/v2/common_helpers"
+	"github.com/Drathveloper/lambda_commons/v2/common_models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	maxScanSegments           = 1000000
+	defaultScanConcurrency    = 8
+	defaultScanDeadlineMargin = 10 * time.Second
+	throttlingErrorCode       = "ThrottlingException"
+)
+
+type scanSegmentResult struct {
+	processedItems   int64
+	consumedCapacity float64
+	appErr           common_errors.GenericApplicationError
+}
+
+// Segments are scanned by a bounded pool of workers, so the callback may run concurrently. Every segment gets its own
+// child context, so callbacks can open transactions without sharing state. A segment checkpoint only advances once
+// every item of a page has been handled, so pages interrupted by an error are replayed on resume.
+func (repository *dynamodbBaseRepository) Scan(ctx *common_models.LambdaContext, scan common_models.DynamodbScan, callback common_models.DynamodbScanCallback) (common_models.DynamodbScanResult, common_errors.GenericApplicationError) {
+	if callback == nil {
+		return common_models.DynamodbScanResult{}, common_errors.NewInternalServerError("scan callback is required")
+	}
+	if ctx.Exists(common_constants.ReadTransaction) || ctx.Exists(common_constants.WriteTransaction) {
+		return common_models.DynamodbScanResult{}, common_errors.NewInternalServerError("scan is not supported inside a transaction")
+	}
+	if appErr := validateSecondaryIndex(scan.Index, scan.IsConsistentRead); appErr != nil {
+		return common_models.DynamodbScanResult{}, appErr
+	}
+	checkpoint, appErr := newScanCheckpoint(scan)
+	if appErr != nil {
+		return common_models.DynamodbScanResult{}, appErr
+	}
+	scanInput, appErr := repository.buildScanInput(scan, int32(len(checkpoint.Segments)))
+	if appErr != nil {
+		return common_models.DynamodbScanResult{}, appErr
+	}
+	deadlineMargin := scan.DeadlineMargin
+	if deadlineMargin <= 0 {
+		deadlineMargin = defaultScanDeadlineMargin
+	}
+	concurrency := scan.MaxConcurrentSegments
+	if concurrency <= 0 {
+		concurrency = defaultScanConcurrency
+	}
+	limiter := common_helpers.NewCapacityRateLimiter(scan.MaxCapacityUnitsPerSecond)
+	var isStopped int32
+	segmentResults := make([]scanSegmentResult, len(checkpoint.Segments))
+	pendingSegments := make(chan int)
+	var waitGroup sync.WaitGroup
+	for worker := 0; worker < concurrency; worker++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for index := range pendingSegments {
+				segmentContext := ctx.NewChildContext()
+				segmentContext.Set(common_constants.WriteTransactionScope, fmt.Sprintf("scan-segment-%d", index))
+				segmentResults[index] = repository.scanSegment(&segmentContext, *scanInput, &checkpoint.Segments[index], deadlineMargin, callback, limiter, &isStopped)
+			}
+		}()
+	}
+	for index := range checkpoint.Segments {
+		if atomic.LoadInt32(&isStopped) != 0 {
+			break
+		}
+		if !checkpoint.Segments[index].IsCompleted {
+			pendingSegments <- index
+		}
+	}
+	close(pendingSegments)
+	waitGroup.Wait()
+	result := common_models.DynamodbScanResult{
+		Checkpoint:  checkpoint,
+		IsCompleted: true,
+	}
+	var scanErr common_errors.GenericApplicationError
+	for index, segmentResult := range segmentResults {
+		result.ProcessedItems += segmentResult.processedItems
+		result.ConsumedCapacity += segmentResult.consumedCapacity
+		if !checkpoint.Segments[index].IsCompleted {
+			result.IsCompleted = false
+		}
+		if scanErr == nil {
+			scanErr = segmentResult.appErr
+		}
+	}
+	return result, scanErr
+}
+
+func (repository *dynamodbBaseRepository) scanSegment(ctx *common_models.LambdaContext, scanInput dynamodb.ScanInput, segment *common_models.DynamodbScanSegmentCheckpoint, deadlineMargin time.Duration, callback common_models.DynamodbScanCallback, limiter common_helpers.CapacityRateLimiter, isStopped *int32) scanSegmentResult {
+	result := scanSegmentResult{}
+	scanInput.Segment = aws.Int32(segment.Segment)
+	for !segment.IsCompleted {
+		if atomic.LoadInt32(isStopped) != 0 || !hasTimeForScanPage(ctx, deadlineMargin) {
+			return result
+		}
+		reservedCapacity, isReserved := limiter.Reserve(ctx)
+		if !isReserved {
+			return result
+		}
+		exclusiveStartKey, appErr := common_helpers.DecodeDynamodbContinuationToken(segment.ContinuationToken)
+		if appErr != nil {
+			atomic.StoreInt32(isStopped, 1)
+			result.appErr = appErr
+			return result
+		}
+		scanInput.ExclusiveStartKey = exclusiveStartKey
+		scanOutput, appErr := repository.scanPage(ctx, &scanInput, deadlineMargin)
+		if appErr != nil {
+			atomic.StoreInt32(isStopped, 1)
+			result.appErr = appErr
+			return result
+		}
+		if scanOutput == nil {
+			return result
+		}
+		consumedCapacity := 0.0
+		if scanOutput.ConsumedCapacity != nil && scanOutput.ConsumedCapacity.CapacityUnits != nil {
+			consumedCapacity = *scanOutput.ConsumedCapacity.CapacityUnits
+		}
+		limiter.Settle(reservedCapacity, consumedCapacity)
+		result.consumedCapacity += consumedCapacity
+		if appErr := repository.prepareItemsForRead(ctx, scanOutput.Items); appErr != nil {
+			atomic.StoreInt32(isStopped, 1)
+			result.appErr = appErr
+			return result
+		}
+		for _, item := range scanOutput.Items {
+			if appErr := callback(ctx, item); appErr != nil {
+				atomic.StoreInt32(isStopped, 1)
+				result.appErr = appErr
+				return result
+			}
+			result.processedItems++
+		}
+		continuationToken, appErr := common_helpers.EncodeDynamodbContinuationToken(scanOutput.LastEvaluatedKey)
+		if appErr != nil {
+			atomic.StoreInt32(isStopped, 1)
+			result.appErr = appErr
+			return result
+		}
+		segment.ContinuationToken = continuationToken
+		segment.IsCompleted = continuationToken == ""
+	}
+	return result
+}
+
+// Returns a nil output without error when the deadline margin is reached while backing off, so the segment stops at
+// its current checkpoint instead of failing the whole scan.
+func (repository *dynamodbBaseRepository) scanPage(ctx *common_models.LambdaContext, scanInput *dynamodb.ScanInput, deadlineMargin time.Duration) (*dynamodb.ScanOutput, common_errors.GenericApplicationError) {
+	for attempt := 0; ; attempt++ {
+		scanOutput, err := repository.client.Scan(ctx, scanInput)
+		if err == nil {
+			return scanOutput, nil
+		}
+		if !isThrottlingError(err) {
+			return nil, common_errors.NewInternalServerError("error while scanning database")
+		}
+		if !common_helpers.WaitForRetry(ctx, repository.batchRetryPolicy, attempt) {
+			if !hasTimeForScanPage(ctx, deadlineMargin) {
+				return nil, nil
+			}
+			return nil, common_errors.NewInternalServerError("database throttled scan after retries")
+		}
+	}
+}
+
+func (repository *dynamodbBaseRepository) buildScanInput(scan common_models.DynamodbScan, totalSegments int32) (*dynamodb.ScanInput, common_errors.GenericApplicationError) {
+	scanInput := &dynamodb.ScanInput{
+		TableName:              aws.String(repository.tableName),
+		ConsistentRead:         aws.Bool(scan.IsConsistentRead),
+		ReturnConsumedCapacity: types.ReturnConsumedCapacityTotal,
+		TotalSegments:          aws.Int32(totalSegments),
+	}
+	if scan.Index != nil {
+		scanInput.IndexName = aws.String(scan.Index.IndexName)
+	}
+	if scan.PageSize > 0 {
+		scanInput.Limit = aws.Int32(scan.PageSize)
+	}
+	filter := repository.applyExpirationToFilter(repository.applySoftDeleteToFilter(scan.Filter))
+	if filter == nil && len(scan.ProjectedAttributes) == 0 {
+		return scanInput, nil
+	}
+	expressionBuilder := expression.NewBuilder()
+	if filter != nil {
+		expressionBuilder = expressionBuilder.WithFilter(*filter)
+	}
+	if len(scan.ProjectedAttributes) > 0 {
+		expressionBuilder = expressionBuilder.WithProjection(buildProjection(repository.expandProjectedAttributes(scan.ProjectedAttributes)))
+	}
+	builtExpression, err := expressionBuilder.Build()
+	if err != nil {
+		return nil, common_errors.NewInternalServerError("error while building scan expression")
+	}
+	scanInput.FilterExpression = builtExpression.Filter()
+	scanInput.ProjectionExpression = builtExpression.Projection()
+	scanInput.ExpressionAttributeNames = builtExpression.Names()
+	scanInput.ExpressionAttributeValues = builtExpression.Values()
+	return scanInput, nil
+}
+
+func newScanCheckpoint(scan common_models.DynamodbScan) (common_models.DynamodbScanCheckpoint, common_errors.GenericApplicationError) {
+	totalSegments := scan.TotalSegments
+	if totalSegments == 0 {
+		totalSegments = 1
+	}
+	if totalSegments < 0 || totalSegments > maxScanSegments {
+		return common_models.DynamodbScanCheckpoint{}, common_errors.NewInternalServerError(fmt.Sprintf("scan total segments must be between 1 and %d", maxScanSegments))
+	}
+	segments := make([]common_models.DynamodbScanSegmentCheckpoint, totalSegments)
+	if scan.Checkpoint == nil {
+		for index := range segments {
+			segments[index].Segment = int32(index)
+		}
+		return common_models.DynamodbScanCheckpoint{Segments: segments}, nil
+	}
+	if len(scan.Checkpoint.Segments) != int(totalSegments) {
+		return common_models.DynamodbScanCheckpoint{}, common_errors.NewInternalServerError("scan checkpoint does not match total segments")
+	}
+	for index, segment := range scan.Checkpoint.Segments {
+		if segment.Segment != int32(index) {
+			return common_models.DynamodbScanCheckpoint{}, common_errors.NewInternalServerError("scan checkpoint does not match total segments")
+		}
+		segments[index] = segment
+	}
+	return common_models.DynamodbScanCheckpoint{Segments: segments}, nil
+}
+
+func hasTimeForScanPage(ctx *common_models.LambdaContext, deadlineMargin time.Duration) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	deadline, ok := ctx.Deadline()
+	return !ok || time.Until(deadline) > deadlineMargin
+}
+
+func isThrottlingError(err error) bool {
+	var throughputErr *types.ProvisionedThroughputExceededException
+	var requestLimitErr *types.RequestLimitExceeded
+	if errors.As(err, &throughputErr) || errors.As(err, &requestLimitErr) {
+		return true
+	}
+	var apiErr interface{ ErrorCode() string }
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == throttlingErrorCode
+}
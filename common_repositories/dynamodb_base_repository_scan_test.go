@@ -0,0 +1,371 @@
+package common_repositories_test
+
+import (
+	"context"
+	"errors"
+	"github.com/Drathveloper/lambda_commons/v2/common_constants"
+	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/Drathveloper/lambda_commons/v2/common_helpers"
+	"github.com/Drathveloper/lambda_commons/v2/common_models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/golang/mock/gomock"
+	"sort"
+	"sync"
+	"time"
+)
+
+func newScanItem(key string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"key1": &types.AttributeValueMemberS{Value: key},
+	}
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestScan_ShouldReadAllPagesOfSegment() {
+	context := common_models.NewLambdaContext()
+	lastEvaluatedKey := newScanItem("first")
+	firstInput := &dynamodb.ScanInput{
+		TableName:              aws.String("someTable"),
+		ConsistentRead:         aws.Bool(false),
+		ReturnConsumedCapacity: types.ReturnConsumedCapacityTotal,
+		TotalSegments:          aws.Int32(1),
+		Segment:                aws.Int32(0),
+		Limit:                  aws.Int32(1),
+	}
+	secondInput := &dynamodb.ScanInput{
+		TableName:              aws.String("someTable"),
+		ConsistentRead:         aws.Bool(false),
+		ReturnConsumedCapacity: types.ReturnConsumedCapacityTotal,
+		TotalSegments:          aws.Int32(1),
+		Segment:                aws.Int32(0),
+		Limit:                  aws.Int32(1),
+		ExclusiveStartKey:      lastEvaluatedKey,
+	}
+	firstOutput := &dynamodb.ScanOutput{
+		Items:            []map[string]types.AttributeValue{newScanItem("first")},
+		LastEvaluatedKey: lastEvaluatedKey,
+		ConsumedCapacity: &types.ConsumedCapacity{CapacityUnits: aws.Float64(0.5)},
+	}
+	secondOutput := &dynamodb.ScanOutput{
+		Items:            []map[string]types.AttributeValue{newScanItem("second")},
+		ConsumedCapacity: &types.ConsumedCapacity{CapacityUnits: aws.Float64(0.5)},
+	}
+	gomock.InOrder(
+		suite.dynamodbClient.EXPECT().Scan(gomock.Any(), firstInput).Return(firstOutput, nil),
+		suite.dynamodbClient.EXPECT().Scan(gomock.Any(), secondInput).Return(secondOutput, nil),
+	)
+	processedKeys := make([]string, 0)
+	scan := common_models.DynamodbScan{PageSize: 1}
+	expectedResult := common_models.DynamodbScanResult{
+		Checkpoint: common_models.DynamodbScanCheckpoint{
+			Segments: []common_models.DynamodbScanSegmentCheckpoint{{Segment: 0, IsCompleted: true}},
+		},
+		IsCompleted:      true,
+		ProcessedItems:   2,
+		ConsumedCapacity: 1,
+	}
+
+	result, appErr := suite.baseRepository.Scan(&context, scan, func(ctx *common_models.LambdaContext, item map[string]types.AttributeValue) common_errors.GenericApplicationError {
+		processedKeys = append(processedKeys, item["key1"].(*types.AttributeValueMemberS).Value)
+		return nil
+	})
+
+	suite.Nil(appErr)
+	suite.Equal(expectedResult, result)
+	suite.Equal([]string{"first", "second"}, processedKeys)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestScan_ShouldScanSegmentsInParallel() {
+	context := common_models.NewLambdaContext()
+	suite.dynamodbClient.EXPECT().Scan(gomock.Any(), gomock.Any()).Times(3).DoAndReturn(
+		func(ctx *common_models.LambdaContext, input *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+			suite.Equal(int32(3), *input.TotalSegments)
+			segment := *input.Segment
+			return &dynamodb.ScanOutput{
+				Items: []map[string]types.AttributeValue{newScanItem(string(rune('a' + segment)))},
+			}, nil
+		})
+	var mutex sync.Mutex
+	processedKeys := make([]string, 0)
+	scan := common_models.DynamodbScan{TotalSegments: 3}
+
+	result, appErr := suite.baseRepository.Scan(&context, scan, func(ctx *common_models.LambdaContext, item map[string]types.AttributeValue) common_errors.GenericApplicationError {
+		mutex.Lock()
+		defer mutex.Unlock()
+		processedKeys = append(processedKeys, item["key1"].(*types.AttributeValueMemberS).Value)
+		return nil
+	})
+	sort.Strings(processedKeys)
+
+	suite.Nil(appErr)
+	suite.True(result.IsCompleted)
+	suite.Equal(int64(3), result.ProcessedItems)
+	suite.Len(result.Checkpoint.Segments, 3)
+	suite.Equal([]string{"a", "b", "c"}, processedKeys)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestScan_ShouldResumeFromCheckpoint() {
+	context := common_models.NewLambdaContext()
+	lastEvaluatedKey := newScanItem("first")
+	continuationToken, _ := common_helpers.EncodeDynamodbContinuationToken(lastEvaluatedKey)
+	scan := common_models.DynamodbScan{
+		TotalSegments: 2,
+		Checkpoint: &common_models.DynamodbScanCheckpoint{
+			Segments: []common_models.DynamodbScanSegmentCheckpoint{
+				{Segment: 0, IsCompleted: true},
+				{Segment: 1, ContinuationToken: continuationToken},
+			},
+		},
+	}
+	expectedInput := &dynamodb.ScanInput{
+		TableName:              aws.String("someTable"),
+		ConsistentRead:         aws.Bool(false),
+		ReturnConsumedCapacity: types.ReturnConsumedCapacityTotal,
+		TotalSegments:          aws.Int32(2),
+		Segment:                aws.Int32(1),
+		ExclusiveStartKey:      lastEvaluatedKey,
+	}
+	suite.dynamodbClient.EXPECT().Scan(gomock.Any(), expectedInput).Return(&dynamodb.ScanOutput{
+		Items: []map[string]types.AttributeValue{newScanItem("second")},
+	}, nil)
+
+	result, appErr := suite.baseRepository.Scan(&context, scan, func(ctx *common_models.LambdaContext, item map[string]types.AttributeValue) common_errors.GenericApplicationError {
+		return nil
+	})
+
+	suite.Nil(appErr)
+	suite.True(result.IsCompleted)
+	suite.Equal(int64(1), result.ProcessedItems)
+	suite.Equal(continuationToken, scan.Checkpoint.Segments[1].ContinuationToken)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestScan_ShouldKeepCheckpointOfLastCompletedPageWhenCallbackFails() {
+	context := common_models.NewLambdaContext()
+	lastEvaluatedKey := newScanItem("first")
+	continuationToken, _ := common_helpers.EncodeDynamodbContinuationToken(lastEvaluatedKey)
+	gomock.InOrder(
+		suite.dynamodbClient.EXPECT().Scan(gomock.Any(), gomock.Any()).Return(&dynamodb.ScanOutput{
+			Items:            []map[string]types.AttributeValue{newScanItem("first")},
+			LastEvaluatedKey: lastEvaluatedKey,
+		}, nil),
+		suite.dynamodbClient.EXPECT().Scan(gomock.Any(), gomock.Any()).Return(&dynamodb.ScanOutput{
+			Items: []map[string]types.AttributeValue{newScanItem("second")},
+		}, nil),
+	)
+	expectedAppErr := common_errors.NewInternalServerError("migration failure")
+	expectedCheckpoint := common_models.DynamodbScanCheckpoint{
+		Segments: []common_models.DynamodbScanSegmentCheckpoint{{Segment: 0, ContinuationToken: continuationToken}},
+	}
+
+	result, appErr := suite.baseRepository.Scan(&context, common_models.DynamodbScan{}, func(ctx *common_models.LambdaContext, item map[string]types.AttributeValue) common_errors.GenericApplicationError {
+		if item["key1"].(*types.AttributeValueMemberS).Value == "second" {
+			return expectedAppErr
+		}
+		return nil
+	})
+
+	suite.Equal(expectedAppErr, appErr)
+	suite.False(result.IsCompleted)
+	suite.Equal(int64(1), result.ProcessedItems)
+	suite.Equal(expectedCheckpoint, result.Checkpoint)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestScan_ShouldReturnInternalServerErrorWhenScanFails() {
+	context := common_models.NewLambdaContext()
+	suite.dynamodbClient.EXPECT().Scan(gomock.Any(), gomock.Any()).Return(nil, errors.New("some error"))
+	expectedAppErr := common_errors.NewInternalServerError("error while scanning database")
+
+	result, appErr := suite.baseRepository.Scan(&context, common_models.DynamodbScan{}, func(ctx *common_models.LambdaContext, item map[string]types.AttributeValue) common_errors.GenericApplicationError {
+		return nil
+	})
+
+	suite.Equal(expectedAppErr, appErr)
+	suite.False(result.IsCompleted)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestScan_ShouldStopBeforeDeadlineMargin() {
+	parent, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	lambdaContext := common_models.NewLambdaContextFromContext(parent)
+	scan := common_models.DynamodbScan{
+		TotalSegments:  2,
+		DeadlineMargin: 5 * time.Second,
+	}
+	expectedCheckpoint := common_models.DynamodbScanCheckpoint{
+		Segments: []common_models.DynamodbScanSegmentCheckpoint{{Segment: 0}, {Segment: 1}},
+	}
+
+	result, appErr := suite.baseRepository.Scan(&lambdaContext, scan, func(ctx *common_models.LambdaContext, item map[string]types.AttributeValue) common_errors.GenericApplicationError {
+		return nil
+	})
+
+	suite.Nil(appErr)
+	suite.False(result.IsCompleted)
+	suite.Equal(expectedCheckpoint, result.Checkpoint)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestScan_ShouldBuildFilterAndProjectionExpressions() {
+	context := common_models.NewLambdaContext()
+	filter := expression.Name("status").Equal(expression.Value("PENDING"))
+	scan := common_models.DynamodbScan{
+		Index:               &common_models.DynamodbSecondaryIndex{IndexName: "someIndex", IsGlobal: true},
+		Filter:              &filter,
+		ProjectedAttributes: []string{"key1", "status"},
+	}
+	expectedInput := &dynamodb.ScanInput{
+		TableName:                aws.String("someTable"),
+		IndexName:                aws.String("someIndex"),
+		ConsistentRead:           aws.Bool(false),
+		ReturnConsumedCapacity:   types.ReturnConsumedCapacityTotal,
+		TotalSegments:            aws.Int32(1),
+		Segment:                  aws.Int32(0),
+		FilterExpression:         aws.String("#0 = :0"),
+		ProjectionExpression:     aws.String("#1, #0"),
+		ExpressionAttributeNames: map[string]string{"#0": "status", "#1": "key1"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":0": &types.AttributeValueMemberS{Value: "PENDING"},
+		},
+	}
+	suite.dynamodbClient.EXPECT().Scan(gomock.Any(), expectedInput).Return(&dynamodb.ScanOutput{}, nil)
+
+	result, appErr := suite.baseRepository.Scan(&context, scan, func(ctx *common_models.LambdaContext, item map[string]types.AttributeValue) common_errors.GenericApplicationError {
+		return nil
+	})
+
+	suite.Nil(appErr)
+	suite.True(result.IsCompleted)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestScan_ShouldReturnErrorWhenCheckpointDoesNotMatchSegments() {
+	context := common_models.NewLambdaContext()
+	scan := common_models.DynamodbScan{
+		TotalSegments: 2,
+		Checkpoint: &common_models.DynamodbScanCheckpoint{
+			Segments: []common_models.DynamodbScanSegmentCheckpoint{{Segment: 0}},
+		},
+	}
+	expectedAppErr := common_errors.NewInternalServerError("scan checkpoint does not match total segments")
+
+	_, appErr := suite.baseRepository.Scan(&context, scan, func(ctx *common_models.LambdaContext, item map[string]types.AttributeValue) common_errors.GenericApplicationError {
+		return nil
+	})
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestScan_ShouldReturnErrorWhenConsistentReadOnGlobalIndex() {
+	context := common_models.NewLambdaContext()
+	scan := common_models.DynamodbScan{
+		Index:            &common_models.DynamodbSecondaryIndex{IndexName: "someIndex", IsGlobal: true},
+		IsConsistentRead: true,
+	}
+	expectedAppErr := common_errors.NewInternalServerError("consistent reads are not supported on global secondary indexes")
+
+	_, appErr := suite.baseRepository.Scan(&context, scan, func(ctx *common_models.LambdaContext, item map[string]types.AttributeValue) common_errors.GenericApplicationError {
+		return nil
+	})
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestScan_ShouldRetryThrottledPages() {
+	context := common_models.NewLambdaContext()
+	gomock.InOrder(
+		suite.dynamodbClient.EXPECT().Scan(gomock.Any(), gomock.Any()).Return(nil, &types.ProvisionedThroughputExceededException{}),
+		suite.dynamodbClient.EXPECT().Scan(gomock.Any(), gomock.Any()).Return(&dynamodb.ScanOutput{
+			Items: []map[string]types.AttributeValue{newScanItem("first")},
+		}, nil),
+	)
+
+	result, appErr := suite.baseRepository.Scan(&context, common_models.DynamodbScan{}, func(ctx *common_models.LambdaContext, item map[string]types.AttributeValue) common_errors.GenericApplicationError {
+		return nil
+	})
+
+	suite.Nil(appErr)
+	suite.True(result.IsCompleted)
+	suite.Equal(int64(1), result.ProcessedItems)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestScan_ShouldLimitConcurrentSegments() {
+	context := common_models.NewLambdaContext()
+	var mutex sync.Mutex
+	running := 0
+	maxRunning := 0
+	suite.dynamodbClient.EXPECT().Scan(gomock.Any(), gomock.Any()).Times(6).DoAndReturn(
+		func(ctx *common_models.LambdaContext, input *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+			mutex.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mutex.Unlock()
+			time.Sleep(5 * time.Millisecond)
+			mutex.Lock()
+			running--
+			mutex.Unlock()
+			return &dynamodb.ScanOutput{}, nil
+		})
+	scan := common_models.DynamodbScan{TotalSegments: 6, MaxConcurrentSegments: 2}
+
+	result, appErr := suite.baseRepository.Scan(&context, scan, func(ctx *common_models.LambdaContext, item map[string]types.AttributeValue) common_errors.GenericApplicationError {
+		return nil
+	})
+
+	suite.Nil(appErr)
+	suite.True(result.IsCompleted)
+	suite.LessOrEqual(maxRunning, 2)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestScan_ShouldGiveEverySegmentItsOwnContext() {
+	context := common_models.NewLambdaContext()
+	context.Set("someKey", "someValue")
+	suite.dynamodbClient.EXPECT().Scan(gomock.Any(), gomock.Any()).Times(4).DoAndReturn(
+		func(ctx *common_models.LambdaContext, input *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+			return &dynamodb.ScanOutput{Items: []map[string]types.AttributeValue{newScanItem("first"), newScanItem("second")}}, nil
+		})
+	var mutex sync.Mutex
+	contexts := make(map[*common_models.LambdaContext]bool)
+	scan := common_models.DynamodbScan{TotalSegments: 4}
+
+	_, appErr := suite.baseRepository.Scan(&context, scan, func(ctx *common_models.LambdaContext, item map[string]types.AttributeValue) common_errors.GenericApplicationError {
+		value, _ := ctx.Get("someKey")
+		suite.Equal("someValue", value)
+		ctx.Set("segmentKey", item)
+		mutex.Lock()
+		contexts[ctx] = true
+		mutex.Unlock()
+		return nil
+	})
+
+	suite.Nil(appErr)
+	suite.Len(contexts, 4)
+	_, exists := context.Get("segmentKey")
+	suite.False(exists)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestScan_ShouldReturnErrorInsideTransaction() {
+	context := common_models.NewLambdaContext()
+	context.Set(common_constants.WriteTransaction, dynamodb.TransactWriteItemsInput{})
+	expectedAppErr := common_errors.NewInternalServerError("scan is not supported inside a transaction")
+
+	_, appErr := suite.baseRepository.Scan(&context, common_models.DynamodbScan{}, func(ctx *common_models.LambdaContext, item map[string]types.AttributeValue) common_errors.GenericApplicationError {
+		return nil
+	})
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestScan_ShouldApplyDefaultDeadlineMargin() {
+	parent, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	lambdaContext := common_models.NewLambdaContextFromContext(parent)
+
+	result, appErr := suite.baseRepository.Scan(&lambdaContext, common_models.DynamodbScan{}, func(ctx *common_models.LambdaContext, item map[string]types.AttributeValue) common_errors.GenericApplicationError {
+		return nil
+	})
+
+	suite.Nil(appErr)
+	suite.False(result.IsCompleted)
+}
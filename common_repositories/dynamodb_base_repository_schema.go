@@ -0,0 +1,133 @@
+package common_repositories
+
+import (
+	"fmt"
+	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/Drathveloper/lambda_commons/v2/common_models"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func (repository *dynamodbBaseRepository) SaveIfNotPresent(ctx *common_models.LambdaContext, item interface{}) common_errors.GenericApplicationError {
+	if repository.tableSchema == nil {
+		return common_errors.NewInternalServerError("a table schema is required to save an item if not present")
+	}
+	itemAttributeValue, appErr := repository.marshalItem(item)
+	if appErr != nil {
+		return appErr
+	}
+	condition := expression.AttributeNotExists(expression.Name(repository.tableSchema.PartitionKey))
+	if repository.tableSchema.SortKey != "" {
+		condition = condition.And(expression.AttributeNotExists(expression.Name(repository.tableSchema.SortKey)))
+	}
+	builtExpression, err := expression.NewBuilder().WithCondition(condition).Build()
+	if err != nil {
+		return common_errors.NewInternalServerError("error while building save expression")
+	}
+	repository.initializeVersion(itemAttributeValue)
+	return repository.save(ctx, builtExpression, itemAttributeValue, false)
+}
+
+func (repository *dynamodbBaseRepository) DeleteByItem(ctx *common_models.LambdaContext, item interface{}, options common_models.DynamodbDeleteOptions) (map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+	keyValues, appErr := repository.ExtractPrimaryKey(item)
+	if appErr != nil {
+		return nil, appErr
+	}
+	return repository.delete(ctx, keyValues, options)
+}
+
+func (repository *dynamodbBaseRepository) ExtractPrimaryKey(item interface{}) (map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+	if repository.tableSchema == nil {
+		return nil, common_errors.NewInternalServerError("a table schema is required to extract the primary key of an item")
+	}
+	itemAttributeValue, appErr := repository.marshalItem(item)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if appErr := repository.validateItemKey(itemAttributeValue); appErr != nil {
+		return nil, appErr
+	}
+	return extractKey(itemAttributeValue, repository.primaryKeyNames()), nil
+}
+
+func (repository *dynamodbBaseRepository) primaryKeyNames() []string {
+	if repository.tableSchema.SortKey == "" {
+		return []string{repository.tableSchema.PartitionKey}
+	}
+	return []string{repository.tableSchema.PartitionKey, repository.tableSchema.SortKey}
+}
+
+func (repository *dynamodbBaseRepository) validatePrimaryKey(keyValues map[string]types.AttributeValue) common_errors.GenericApplicationError {
+	if repository.tableSchema == nil {
+		return nil
+	}
+	keyNames := repository.primaryKeyNames()
+	for attributeName := range keyValues {
+		if !containsString(keyNames, attributeName) {
+			return common_errors.NewInternalServerError(fmt.Sprintf("attribute %s is not part of the primary key of table %s", attributeName, repository.tableName))
+		}
+	}
+	return validateKeyAttributes(keyValues, keyNames)
+}
+
+func (repository *dynamodbBaseRepository) validateItemKey(item map[string]types.AttributeValue) common_errors.GenericApplicationError {
+	if repository.tableSchema == nil {
+		return nil
+	}
+	return validateKeyAttributes(item, repository.primaryKeyNames())
+}
+
+func (repository *dynamodbBaseRepository) validateQueryKeys(query common_models.DynamodbQuery) common_errors.GenericApplicationError {
+	if repository.tableSchema == nil {
+		return nil
+	}
+	partitionKey := repository.tableSchema.PartitionKey
+	sortKey := repository.tableSchema.SortKey
+	if query.Index != nil {
+		index, exists := repository.tableSchema.Indexes[query.Index.IndexName]
+		if !exists {
+			return common_errors.NewInternalServerError(fmt.Sprintf("index %s is not declared in the table schema", query.Index.IndexName))
+		}
+		partitionKey = index.PartitionKey
+		sortKey = index.SortKey
+	}
+	if query.PartitionKey.KeyName != partitionKey {
+		return common_errors.NewInternalServerError(fmt.Sprintf("query partition key must be %s", partitionKey))
+	}
+	if query.SortKeyCondition != nil && query.SortKeyCondition.KeyName != sortKey {
+		return common_errors.NewInternalServerError(fmt.Sprintf("query sort key must be %s", sortKey))
+	}
+	return nil
+}
+
+func validateKeyAttributes(item map[string]types.AttributeValue, keyNames []string) common_errors.GenericApplicationError {
+	for _, keyName := range keyNames {
+		value, exists := item[keyName]
+		if !exists {
+			return common_errors.NewBadRequestError(fmt.Sprintf("primary key attribute %s is missing", keyName))
+		}
+		switch typedValue := value.(type) {
+		case *types.AttributeValueMemberS:
+			if typedValue.Value == "" {
+				return common_errors.NewBadRequestError(fmt.Sprintf("primary key attribute %s must not be empty", keyName))
+			}
+		case *types.AttributeValueMemberB:
+			if len(typedValue.Value) == 0 {
+				return common_errors.NewBadRequestError(fmt.Sprintf("primary key attribute %s must not be empty", keyName))
+			}
+		case *types.AttributeValueMemberN:
+		default:
+			return common_errors.NewBadRequestError(fmt.Sprintf("primary key attribute %s must be a string, number or binary", keyName))
+		}
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package common_repositories
+
+import (
+	"errors"
+	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/Drathveloper/lambda_commons/v2/common_models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"sort"
+	"time"
+)
+
+const (
+	defaultDeletedAtAttribute  = "deletedAt"
+	defaultExpirationAttribute = "ttl"
+	defaultSoftDeleteRetention = 30 * 24 * time.Hour
+)
+
+func newSoftDeleteConfig(config common_models.DynamodbSoftDeleteConfig) *common_models.DynamodbSoftDeleteConfig {
+	if config.DeletedAtAttribute == "" {
+		config.DeletedAtAttribute = defaultDeletedAtAttribute
+	}
+	if config.ExpirationAttribute == "" {
+		config.ExpirationAttribute = defaultExpirationAttribute
+	}
+	if config.Retention <= 0 {
+		config.Retention = defaultSoftDeleteRetention
+	}
+	if config.Clock == nil {
+		config.Clock = time.Now
+	}
+	return &config
+}
+
+func (repository *dynamodbBaseRepository) IncludingDeleted() DynamodbBaseRepository {
+	includingDeleted := *repository
+	includingDeleted.isIncludingDeleted = true
+	return &includingDeleted
+}
+
+func (repository *dynamodbBaseRepository) softDelete(ctx *common_models.LambdaContext, keyValues map[string]types.AttributeValue, options common_models.DynamodbDeleteOptions) (map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+	deletedAt := repository.softDeleteConfig.Clock().UTC()
+	expiresAt := deletedAt.Add(repository.softDeleteConfig.Retention)
+	condition := repository.buildSoftDeleteCondition(keyValues)
+	if options.Condition != nil {
+		condition = condition.And(*options.Condition)
+	}
+	update := common_models.DynamodbUpdate{
+		Operations: []common_models.DynamodbUpdateOperation{
+			{Action: common_models.DynamodbUpdateSet, AttributeName: repository.softDeleteConfig.DeletedAtAttribute, Value: deletedAt.Format(time.RFC3339Nano)},
+			{Action: common_models.DynamodbUpdateSet, AttributeName: repository.softDeleteConfig.ExpirationAttribute, Value: expiresAt.Unix()},
+		},
+		Condition: &condition,
+	}
+	builtExpression, appErr := buildUpdateExpression(repository.applyAuditToUpdate(ctx, repository.applyVersionToUpdate(update)))
+	if appErr != nil {
+		return nil, appErr
+	}
+	transactWriteItem := types.TransactWriteItem{
+		Update: &types.Update{
+			TableName:                 aws.String(repository.tableName),
+			UpdateExpression:          builtExpression.Update(),
+			ConditionExpression:       builtExpression.Condition(),
+			ExpressionAttributeNames:  builtExpression.Names(),
+			ExpressionAttributeValues: builtExpression.Values(),
+			Key:                       keyValues,
+		},
+	}
+	if inTransaction, appErr := appendToWriteTransaction(ctx, transactWriteItem, common_models.DynamodbTransactWriteItemMetadata{}); inTransaction {
+		return nil, appErr
+	}
+	updateItemInput := &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(repository.tableName),
+		UpdateExpression:          builtExpression.Update(),
+		ConditionExpression:       builtExpression.Condition(),
+		ExpressionAttributeNames:  builtExpression.Names(),
+		ExpressionAttributeValues: builtExpression.Values(),
+		Key:                       keyValues,
+	}
+	if options.ReturnOldValues {
+		updateItemInput.ReturnValues = types.ReturnValueAllOld
+	}
+	updateItemOutput, err := repository.client.UpdateItem(ctx, updateItemInput)
+	if err != nil {
+		var dynamodbErr *types.ConditionalCheckFailedException
+		if errors.As(err, &dynamodbErr) {
+			if options.Condition == nil {
+				return nil, common_errors.NewNotFoundError("item not found or already deleted")
+			}
+			return nil, common_errors.NewForbiddenError("item does not satisfy delete condition")
+		}
+		return nil, common_errors.NewInternalServerError("error while deleting from database")
+	}
+	return repository.prepareTransactionItemForRead(ctx, updateItemOutput.Attributes)
+}
+
+func (repository *dynamodbBaseRepository) buildSoftDeleteCondition(keyValues map[string]types.AttributeValue) expression.ConditionBuilder {
+	keyNames := make([]string, 0, len(keyValues))
+	for keyName := range keyValues {
+		keyNames = append(keyNames, keyName)
+	}
+	sort.Strings(keyNames)
+	condition := repository.buildNotDeletedCondition()
+	for _, keyName := range keyNames {
+		condition = condition.And(expression.AttributeExists(expression.Name(keyName)))
+	}
+	return condition
+}
+
+func (repository *dynamodbBaseRepository) isHidingDeleted() bool {
+	return repository.softDeleteConfig != nil && !repository.isIncludingDeleted
+}
+
+func (repository *dynamodbBaseRepository) isSoftDeleted(item map[string]types.AttributeValue) bool {
+	if !repository.isHidingDeleted() {
+		return false
+	}
+	value, exists := item[repository.softDeleteConfig.DeletedAtAttribute]
+	if !exists {
+		return false
+	}
+	_, isNull := value.(*types.AttributeValueMemberNULL)
+	return !isNull
+}
+
+func (repository *dynamodbBaseRepository) applySoftDeleteToFilter(filter *expression.ConditionBuilder) *expression.ConditionBuilder {
+	if !repository.isHidingDeleted() {
+		return filter
+	}
+	notDeleted := repository.buildNotDeletedCondition()
+	if filter != nil {
+		notDeleted = notDeleted.And(*filter)
+	}
+	return &notDeleted
+}
+
+// A NULL deletedAt, as marshaled from an unset pointer field, means the item is live, matching isSoftDeleted.
+func (repository *dynamodbBaseRepository) buildNotDeletedCondition() expression.ConditionBuilder {
+	deletedAt := expression.Name(repository.softDeleteConfig.DeletedAtAttribute)
+	return expression.AttributeNotExists(deletedAt).Or(expression.AttributeType(deletedAt, expression.Null))
+}
@@ -1,18 +1,26 @@
 package common_repositories_test
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"github.com/Drathveloper/lambda_commons/v2/common_constants"
 	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/Drathveloper/lambda_commons/v2/common_helpers"
 	"github.com/Drathveloper/lambda_commons/v2/common_models"
 	"github.com/Drathveloper/lambda_commons/v2/common_repositories"
 	"github.com/Drathveloper/lambda_commons/v2/mocks"
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/suite"
+	"strings"
 	"testing"
+	"time"
 )
 
 type DummyItem struct {
@@ -20,10 +28,35 @@ type DummyItem struct {
 	Key2 string `dynamodbav:"key2"`
 }
 
+type VersionedDummyItem struct {
+	Key1    string `dynamodbav:"key1"`
+	Version int64  `dynamodbav:"version"`
+}
+
+type SchemaDummyItem struct {
+	Key1  string `dynamodbav:"key1" dynamo:"pk"`
+	Key2  string `dynamodbav:"key2" dynamo:"sk"`
+	Value string `dynamodbav:"value"`
+}
+
+type EncryptedDummyItem struct {
+	Key1  string `dynamodbav:"key1" dynamo:"pk"`
+	Email string `dynamodbav:"email" dynamo:"encrypted"`
+}
+
 type DynamodbBaseRepositoryTestSuite struct {
 	suite.Suite
-	dynamodbClient *mocks.MockDynamodbClientAPI
-	baseRepository common_repositories.DynamodbBaseRepository
+	dynamodbClient       *mocks.MockDynamodbClientAPI
+	baseRepository       common_repositories.DynamodbBaseRepository
+	versionedRepository  common_repositories.DynamodbBaseRepository
+	schemaRepository     common_repositories.DynamodbBaseRepository
+	auditedRepository    common_repositories.DynamodbBaseRepository
+	softDeleteRepository common_repositories.DynamodbBaseRepository
+	ttlRepository        common_repositories.DynamodbBaseRepository
+	blobStore            *mocks.MockBlobStoreAPI
+	offloadRepository    common_repositories.DynamodbBaseRepository
+	encryptedRepository  common_repositories.DynamodbBaseRepository
+	compressedRepository common_repositories.DynamodbBaseRepository
 }
 
 func TestDynamodbBaseRepositoryTestSuite(t *testing.T) {
@@ -34,6 +67,48 @@ func (suite *DynamodbBaseRepositoryTestSuite) SetupTest() {
 	controller := gomock.NewController(suite.T())
 	suite.dynamodbClient = mocks.NewMockDynamodbClientAPI(controller)
 	suite.baseRepository = common_repositories.NewDynamodbBaseRepository(suite.dynamodbClient, "someTable")
+	suite.versionedRepository = common_repositories.NewDynamodbBaseRepository(suite.dynamodbClient, "someTable", common_repositories.WithVersionAttribute("version"))
+	schema, _ := common_helpers.ParseDynamodbTableSchema(SchemaDummyItem{})
+	suite.schemaRepository = common_repositories.NewDynamodbBaseRepository(suite.dynamodbClient, "someTable", common_repositories.WithTableSchema(schema))
+	suite.auditedRepository = common_repositories.NewDynamodbBaseRepository(suite.dynamodbClient, "someTable", common_repositories.WithAudit(common_models.DynamodbAuditConfig{
+		Clock: func() time.Time {
+			return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		},
+	}))
+	suite.ttlRepository = common_repositories.NewDynamodbBaseRepository(suite.dynamodbClient, "someTable", common_repositories.WithTimeToLive(common_models.DynamodbTimeToLiveConfig{
+		Attribute:          "expiresAt",
+		IsFilteringExpired: true,
+		Clock: func() time.Time {
+			return time.Unix(1700000000, 0)
+		},
+	}))
+	suite.blobStore = mocks.NewMockBlobStoreAPI(controller)
+	suite.offloadRepository = common_repositories.NewDynamodbBaseRepository(suite.dynamodbClient, "someTable", common_repositories.WithBlobOffload(common_models.DynamodbBlobOffloadConfig{
+		BlobStore:     suite.blobStore,
+		Attributes:    []string{"payload"},
+		SizeThreshold: 5,
+		KeyPrefix:     "blobs/",
+		KeyAttributes: []string{"key1"},
+		Clock: func() time.Time {
+			return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		},
+	}))
+	encryptedSchema, _ := common_helpers.ParseDynamodbTableSchema(EncryptedDummyItem{})
+	suite.encryptedRepository = common_repositories.NewDynamodbBaseRepository(suite.dynamodbClient, "someTable",
+		common_repositories.WithTableSchema(encryptedSchema),
+		common_repositories.WithEncryption(common_models.DynamodbEncryptionConfig{
+			KeyProvider: common_helpers.NewStaticDataKeyProvider([]byte("0123456789abcdef0123456789abcdef")),
+		}))
+	suite.compressedRepository = common_repositories.NewDynamodbBaseRepository(suite.dynamodbClient, "someTable", common_repositories.WithCompression(common_models.DynamodbCompressionConfig{
+		Attributes: []string{"payload"},
+		MinSize:    10,
+	}))
+	suite.softDeleteRepository = common_repositories.NewDynamodbBaseRepository(suite.dynamodbClient, "someTable", common_repositories.WithSoftDelete(common_models.DynamodbSoftDeleteConfig{
+		Retention: 24 * time.Hour,
+		Clock: func() time.Time {
+			return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		},
+	}))
 }
 
 func (suite *DynamodbBaseRepositoryTestSuite) TestFindBySimplePrimaryKey_ShouldSucceedWhenNoTransaction() {
@@ -70,6 +145,85 @@ func (suite *DynamodbBaseRepositoryTestSuite) TestFindBySimplePrimaryKey_ShouldS
 	suite.Equal(response, item)
 }
 
+func (suite *DynamodbBaseRepositoryTestSuite) TestFindBySimplePrimaryKeyInto_ShouldDecodeItemWithDecoderOptions() {
+	context := common_models.NewLambdaContext()
+	repository := common_repositories.NewDynamodbBaseRepository(suite.dynamodbClient, "someTable", common_repositories.WithDecoderOptions(func(options *attributevalue.DecoderOptions) {
+		options.TagKey = "json"
+	}))
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "someKey",
+		Value:   "someValue",
+	}
+	getItemOutput := &dynamodb.GetItemOutput{
+		Item: map[string]types.AttributeValue{
+			"someKey":    &types.AttributeValueMemberS{Value: "someValue"},
+			"anotherKey": &types.AttributeValueMemberS{Value: "anotherValue"},
+		},
+	}
+	suite.dynamodbClient.EXPECT().GetItem(&context, gomock.Any()).Return(getItemOutput, nil)
+	var actual struct {
+		SomeKey    string `json:"someKey"`
+		AnotherKey string `json:"anotherKey"`
+	}
+
+	found, appErr := repository.FindBySimplePrimaryKeyInto(&context, primaryKey, false, &actual)
+
+	suite.NoError(appErr)
+	suite.True(found)
+	suite.Equal("someValue", actual.SomeKey)
+	suite.Equal("anotherValue", actual.AnotherKey)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestFindBySimplePrimaryKeyInto_ShouldReturnNotFoundWhenItemIsAbsent() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "someKey",
+		Value:   "someValue",
+	}
+	suite.dynamodbClient.EXPECT().GetItem(&context, gomock.Any()).Return(&dynamodb.GetItemOutput{}, nil)
+	actual := DummyItem{}
+
+	found, appErr := suite.baseRepository.FindBySimplePrimaryKeyInto(&context, primaryKey, false, &actual)
+
+	suite.NoError(appErr)
+	suite.False(found)
+	suite.Equal(DummyItem{}, actual)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestFindBySimplePrimaryKeyInto_ShouldReturnInternalServerErrorWhenReadTransaction() {
+	context := common_models.NewLambdaContext()
+	context.Set(common_constants.ReadTransaction, dynamodb.TransactGetItemsInput{})
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "someKey",
+		Value:   "someValue",
+	}
+	expectedAppErr := common_errors.NewInternalServerError("typed finders cannot be used inside a read transaction, use read handles instead")
+
+	_, appErr := suite.baseRepository.FindBySimplePrimaryKeyInto(&context, primaryKey, false, &DummyItem{})
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestFindByComplexPrimaryKeyInto_ShouldReturnInternalServerErrorWhenItemCannotBeDecoded() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbComplexPrimaryKey{
+		PartitionKey: common_models.DynamodbSimplePrimaryKey{KeyName: "key1", Value: "foo"},
+		SortKey:      common_models.DynamodbSimplePrimaryKey{KeyName: "key2", Value: "bar"},
+	}
+	getItemOutput := &dynamodb.GetItemOutput{
+		Item: map[string]types.AttributeValue{
+			"key1": &types.AttributeValueMemberL{Value: []types.AttributeValue{}},
+		},
+	}
+	suite.dynamodbClient.EXPECT().GetItem(&context, gomock.Any()).Return(getItemOutput, nil)
+	expectedAppErr := common_errors.NewInternalServerError("error while decoding item")
+
+	found, appErr := suite.baseRepository.FindByComplexPrimaryKeyInto(&context, primaryKey, false, &DummyItem{})
+
+	suite.False(found)
+	suite.Equal(expectedAppErr, appErr)
+}
+
 func (suite *DynamodbBaseRepositoryTestSuite) TestFindBySimplePrimaryKey_ShouldReturnInternalServerErrorWhenGetItemFailed() {
 	context := common_models.NewLambdaContext()
 	primaryKey := common_models.DynamodbSimplePrimaryKey{
@@ -131,6 +285,121 @@ func (suite *DynamodbBaseRepositoryTestSuite) TestFindBySimplePrimaryKey_ShouldS
 	suite.Equal(expectedGetItemsInput, actualGetItemsInput)
 }
 
+func (suite *DynamodbBaseRepositoryTestSuite) TestFindBySimplePrimaryKeyInReadTransaction_ShouldReturnHandlesForEachRead() {
+	context := common_models.NewLambdaContext()
+	transactionManager := common_repositories.NewDynamodbTransactionManager(suite.dynamodbClient)
+	firstItem := map[string]types.AttributeValue{
+		"someKey": &types.AttributeValueMemberS{
+			Value: "firstValue",
+		},
+	}
+	secondItem := map[string]types.AttributeValue{
+		"someKey": &types.AttributeValueMemberS{
+			Value: "secondValue",
+		},
+	}
+	transactionOutput := &dynamodb.TransactGetItemsOutput{
+		Responses: []types.ItemResponse{
+			{Item: firstItem},
+			{Item: secondItem},
+		},
+	}
+	suite.dynamodbClient.EXPECT().TransactGetItems(&context, gomock.Any()).Return(transactionOutput, nil)
+
+	startErr := transactionManager.StartReadTransaction(&context)
+	firstHandle, firstErr := suite.baseRepository.FindBySimplePrimaryKeyInReadTransaction(&context, common_models.DynamodbSimplePrimaryKey{KeyName: "someKey", Value: "firstValue"})
+	secondHandle, secondErr := suite.baseRepository.FindBySimplePrimaryKeyInReadTransaction(&context, common_models.DynamodbSimplePrimaryKey{KeyName: "someKey", Value: "secondValue"})
+	_, notExecutedErr := firstHandle.Item()
+	_, executeErr := transactionManager.ExecuteReadTransactionItems(&context)
+	actualFirstItem, firstItemErr := firstHandle.Item()
+	actualSecondItem, secondItemErr := secondHandle.Item()
+
+	suite.NoError(startErr)
+	suite.NoError(firstErr)
+	suite.NoError(secondErr)
+	suite.Equal(common_errors.NewInternalServerError("read transaction has not been executed yet"), notExecutedErr)
+	suite.NoError(executeErr)
+	suite.NoError(firstItemErr)
+	suite.NoError(secondItemErr)
+	suite.Equal(firstItem, actualFirstItem)
+	suite.Equal(secondItem, actualSecondItem)
+	suite.False(context.Exists(common_constants.ReadTransactionResults))
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestFindBySimplePrimaryKeyInReadTransaction_ShouldReturnInternalServerErrorWhenNoTransaction() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "someKey",
+		Value:   "someValue",
+	}
+	expectedAppErr := common_errors.NewInternalServerError("there is no read transaction in progress")
+
+	_, appErr := suite.baseRepository.FindBySimplePrimaryKeyInReadTransaction(&context, primaryKey)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestConditionCheckBySimplePrimaryKey_ShouldAppendConditionCheckToTransaction() {
+	context := common_models.NewLambdaContext()
+	context.Set(common_constants.WriteTransaction, dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{},
+	})
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "someKey",
+		Value:   "someValue",
+	}
+	conditionCheck := common_models.DynamodbConditionCheck{
+		Condition: expression.Name("status").Equal(expression.Value("ACTIVE")),
+		Label:     "account-active",
+	}
+	expectedTransactionInput := dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{
+				ConditionCheck: &types.ConditionCheck{
+					TableName:           aws.String("someTable"),
+					ConditionExpression: aws.String("#0 = :0"),
+					ExpressionAttributeNames: map[string]string{
+						"#0": "status",
+					},
+					ExpressionAttributeValues: map[string]types.AttributeValue{
+						":0": &types.AttributeValueMemberS{Value: "ACTIVE"},
+					},
+					Key: map[string]types.AttributeValue{
+						"someKey": &types.AttributeValueMemberS{Value: "someValue"},
+					},
+				},
+			},
+		},
+	}
+	expectedItemsMetadata := []common_models.DynamodbTransactWriteItemMetadata{
+		{Label: "account-active"},
+	}
+
+	appErr := suite.baseRepository.ConditionCheckBySimplePrimaryKey(&context, primaryKey, conditionCheck)
+	actualTransactionInput, _ := context.Get(common_constants.WriteTransaction)
+	actualItemsMetadata, _ := context.Get(common_constants.WriteTransactionItems)
+
+	suite.NoError(appErr)
+	suite.Equal(expectedTransactionInput, actualTransactionInput)
+	suite.Equal(expectedItemsMetadata, actualItemsMetadata)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestConditionCheckBySimplePrimaryKey_ShouldReturnInternalServerErrorWhenNoTransaction() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "someKey",
+		Value:   "someValue",
+	}
+	conditionCheck := common_models.DynamodbConditionCheck{
+		Condition: expression.AttributeExists(expression.Name("someKey")),
+	}
+	expectedAppErr := common_errors.NewInternalServerError("there is no write transaction in progress")
+
+	appErr := suite.baseRepository.ConditionCheckBySimplePrimaryKey(&context, primaryKey, conditionCheck)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
 func (suite *DynamodbBaseRepositoryTestSuite) TestFindByComplexPrimaryKey_ShouldSucceedWhenNoTransaction() {
 	context := common_models.NewLambdaContext()
 	primaryKey := common_models.DynamodbComplexPrimaryKey{
@@ -608,3 +877,2046 @@ func (suite *DynamodbBaseRepositoryTestSuite) TestSave_ShouldSucceedWhenTransact
 	suite.True(exists)
 	suite.Equal(expectedWriteItemsInput, actualWriteItemInput)
 }
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestSave_ShouldReturnTransactionErrorWhenTransactionIsFull() {
+	context := common_models.NewLambdaContext()
+	transactItems := make([]types.TransactWriteItem, 0)
+	for index := 0; index < 100; index++ {
+		transactItems = append(transactItems, types.TransactWriteItem{
+			Put: &types.Put{
+				TableName: aws.String("someTable"),
+			},
+		})
+	}
+	context.Set(common_constants.WriteTransaction, dynamodb.TransactWriteItemsInput{
+		TransactItems: transactItems,
+	})
+	item := DummyItem{Key1: "foo", Key2: "bar"}
+	expectedAppErr := common_errors.NewTransactionLimitExceededError("write transaction cannot contain more than 100 items, rejected transaction item 100", 100, "")
+
+	appErr := suite.baseRepository.Save(&context, item)
+	actualWriteItemInput, _ := context.Get(common_constants.WriteTransaction)
+
+	suite.Equal(expectedAppErr, appErr)
+	suite.Len(actualWriteItemInput.(dynamodb.TransactWriteItemsInput).TransactItems, 100)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestSave_ShouldReturnTransactionErrorWhenPayloadIsTooLarge() {
+	context := common_models.NewLambdaContext()
+	transactItems := make([]types.TransactWriteItem, 0)
+	for index := 0; index < 10; index++ {
+		transactItems = append(transactItems, types.TransactWriteItem{
+			Put: &types.Put{
+				TableName: aws.String("someTable"),
+				Item: map[string]types.AttributeValue{
+					"payload": &types.AttributeValueMemberB{Value: make([]byte, 390*1024)},
+				},
+			},
+		})
+	}
+	context.Set(common_constants.WriteTransaction, dynamodb.TransactWriteItemsInput{
+		TransactItems: transactItems,
+	})
+	item := DummyItem{Key1: strings.Repeat("a", 300*1024), Key2: "bar"}
+	expectedAppErr := common_errors.NewTransactionLimitExceededError("write transaction payload cannot exceed 4194304 bytes, rejected transaction item 10", 10, "")
+
+	appErr := suite.baseRepository.Save(&context, item)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestSave_ShouldReturnTransactionErrorWhenItemIsTooLarge() {
+	context := common_models.NewLambdaContext()
+	context.Set(common_constants.WriteTransaction, dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{},
+	})
+	item := DummyItem{Key1: strings.Repeat("a", 401*1024), Key2: "bar"}
+	expectedAppErr := common_errors.NewTransactionLimitExceededError("transaction item 0 exceeds the maximum item size of 409600 bytes", 0, "")
+
+	appErr := suite.baseRepository.Save(&context, item)
+	actualWriteItemInput, _ := context.Get(common_constants.WriteTransaction)
+
+	suite.Equal(expectedAppErr, appErr)
+	suite.Equal(400, appErr.HttpStatus())
+	suite.Empty(actualWriteItemInput.(dynamodb.TransactWriteItemsInput).TransactItems)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestQuery_ShouldSucceed() {
+	context := common_models.NewLambdaContext()
+	startKey := map[string]types.AttributeValue{
+		"pk": &types.AttributeValueMemberS{Value: "somePartition"},
+		"sk": &types.AttributeValueMemberS{Value: "ORDER#1"},
+	}
+	lastEvaluatedKey := map[string]types.AttributeValue{
+		"pk": &types.AttributeValueMemberS{Value: "somePartition"},
+		"sk": &types.AttributeValueMemberS{Value: "ORDER#2"},
+	}
+	continuationToken, _ := common_helpers.EncodeDynamodbContinuationToken(startKey)
+	expectedContinuationToken, _ := common_helpers.EncodeDynamodbContinuationToken(lastEvaluatedKey)
+	filter := expression.Name("status").Equal(expression.Value("OPEN"))
+	query := common_models.DynamodbQuery{
+		PartitionKey: common_models.DynamodbSimplePrimaryKey{
+			KeyName: "pk",
+			Value:   "somePartition",
+		},
+		SortKeyCondition: &common_models.DynamodbSortKeyCondition{
+			KeyName:  "sk",
+			Operator: common_models.DynamodbSortKeyBeginsWith,
+			Values:   []interface{}{"ORDER#"},
+		},
+		Filter:            &filter,
+		IsDescending:      true,
+		Limit:             10,
+		ContinuationToken: continuationToken,
+	}
+	queryInput := dynamodb.QueryInput{
+		TableName:              aws.String("someTable"),
+		ConsistentRead:         aws.Bool(false),
+		KeyConditionExpression: aws.String("(#1 = :1) AND (begins_with (#2, :2))"),
+		FilterExpression:       aws.String("#0 = :0"),
+		ExpressionAttributeNames: map[string]string{
+			"#0": "status",
+			"#1": "pk",
+			"#2": "sk",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":0": &types.AttributeValueMemberS{Value: "OPEN"},
+			":1": &types.AttributeValueMemberS{Value: "somePartition"},
+			":2": &types.AttributeValueMemberS{Value: "ORDER#"},
+		},
+		ScanIndexForward:  aws.Bool(false),
+		Limit:             aws.Int32(10),
+		ExclusiveStartKey: startKey,
+	}
+	items := []map[string]types.AttributeValue{
+		{
+			"pk": &types.AttributeValueMemberS{Value: "somePartition"},
+			"sk": &types.AttributeValueMemberS{Value: "ORDER#2"},
+		},
+	}
+	queryOutput := &dynamodb.QueryOutput{
+		Items:            items,
+		LastEvaluatedKey: lastEvaluatedKey,
+	}
+	expectedResult := common_models.DynamodbQueryResult{
+		Items:             items,
+		ContinuationToken: expectedContinuationToken,
+	}
+
+	suite.dynamodbClient.EXPECT().Query(&context, &queryInput).Return(queryOutput, nil)
+
+	result, appErr := suite.baseRepository.Query(&context, query)
+
+	suite.NoError(appErr)
+	suite.Equal(expectedResult, result)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestQuery_ShouldSucceedWithBetweenSortKeyCondition() {
+	context := common_models.NewLambdaContext()
+	query := common_models.DynamodbQuery{
+		PartitionKey: common_models.DynamodbSimplePrimaryKey{
+			KeyName: "pk",
+			Value:   "somePartition",
+		},
+		SortKeyCondition: &common_models.DynamodbSortKeyCondition{
+			KeyName:  "sk",
+			Operator: common_models.DynamodbSortKeyBetween,
+			Values:   []interface{}{1, 5},
+		},
+		IsConsistentRead: true,
+	}
+	queryInput := dynamodb.QueryInput{
+		TableName:              aws.String("someTable"),
+		ConsistentRead:         aws.Bool(true),
+		KeyConditionExpression: aws.String("(#0 = :0) AND (#1 BETWEEN :1 AND :2)"),
+		ExpressionAttributeNames: map[string]string{
+			"#0": "pk",
+			"#1": "sk",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":0": &types.AttributeValueMemberS{Value: "somePartition"},
+			":1": &types.AttributeValueMemberN{Value: "1"},
+			":2": &types.AttributeValueMemberN{Value: "5"},
+		},
+		ScanIndexForward: aws.Bool(true),
+	}
+	queryOutput := &dynamodb.QueryOutput{
+		Items: []map[string]types.AttributeValue{},
+	}
+	expectedResult := common_models.DynamodbQueryResult{
+		Items: []map[string]types.AttributeValue{},
+	}
+
+	suite.dynamodbClient.EXPECT().Query(&context, &queryInput).Return(queryOutput, nil)
+
+	result, appErr := suite.baseRepository.Query(&context, query)
+
+	suite.NoError(appErr)
+	suite.Equal(expectedResult, result)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestQuery_ShouldReturnInternalServerErrorWhenSortKeyConditionIsInvalid() {
+	context := common_models.NewLambdaContext()
+	query := common_models.DynamodbQuery{
+		PartitionKey: common_models.DynamodbSimplePrimaryKey{
+			KeyName: "pk",
+			Value:   "somePartition",
+		},
+		SortKeyCondition: &common_models.DynamodbSortKeyCondition{
+			KeyName:  "sk",
+			Operator: common_models.DynamodbSortKeyBetween,
+			Values:   []interface{}{1},
+		},
+	}
+	expectedAppErr := common_errors.NewInternalServerError("between sort key condition requires two values")
+
+	_, appErr := suite.baseRepository.Query(&context, query)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestQuery_ShouldReturnBadRequestErrorWhenContinuationTokenIsInvalid() {
+	context := common_models.NewLambdaContext()
+	query := common_models.DynamodbQuery{
+		PartitionKey: common_models.DynamodbSimplePrimaryKey{
+			KeyName: "pk",
+			Value:   "somePartition",
+		},
+		ContinuationToken: "not a token",
+	}
+	expectedAppErr := common_errors.NewBadRequestError("invalid continuation token")
+
+	_, appErr := suite.baseRepository.Query(&context, query)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestQuery_ShouldReturnInternalServerErrorWhenQueryFailed() {
+	context := common_models.NewLambdaContext()
+	query := common_models.DynamodbQuery{
+		PartitionKey: common_models.DynamodbSimplePrimaryKey{
+			KeyName: "pk",
+			Value:   "somePartition",
+		},
+	}
+	cause := errors.New("someErr")
+	expectedAppErr := common_errors.NewInternalServerError("error while querying database")
+
+	suite.dynamodbClient.EXPECT().Query(&context, gomock.Any()).Return(nil, cause)
+
+	_, appErr := suite.baseRepository.Query(&context, query)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestFindByIndexSimpleKey_ShouldSucceedFollowingAllPages() {
+	context := common_models.NewLambdaContext()
+	index := common_models.DynamodbSecondaryIndex{
+		IndexName: "GSI1",
+		IsGlobal:  true,
+	}
+	indexKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "GSI1PK",
+		Value:   "USER#1",
+	}
+	lastEvaluatedKey := map[string]types.AttributeValue{
+		"GSI1PK": &types.AttributeValueMemberS{Value: "USER#1"},
+		"pk":     &types.AttributeValueMemberS{Value: "ORDER#1"},
+	}
+	firstQueryInput := dynamodb.QueryInput{
+		TableName:              aws.String("someTable"),
+		IndexName:              aws.String("GSI1"),
+		ConsistentRead:         aws.Bool(false),
+		KeyConditionExpression: aws.String("#0 = :0"),
+		ProjectionExpression:   aws.String("#1, #2"),
+		ExpressionAttributeNames: map[string]string{
+			"#0": "GSI1PK",
+			"#1": "pk",
+			"#2": "status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":0": &types.AttributeValueMemberS{Value: "USER#1"},
+		},
+		ScanIndexForward: aws.Bool(true),
+	}
+	secondQueryInput := firstQueryInput
+	secondQueryInput.ExclusiveStartKey = lastEvaluatedKey
+	firstItem := map[string]types.AttributeValue{
+		"pk":     &types.AttributeValueMemberS{Value: "ORDER#1"},
+		"status": &types.AttributeValueMemberS{Value: "OPEN"},
+	}
+	secondItem := map[string]types.AttributeValue{
+		"pk":     &types.AttributeValueMemberS{Value: "ORDER#2"},
+		"status": &types.AttributeValueMemberS{Value: "CLOSED"},
+	}
+	expectedItems := []map[string]types.AttributeValue{firstItem, secondItem}
+
+	gomock.InOrder(
+		suite.dynamodbClient.EXPECT().Query(&context, &firstQueryInput).Return(&dynamodb.QueryOutput{
+			Items:            []map[string]types.AttributeValue{firstItem},
+			LastEvaluatedKey: lastEvaluatedKey,
+		}, nil),
+		suite.dynamodbClient.EXPECT().Query(&context, &secondQueryInput).Return(&dynamodb.QueryOutput{
+			Items: []map[string]types.AttributeValue{secondItem},
+		}, nil),
+	)
+
+	items, appErr := suite.baseRepository.FindByIndexSimpleKey(&context, index, indexKey, []string{"pk", "status"}, false)
+
+	suite.NoError(appErr)
+	suite.Equal(expectedItems, items)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestFindByIndexSimpleKey_ShouldReturnInternalServerErrorWhenConsistentReadOnGlobalIndex() {
+	context := common_models.NewLambdaContext()
+	index := common_models.DynamodbSecondaryIndex{
+		IndexName: "GSI1",
+		IsGlobal:  true,
+	}
+	indexKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "GSI1PK",
+		Value:   "USER#1",
+	}
+	expectedAppErr := common_errors.NewInternalServerError("consistent reads are not supported on global secondary indexes")
+
+	_, appErr := suite.baseRepository.FindByIndexSimpleKey(&context, index, indexKey, nil, true)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestFindByIndexComplexKey_ShouldSucceedWithConsistentReadOnLocalIndex() {
+	context := common_models.NewLambdaContext()
+	index := common_models.DynamodbSecondaryIndex{
+		IndexName: "LSI1",
+	}
+	indexKey := common_models.DynamodbComplexPrimaryKey{
+		PartitionKey: common_models.DynamodbSimplePrimaryKey{
+			KeyName: "pk",
+			Value:   "USER#1",
+		},
+		SortKey: common_models.DynamodbSimplePrimaryKey{
+			KeyName: "LSI1SK",
+			Value:   "2021-01-01",
+		},
+	}
+	queryInput := dynamodb.QueryInput{
+		TableName:              aws.String("someTable"),
+		IndexName:              aws.String("LSI1"),
+		ConsistentRead:         aws.Bool(true),
+		KeyConditionExpression: aws.String("(#0 = :0) AND (#1 = :1)"),
+		ExpressionAttributeNames: map[string]string{
+			"#0": "pk",
+			"#1": "LSI1SK",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":0": &types.AttributeValueMemberS{Value: "USER#1"},
+			":1": &types.AttributeValueMemberS{Value: "2021-01-01"},
+		},
+		ScanIndexForward: aws.Bool(true),
+	}
+	item := map[string]types.AttributeValue{
+		"pk":     &types.AttributeValueMemberS{Value: "USER#1"},
+		"LSI1SK": &types.AttributeValueMemberS{Value: "2021-01-01"},
+	}
+	expectedItems := []map[string]types.AttributeValue{item}
+
+	suite.dynamodbClient.EXPECT().Query(&context, &queryInput).Return(&dynamodb.QueryOutput{
+		Items: []map[string]types.AttributeValue{item},
+	}, nil)
+
+	items, appErr := suite.baseRepository.FindByIndexComplexKey(&context, index, indexKey, nil, true)
+
+	suite.NoError(appErr)
+	suite.Equal(expectedItems, items)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestFindByIndexComplexKey_ShouldReturnInternalServerErrorWhenQueryFailed() {
+	context := common_models.NewLambdaContext()
+	index := common_models.DynamodbSecondaryIndex{
+		IndexName: "GSI1",
+		IsGlobal:  true,
+	}
+	indexKey := common_models.DynamodbComplexPrimaryKey{
+		PartitionKey: common_models.DynamodbSimplePrimaryKey{
+			KeyName: "GSI1PK",
+			Value:   "USER#1",
+		},
+		SortKey: common_models.DynamodbSimplePrimaryKey{
+			KeyName: "GSI1SK",
+			Value:   "ORDER#1",
+		},
+	}
+	cause := errors.New("someErr")
+	expectedAppErr := common_errors.NewInternalServerError("error while querying database")
+
+	suite.dynamodbClient.EXPECT().Query(&context, gomock.Any()).Return(nil, cause)
+
+	_, appErr := suite.baseRepository.FindByIndexComplexKey(&context, index, indexKey, nil, false)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestDeleteBySimplePrimaryKey_ShouldSucceedWhenNoTransaction() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "pk",
+		Value:   "someKey",
+	}
+	condition := expression.Name("status").Equal(expression.Value("CLOSED"))
+	options := common_models.DynamodbDeleteOptions{
+		Condition:       &condition,
+		ReturnOldValues: true,
+	}
+	deleteItemInput := dynamodb.DeleteItemInput{
+		TableName:           aws.String("someTable"),
+		ConditionExpression: aws.String("#0 = :0"),
+		ExpressionAttributeNames: map[string]string{
+			"#0": "status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":0": &types.AttributeValueMemberS{Value: "CLOSED"},
+		},
+		Key: map[string]types.AttributeValue{
+			"pk": &types.AttributeValueMemberS{Value: "someKey"},
+		},
+		ReturnValues: types.ReturnValueAllOld,
+	}
+	oldItem := map[string]types.AttributeValue{
+		"pk":     &types.AttributeValueMemberS{Value: "someKey"},
+		"status": &types.AttributeValueMemberS{Value: "CLOSED"},
+	}
+
+	suite.dynamodbClient.EXPECT().DeleteItem(&context, &deleteItemInput).Return(&dynamodb.DeleteItemOutput{Attributes: oldItem}, nil)
+
+	response, appErr := suite.baseRepository.DeleteBySimplePrimaryKey(&context, primaryKey, options)
+
+	suite.NoError(appErr)
+	suite.Equal(oldItem, response)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestDeleteBySimplePrimaryKey_ShouldReturnForbiddenErrorWhenConditionFailed() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "pk",
+		Value:   "someKey",
+	}
+	condition := expression.Name("status").Equal(expression.Value("CLOSED"))
+	options := common_models.DynamodbDeleteOptions{
+		Condition: &condition,
+	}
+	cause := &types.ConditionalCheckFailedException{}
+	expectedAppErr := common_errors.NewForbiddenError("item does not satisfy delete condition")
+
+	suite.dynamodbClient.EXPECT().DeleteItem(&context, gomock.Any()).Return(nil, cause)
+
+	_, appErr := suite.baseRepository.DeleteBySimplePrimaryKey(&context, primaryKey, options)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestDeleteByComplexPrimaryKey_ShouldSucceedWhenNoTransaction() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbComplexPrimaryKey{
+		PartitionKey: common_models.DynamodbSimplePrimaryKey{
+			KeyName: "somePartitionKey",
+			Value:   "somePartitionValue",
+		},
+		SortKey: common_models.DynamodbSimplePrimaryKey{
+			KeyName: "someSortKey",
+			Value:   "someSortValue",
+		},
+	}
+	deleteItemInput := dynamodb.DeleteItemInput{
+		TableName: aws.String("someTable"),
+		Key: map[string]types.AttributeValue{
+			"somePartitionKey": &types.AttributeValueMemberS{Value: "somePartitionValue"},
+			"someSortKey":      &types.AttributeValueMemberS{Value: "someSortValue"},
+		},
+	}
+
+	suite.dynamodbClient.EXPECT().DeleteItem(&context, &deleteItemInput).Return(&dynamodb.DeleteItemOutput{}, nil)
+
+	response, appErr := suite.baseRepository.DeleteByComplexPrimaryKey(&context, primaryKey, common_models.DynamodbDeleteOptions{})
+
+	suite.NoError(appErr)
+	suite.Nil(response)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestDeleteByComplexPrimaryKey_ShouldReturnInternalServerErrorWhenDeleteItemFailed() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbComplexPrimaryKey{
+		PartitionKey: common_models.DynamodbSimplePrimaryKey{
+			KeyName: "somePartitionKey",
+			Value:   "somePartitionValue",
+		},
+		SortKey: common_models.DynamodbSimplePrimaryKey{
+			KeyName: "someSortKey",
+			Value:   "someSortValue",
+		},
+	}
+	cause := errors.New("someErr")
+	expectedAppErr := common_errors.NewInternalServerError("error while deleting from database")
+
+	suite.dynamodbClient.EXPECT().DeleteItem(&context, gomock.Any()).Return(nil, cause)
+
+	_, appErr := suite.baseRepository.DeleteByComplexPrimaryKey(&context, primaryKey, common_models.DynamodbDeleteOptions{})
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestDeleteByComplexPrimaryKey_ShouldSucceedWhenTransaction() {
+	context := common_models.NewLambdaContext()
+	transactWriteItemsInput := dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{},
+	}
+	context.Set(common_constants.WriteTransaction, transactWriteItemsInput)
+	primaryKey := common_models.DynamodbComplexPrimaryKey{
+		PartitionKey: common_models.DynamodbSimplePrimaryKey{
+			KeyName: "somePartitionKey",
+			Value:   "somePartitionValue",
+		},
+		SortKey: common_models.DynamodbSimplePrimaryKey{
+			KeyName: "someSortKey",
+			Value:   "someSortValue",
+		},
+	}
+	condition := expression.AttributeExists(expression.Name("somePartitionKey"))
+	options := common_models.DynamodbDeleteOptions{
+		Condition: &condition,
+	}
+	expectedWriteItemsInput := dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{
+				Delete: &types.Delete{
+					TableName:           aws.String("someTable"),
+					ConditionExpression: aws.String("attribute_exists (#0)"),
+					ExpressionAttributeNames: map[string]string{
+						"#0": "somePartitionKey",
+					},
+					Key: map[string]types.AttributeValue{
+						"somePartitionKey": &types.AttributeValueMemberS{Value: "somePartitionValue"},
+						"someSortKey":      &types.AttributeValueMemberS{Value: "someSortValue"},
+					},
+				},
+			},
+		},
+	}
+
+	response, appErr := suite.baseRepository.DeleteByComplexPrimaryKey(&context, primaryKey, options)
+	actualWriteItemsInput, exists := context.Get(common_constants.WriteTransaction)
+
+	suite.NoError(appErr)
+	suite.Nil(response)
+	suite.True(exists)
+	suite.Equal(expectedWriteItemsInput, actualWriteItemsInput)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestUpdateBySimplePrimaryKey_ShouldSucceedWhenNoTransaction() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "pk",
+		Value:   "someKey",
+	}
+	condition := expression.AttributeExists(expression.Name("pk"))
+	update := common_models.DynamodbUpdate{
+		Operations: []common_models.DynamodbUpdateOperation{
+			{Action: common_models.DynamodbUpdateSet, AttributeName: "status", Value: "OPEN"},
+			{Action: common_models.DynamodbUpdateListAppend, AttributeName: "history", Value: []string{"opened"}},
+			{Action: common_models.DynamodbUpdateRemove, AttributeName: "closedAt"},
+			{Action: common_models.DynamodbUpdateAdd, AttributeName: "counter", Value: 1},
+			{Action: common_models.DynamodbUpdateDelete, AttributeName: "tags", Value: &types.AttributeValueMemberSS{Value: []string{"old"}}},
+		},
+		Condition: &condition,
+	}
+	updateItemInput := dynamodb.UpdateItemInput{
+		TableName:           aws.String("someTable"),
+		UpdateExpression:    aws.String("ADD #1 :0\nDELETE #2 :1\nREMOVE #3\nSET #4 = :2, #5 = list_append(if_not_exists(#5, :3), :4)\n"),
+		ConditionExpression: aws.String("attribute_exists (#0)"),
+		ExpressionAttributeNames: map[string]string{
+			"#0": "pk",
+			"#1": "counter",
+			"#2": "tags",
+			"#3": "closedAt",
+			"#4": "status",
+			"#5": "history",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":0": &types.AttributeValueMemberN{Value: "1"},
+			":1": &types.AttributeValueMemberSS{Value: []string{"old"}},
+			":2": &types.AttributeValueMemberS{Value: "OPEN"},
+			":3": &types.AttributeValueMemberL{Value: []types.AttributeValue{}},
+			":4": &types.AttributeValueMemberL{Value: []types.AttributeValue{
+				&types.AttributeValueMemberS{Value: "opened"},
+			}},
+		},
+		Key: map[string]types.AttributeValue{
+			"pk": &types.AttributeValueMemberS{Value: "someKey"},
+		},
+		ReturnValues: types.ReturnValueAllNew,
+	}
+	updatedItem := map[string]types.AttributeValue{
+		"pk":     &types.AttributeValueMemberS{Value: "someKey"},
+		"status": &types.AttributeValueMemberS{Value: "OPEN"},
+	}
+
+	suite.dynamodbClient.EXPECT().UpdateItem(&context, &updateItemInput).Return(&dynamodb.UpdateItemOutput{Attributes: updatedItem}, nil)
+
+	response, appErr := suite.baseRepository.UpdateBySimplePrimaryKey(&context, primaryKey, update)
+
+	suite.NoError(appErr)
+	suite.Equal(updatedItem, response)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestUpdateBySimplePrimaryKey_ShouldReturnInternalServerErrorWhenNoOperations() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "pk",
+		Value:   "someKey",
+	}
+	expectedAppErr := common_errors.NewInternalServerError("update requires at least one operation")
+
+	_, appErr := suite.baseRepository.UpdateBySimplePrimaryKey(&context, primaryKey, common_models.DynamodbUpdate{})
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestUpdateByComplexPrimaryKey_ShouldReturnForbiddenErrorWhenConditionFailed() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbComplexPrimaryKey{
+		PartitionKey: common_models.DynamodbSimplePrimaryKey{
+			KeyName: "somePartitionKey",
+			Value:   "somePartitionValue",
+		},
+		SortKey: common_models.DynamodbSimplePrimaryKey{
+			KeyName: "someSortKey",
+			Value:   "someSortValue",
+		},
+	}
+	update := common_models.DynamodbUpdate{
+		Operations: []common_models.DynamodbUpdateOperation{
+			{Action: common_models.DynamodbUpdateSet, AttributeName: "status", Value: "OPEN"},
+		},
+	}
+	cause := &types.ConditionalCheckFailedException{}
+	expectedAppErr := common_errors.NewForbiddenError("item does not satisfy update condition")
+
+	suite.dynamodbClient.EXPECT().UpdateItem(&context, gomock.Any()).Return(nil, cause)
+
+	_, appErr := suite.baseRepository.UpdateByComplexPrimaryKey(&context, primaryKey, update)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestUpdateByComplexPrimaryKey_ShouldReturnInternalServerErrorWhenUpdateItemFailed() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbComplexPrimaryKey{
+		PartitionKey: common_models.DynamodbSimplePrimaryKey{
+			KeyName: "somePartitionKey",
+			Value:   "somePartitionValue",
+		},
+		SortKey: common_models.DynamodbSimplePrimaryKey{
+			KeyName: "someSortKey",
+			Value:   "someSortValue",
+		},
+	}
+	update := common_models.DynamodbUpdate{
+		Operations: []common_models.DynamodbUpdateOperation{
+			{Action: common_models.DynamodbUpdateSet, AttributeName: "status", Value: "OPEN"},
+		},
+	}
+	cause := errors.New("someErr")
+	expectedAppErr := common_errors.NewInternalServerError("error while updating database")
+
+	suite.dynamodbClient.EXPECT().UpdateItem(&context, gomock.Any()).Return(nil, cause)
+
+	_, appErr := suite.baseRepository.UpdateByComplexPrimaryKey(&context, primaryKey, update)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestUpdateByComplexPrimaryKey_ShouldSucceedWhenTransaction() {
+	context := common_models.NewLambdaContext()
+	transactWriteItemsInput := dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{},
+	}
+	context.Set(common_constants.WriteTransaction, transactWriteItemsInput)
+	primaryKey := common_models.DynamodbComplexPrimaryKey{
+		PartitionKey: common_models.DynamodbSimplePrimaryKey{
+			KeyName: "somePartitionKey",
+			Value:   "somePartitionValue",
+		},
+		SortKey: common_models.DynamodbSimplePrimaryKey{
+			KeyName: "someSortKey",
+			Value:   "someSortValue",
+		},
+	}
+	update := common_models.DynamodbUpdate{
+		Operations: []common_models.DynamodbUpdateOperation{
+			{Action: common_models.DynamodbUpdateAdd, AttributeName: "counter", Value: 1},
+		},
+	}
+	expectedWriteItemsInput := dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{
+				Update: &types.Update{
+					TableName:        aws.String("someTable"),
+					UpdateExpression: aws.String("ADD #0 :0\n"),
+					ExpressionAttributeNames: map[string]string{
+						"#0": "counter",
+					},
+					ExpressionAttributeValues: map[string]types.AttributeValue{
+						":0": &types.AttributeValueMemberN{Value: "1"},
+					},
+					Key: map[string]types.AttributeValue{
+						"somePartitionKey": &types.AttributeValueMemberS{Value: "somePartitionValue"},
+						"someSortKey":      &types.AttributeValueMemberS{Value: "someSortValue"},
+					},
+				},
+			},
+		},
+	}
+
+	response, appErr := suite.baseRepository.UpdateByComplexPrimaryKey(&context, primaryKey, update)
+	actualWriteItemsInput, exists := context.Get(common_constants.WriteTransaction)
+
+	suite.NoError(appErr)
+	suite.Nil(response)
+	suite.True(exists)
+	suite.Equal(expectedWriteItemsInput, actualWriteItemsInput)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestSave_ShouldRequireMissingVersionWhenVersionedItemIsNew() {
+	context := common_models.NewLambdaContext()
+	item := VersionedDummyItem{Key1: "foo"}
+	putItemInput := dynamodb.PutItemInput{
+		TableName:           aws.String("someTable"),
+		ConditionExpression: aws.String("attribute_not_exists (#0)"),
+		ExpressionAttributeNames: map[string]string{
+			"#0": "version",
+		},
+		Item: map[string]types.AttributeValue{
+			"key1":    &types.AttributeValueMemberS{Value: "foo"},
+			"version": &types.AttributeValueMemberN{Value: "1"},
+		},
+	}
+
+	suite.dynamodbClient.EXPECT().PutItem(&context, &putItemInput).Return(&dynamodb.PutItemOutput{}, nil)
+
+	appErr := suite.versionedRepository.Save(&context, item)
+
+	suite.NoError(appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestSave_ShouldRequireExpectedVersionWhenVersionedItemExists() {
+	context := common_models.NewLambdaContext()
+	item := VersionedDummyItem{Key1: "foo", Version: 3}
+	putItemInput := dynamodb.PutItemInput{
+		TableName:           aws.String("someTable"),
+		ConditionExpression: aws.String("#0 = :0"),
+		ExpressionAttributeNames: map[string]string{
+			"#0": "version",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":0": &types.AttributeValueMemberN{Value: "3"},
+		},
+		Item: map[string]types.AttributeValue{
+			"key1":    &types.AttributeValueMemberS{Value: "foo"},
+			"version": &types.AttributeValueMemberN{Value: "4"},
+		},
+	}
+
+	suite.dynamodbClient.EXPECT().PutItem(&context, &putItemInput).Return(&dynamodb.PutItemOutput{}, nil)
+
+	appErr := suite.versionedRepository.Save(&context, item)
+
+	suite.NoError(appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestSave_ShouldReturnPreconditionFailedErrorWhenVersionIsStale() {
+	context := common_models.NewLambdaContext()
+	item := VersionedDummyItem{Key1: "foo", Version: 3}
+	cause := &types.ConditionalCheckFailedException{}
+	expectedAppErr := common_errors.NewPreconditionFailedError("item version is stale")
+
+	suite.dynamodbClient.EXPECT().PutItem(&context, gomock.Any()).Return(nil, cause)
+
+	appErr := suite.versionedRepository.Save(&context, item)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestSave_ShouldMarkVersionCheckWhenVersionedTransaction() {
+	context := common_models.NewLambdaContext()
+	context.Set(common_constants.WriteTransaction, dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{},
+	})
+	item := VersionedDummyItem{Key1: "foo", Version: 3}
+	expectedItemsMetadata := []common_models.DynamodbTransactWriteItemMetadata{
+		{IsVersionCheck: true},
+	}
+
+	appErr := suite.versionedRepository.Save(&context, item)
+	actualItemsMetadata, _ := context.Get(common_constants.WriteTransactionItems)
+
+	suite.NoError(appErr)
+	suite.Equal(expectedItemsMetadata, actualItemsMetadata)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestSaveIfNotPresentWithSimplePrimaryKey_ShouldInitializeVersionWhenVersioned() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "pk",
+		Value:   "someKey",
+	}
+	item := VersionedDummyItem{Key1: "foo", Version: 7}
+	putItemInput := dynamodb.PutItemInput{
+		TableName:           aws.String("someTable"),
+		ConditionExpression: aws.String("attribute_not_exists (#0)"),
+		ExpressionAttributeNames: map[string]string{
+			"#0": "pk",
+		},
+		Item: map[string]types.AttributeValue{
+			"pk":      &types.AttributeValueMemberS{Value: "someKey"},
+			"key1":    &types.AttributeValueMemberS{Value: "foo"},
+			"version": &types.AttributeValueMemberN{Value: "1"},
+		},
+	}
+
+	suite.dynamodbClient.EXPECT().PutItem(&context, &putItemInput).Return(&dynamodb.PutItemOutput{}, nil)
+
+	appErr := suite.versionedRepository.SaveIfNotPresentWithSimplePrimaryKey(&context, primaryKey, item)
+
+	suite.NoError(appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestUpdateBySimplePrimaryKey_ShouldIncrementVersionAndCheckExpectedVersion() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "pk",
+		Value:   "someKey",
+	}
+	expectedVersion := int64(2)
+	update := common_models.DynamodbUpdate{
+		Operations: []common_models.DynamodbUpdateOperation{
+			{Action: common_models.DynamodbUpdateSet, AttributeName: "status", Value: "OPEN"},
+		},
+		ExpectedVersion: &expectedVersion,
+	}
+	updateItemInput := dynamodb.UpdateItemInput{
+		TableName:           aws.String("someTable"),
+		UpdateExpression:    aws.String("ADD #0 :1\nSET #1 = :2\n"),
+		ConditionExpression: aws.String("#0 = :0"),
+		ExpressionAttributeNames: map[string]string{
+			"#0": "version",
+			"#1": "status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":0": &types.AttributeValueMemberN{Value: "2"},
+			":1": &types.AttributeValueMemberN{Value: "1"},
+			":2": &types.AttributeValueMemberS{Value: "OPEN"},
+		},
+		Key: map[string]types.AttributeValue{
+			"pk": &types.AttributeValueMemberS{Value: "someKey"},
+		},
+		ReturnValues: types.ReturnValueAllNew,
+	}
+
+	suite.dynamodbClient.EXPECT().UpdateItem(&context, &updateItemInput).Return(&dynamodb.UpdateItemOutput{}, nil)
+
+	_, appErr := suite.versionedRepository.UpdateBySimplePrimaryKey(&context, primaryKey, update)
+
+	suite.NoError(appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestUpdateBySimplePrimaryKey_ShouldReturnPreconditionFailedErrorWhenVersionIsStale() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "pk",
+		Value:   "someKey",
+	}
+	expectedVersion := int64(2)
+	update := common_models.DynamodbUpdate{
+		Operations: []common_models.DynamodbUpdateOperation{
+			{Action: common_models.DynamodbUpdateSet, AttributeName: "status", Value: "OPEN"},
+		},
+		ExpectedVersion: &expectedVersion,
+	}
+	cause := &types.ConditionalCheckFailedException{}
+	expectedAppErr := common_errors.NewPreconditionFailedError("item version is stale")
+
+	suite.dynamodbClient.EXPECT().UpdateItem(&context, gomock.Any()).Return(nil, cause)
+
+	_, appErr := suite.versionedRepository.UpdateBySimplePrimaryKey(&context, primaryKey, update)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestUpdateBySimplePrimaryKey_ShouldReturnInternalServerErrorWhenExpectedVersionWithoutVersioning() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "pk",
+		Value:   "someKey",
+	}
+	expectedVersion := int64(2)
+	update := common_models.DynamodbUpdate{
+		Operations: []common_models.DynamodbUpdateOperation{
+			{Action: common_models.DynamodbUpdateSet, AttributeName: "status", Value: "OPEN"},
+		},
+		ExpectedVersion: &expectedVersion,
+	}
+	expectedAppErr := common_errors.NewInternalServerError("optimistic locking is not enabled for this repository")
+
+	_, appErr := suite.baseRepository.UpdateBySimplePrimaryKey(&context, primaryKey, update)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestSaveIfNotPresent_ShouldConditionOnSchemaKeys() {
+	context := common_models.NewLambdaContext()
+	item := SchemaDummyItem{Key1: "foo", Key2: "bar", Value: "baz"}
+	putItemInput := dynamodb.PutItemInput{
+		TableName:           aws.String("someTable"),
+		ConditionExpression: aws.String("(attribute_not_exists (#0)) AND (attribute_not_exists (#1))"),
+		ExpressionAttributeNames: map[string]string{
+			"#0": "key1",
+			"#1": "key2",
+		},
+		Item: map[string]types.AttributeValue{
+			"key1":  &types.AttributeValueMemberS{Value: "foo"},
+			"key2":  &types.AttributeValueMemberS{Value: "bar"},
+			"value": &types.AttributeValueMemberS{Value: "baz"},
+		},
+	}
+
+	suite.dynamodbClient.EXPECT().PutItem(&context, &putItemInput).Return(&dynamodb.PutItemOutput{}, nil)
+
+	appErr := suite.schemaRepository.SaveIfNotPresent(&context, item)
+
+	suite.NoError(appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestSaveIfNotPresent_ShouldReturnInternalServerErrorWhenNoSchema() {
+	context := common_models.NewLambdaContext()
+	expectedAppErr := common_errors.NewInternalServerError("a table schema is required to save an item if not present")
+
+	appErr := suite.baseRepository.SaveIfNotPresent(&context, SchemaDummyItem{Key1: "foo", Key2: "bar"})
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestSave_ShouldReturnBadRequestErrorWhenSchemaKeyIsEmpty() {
+	context := common_models.NewLambdaContext()
+	expectedAppErr := common_errors.NewBadRequestError("primary key attribute key2 must not be empty")
+
+	appErr := suite.schemaRepository.Save(&context, SchemaDummyItem{Key1: "foo"})
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestFindBySimplePrimaryKey_ShouldReturnInternalServerErrorWhenKeyIsNotInSchema() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "someKey",
+		Value:   "someValue",
+	}
+	expectedAppErr := common_errors.NewInternalServerError("attribute someKey is not part of the primary key of table someTable")
+
+	_, appErr := suite.schemaRepository.FindBySimplePrimaryKey(&context, primaryKey, false)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestFindBySimplePrimaryKey_ShouldReturnBadRequestErrorWhenSortKeyIsMissing() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "key1",
+		Value:   "foo",
+	}
+	expectedAppErr := common_errors.NewBadRequestError("primary key attribute key2 is missing")
+
+	_, appErr := suite.schemaRepository.FindBySimplePrimaryKey(&context, primaryKey, false)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestExtractPrimaryKey_ShouldReturnSchemaKeys() {
+	expectedKey := map[string]types.AttributeValue{
+		"key1": &types.AttributeValueMemberS{Value: "foo"},
+		"key2": &types.AttributeValueMemberS{Value: "bar"},
+	}
+
+	key, appErr := suite.schemaRepository.ExtractPrimaryKey(SchemaDummyItem{Key1: "foo", Key2: "bar", Value: "baz"})
+
+	suite.NoError(appErr)
+	suite.Equal(expectedKey, key)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestDeleteByItem_ShouldDeleteBySchemaKeys() {
+	context := common_models.NewLambdaContext()
+	deleteItemInput := dynamodb.DeleteItemInput{
+		TableName: aws.String("someTable"),
+		Key: map[string]types.AttributeValue{
+			"key1": &types.AttributeValueMemberS{Value: "foo"},
+			"key2": &types.AttributeValueMemberS{Value: "bar"},
+		},
+	}
+
+	suite.dynamodbClient.EXPECT().DeleteItem(&context, &deleteItemInput).Return(&dynamodb.DeleteItemOutput{}, nil)
+
+	_, appErr := suite.schemaRepository.DeleteByItem(&context, SchemaDummyItem{Key1: "foo", Key2: "bar", Value: "baz"}, common_models.DynamodbDeleteOptions{})
+
+	suite.NoError(appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestQuery_ShouldReturnInternalServerErrorWhenPartitionKeyIsNotInSchema() {
+	context := common_models.NewLambdaContext()
+	query := common_models.DynamodbQuery{
+		PartitionKey: common_models.DynamodbSimplePrimaryKey{KeyName: "key2", Value: "bar"},
+	}
+	expectedAppErr := common_errors.NewInternalServerError("query partition key must be key1")
+
+	_, appErr := suite.schemaRepository.Query(&context, query)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestQuery_ShouldReturnInternalServerErrorWhenIndexIsNotInSchema() {
+	context := common_models.NewLambdaContext()
+	query := common_models.DynamodbQuery{
+		Index:        &common_models.DynamodbSecondaryIndex{IndexName: "gsi1"},
+		PartitionKey: common_models.DynamodbSimplePrimaryKey{KeyName: "key2", Value: "bar"},
+	}
+	expectedAppErr := common_errors.NewInternalServerError("index gsi1 is not declared in the table schema")
+
+	_, appErr := suite.schemaRepository.Query(&context, query)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestQuery_ShouldAcceptComposedKeys() {
+	context := common_models.NewLambdaContext()
+	userFormat, _ := common_helpers.NewDynamodbKeyFormat(common_helpers.LiteralKeySegment("USER"), common_helpers.IntegerKeySegment("userId", 0))
+	orderFormat, _ := common_helpers.NewDynamodbKeyFormat(common_helpers.LiteralKeySegment("ORDER"), common_helpers.StringKeySegment("date"), common_helpers.StringKeySegment("id"))
+	partitionKey, _ := common_helpers.ComposeDynamodbPrimaryKey("pk", userFormat, common_models.DynamodbKeyValues{"userId": 123})
+	sortKeyCondition, _ := common_helpers.ComposeDynamodbBeginsWithCondition("sk", orderFormat, common_models.DynamodbKeyValues{"date": "2024-01-01"})
+	query := common_models.DynamodbQuery{
+		PartitionKey:     partitionKey,
+		SortKeyCondition: &sortKeyCondition,
+	}
+	queryInput := dynamodb.QueryInput{
+		TableName:              aws.String("someTable"),
+		KeyConditionExpression: aws.String("(#0 = :0) AND (begins_with (#1, :1))"),
+		ExpressionAttributeNames: map[string]string{
+			"#0": "pk",
+			"#1": "sk",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":0": &types.AttributeValueMemberS{Value: "USER#123"},
+			":1": &types.AttributeValueMemberS{Value: "ORDER#2024-01-01#"},
+		},
+		ScanIndexForward: aws.Bool(true),
+		ConsistentRead:   aws.Bool(false),
+	}
+
+	suite.dynamodbClient.EXPECT().Query(&context, &queryInput).Return(&dynamodb.QueryOutput{}, nil)
+
+	_, appErr := suite.baseRepository.Query(&context, query)
+
+	suite.NoError(appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestFindByComplexPrimaryKey_ShouldAcceptComposedKeys() {
+	context := common_models.NewLambdaContext()
+	userFormat, _ := common_helpers.NewDynamodbKeyFormat(common_helpers.LiteralKeySegment("USER"), common_helpers.IntegerKeySegment("userId", 0))
+	orderFormat, _ := common_helpers.NewDynamodbKeyFormat(common_helpers.LiteralKeySegment("ORDER"), common_helpers.StringKeySegment("date"), common_helpers.StringKeySegment("id"))
+	primaryKey, _ := common_helpers.ComposeDynamodbComplexPrimaryKey("pk", userFormat, "sk", orderFormat, common_models.DynamodbKeyValues{"userId": 123, "date": "2024-01-01", "id": "abc"})
+	getItemInput := dynamodb.GetItemInput{
+		TableName:      aws.String("someTable"),
+		ConsistentRead: aws.Bool(false),
+		Key: map[string]types.AttributeValue{
+			"pk": &types.AttributeValueMemberS{Value: "USER#123"},
+			"sk": &types.AttributeValueMemberS{Value: "ORDER#2024-01-01#abc"},
+		},
+	}
+
+	suite.dynamodbClient.EXPECT().GetItem(&context, &getItemInput).Return(&dynamodb.GetItemOutput{}, nil)
+
+	_, appErr := suite.baseRepository.FindByComplexPrimaryKey(&context, primaryKey, false)
+
+	suite.NoError(appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestSave_ShouldFillAuditAttributesFromContextPrincipal() {
+	context := common_models.NewLambdaContext()
+	context.Set(common_constants.AuditPrincipal, "someUser")
+	putItemInput := dynamodb.PutItemInput{
+		TableName: aws.String("someTable"),
+		Item: map[string]types.AttributeValue{
+			"key1":      &types.AttributeValueMemberS{Value: "foo"},
+			"key2":      &types.AttributeValueMemberS{Value: "bar"},
+			"createdAt": &types.AttributeValueMemberS{Value: "2024-01-02T03:04:05Z"},
+			"createdBy": &types.AttributeValueMemberS{Value: "someUser"},
+			"updatedAt": &types.AttributeValueMemberS{Value: "2024-01-02T03:04:05Z"},
+			"updatedBy": &types.AttributeValueMemberS{Value: "someUser"},
+		},
+	}
+
+	suite.dynamodbClient.EXPECT().PutItem(&context, &putItemInput).Return(&dynamodb.PutItemOutput{}, nil)
+
+	appErr := suite.auditedRepository.Save(&context, DummyItem{Key1: "foo", Key2: "bar"})
+
+	suite.NoError(appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestSave_ShouldPreserveCreatedAuditAttributesWhenPresent() {
+	type auditedItem struct {
+		Key1      string `dynamodbav:"key1"`
+		CreatedAt string `dynamodbav:"createdAt"`
+		CreatedBy string `dynamodbav:"createdBy"`
+	}
+	context := common_models.NewLambdaContext()
+	putItemInput := dynamodb.PutItemInput{
+		TableName: aws.String("someTable"),
+		Item: map[string]types.AttributeValue{
+			"key1":      &types.AttributeValueMemberS{Value: "foo"},
+			"createdAt": &types.AttributeValueMemberS{Value: "2020-01-01T00:00:00Z"},
+			"createdBy": &types.AttributeValueMemberS{Value: "otherUser"},
+			"updatedAt": &types.AttributeValueMemberS{Value: "2024-01-02T03:04:05Z"},
+			"updatedBy": &types.AttributeValueMemberS{Value: "system"},
+		},
+	}
+
+	suite.dynamodbClient.EXPECT().PutItem(&context, &putItemInput).Return(&dynamodb.PutItemOutput{}, nil)
+
+	appErr := suite.auditedRepository.Save(&context, auditedItem{Key1: "foo", CreatedAt: "2020-01-01T00:00:00Z", CreatedBy: "otherUser"})
+
+	suite.NoError(appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestSave_ShouldFillCreatedAuditAttributesWhenZeroValues() {
+	type auditedItem struct {
+		Key1      string    `dynamodbav:"key1"`
+		CreatedAt time.Time `dynamodbav:"createdAt"`
+		CreatedBy string    `dynamodbav:"createdBy"`
+	}
+	context := common_models.NewLambdaContext()
+	putItemInput := dynamodb.PutItemInput{
+		TableName: aws.String("someTable"),
+		Item: map[string]types.AttributeValue{
+			"key1":      &types.AttributeValueMemberS{Value: "foo"},
+			"createdAt": &types.AttributeValueMemberS{Value: "2024-01-02T03:04:05Z"},
+			"createdBy": &types.AttributeValueMemberS{Value: "system"},
+			"updatedAt": &types.AttributeValueMemberS{Value: "2024-01-02T03:04:05Z"},
+			"updatedBy": &types.AttributeValueMemberS{Value: "system"},
+		},
+	}
+
+	suite.dynamodbClient.EXPECT().PutItem(&context, &putItemInput).Return(&dynamodb.PutItemOutput{}, nil)
+
+	appErr := suite.auditedRepository.Save(&context, auditedItem{Key1: "foo"})
+
+	suite.NoError(appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestUpdateBySimplePrimaryKey_ShouldPreserveCreatedAuditAttributes() {
+	context := common_models.NewLambdaContext()
+	context.Set(common_constants.AuditPrincipal, "someUser")
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "pk",
+		Value:   "someKey",
+	}
+	update := common_models.DynamodbUpdate{
+		Operations: []common_models.DynamodbUpdateOperation{
+			{Action: common_models.DynamodbUpdateSet, AttributeName: "status", Value: "OPEN"},
+		},
+	}
+	updateItemInput := dynamodb.UpdateItemInput{
+		TableName:        aws.String("someTable"),
+		UpdateExpression: aws.String("SET #0 = :0, #1 = if_not_exists(#1, :1), #2 = if_not_exists(#2, :2), #3 = :3, #4 = :4\n"),
+		ExpressionAttributeNames: map[string]string{
+			"#0": "status",
+			"#1": "createdAt",
+			"#2": "createdBy",
+			"#3": "updatedAt",
+			"#4": "updatedBy",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":0": &types.AttributeValueMemberS{Value: "OPEN"},
+			":1": &types.AttributeValueMemberS{Value: "2024-01-02T03:04:05Z"},
+			":2": &types.AttributeValueMemberS{Value: "someUser"},
+			":3": &types.AttributeValueMemberS{Value: "2024-01-02T03:04:05Z"},
+			":4": &types.AttributeValueMemberS{Value: "someUser"},
+		},
+		Key: map[string]types.AttributeValue{
+			"pk": &types.AttributeValueMemberS{Value: "someKey"},
+		},
+		ReturnValues: types.ReturnValueAllNew,
+	}
+
+	suite.dynamodbClient.EXPECT().UpdateItem(&context, &updateItemInput).Return(&dynamodb.UpdateItemOutput{}, nil)
+
+	_, appErr := suite.auditedRepository.UpdateBySimplePrimaryKey(&context, primaryKey, update)
+
+	suite.NoError(appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestSave_ShouldFillAuditAttributesInsideWriteTransaction() {
+	context := common_models.NewLambdaContext()
+	context.Set(common_constants.WriteTransaction, dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{},
+	})
+	context.Set(common_constants.AuditPrincipal, "someUser")
+	expectedItem := map[string]types.AttributeValue{
+		"key1":      &types.AttributeValueMemberS{Value: "foo"},
+		"key2":      &types.AttributeValueMemberS{Value: "bar"},
+		"createdAt": &types.AttributeValueMemberS{Value: "2024-01-02T03:04:05Z"},
+		"createdBy": &types.AttributeValueMemberS{Value: "someUser"},
+		"updatedAt": &types.AttributeValueMemberS{Value: "2024-01-02T03:04:05Z"},
+		"updatedBy": &types.AttributeValueMemberS{Value: "someUser"},
+	}
+
+	appErr := suite.auditedRepository.Save(&context, DummyItem{Key1: "foo", Key2: "bar"})
+	transaction, _ := context.Get(common_constants.WriteTransaction)
+
+	suite.NoError(appErr)
+	suite.Equal(expectedItem, transaction.(dynamodb.TransactWriteItemsInput).TransactItems[0].Put.Item)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestDeleteBySimplePrimaryKey_ShouldMarkItemAsDeletedWhenSoftDelete() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "pk",
+		Value:   "someKey",
+	}
+	updateItemInput := dynamodb.UpdateItemInput{
+		TableName:           aws.String("someTable"),
+		UpdateExpression:    aws.String("SET #0 = :1, #2 = :2\n"),
+		ConditionExpression: aws.String("((attribute_not_exists (#0)) OR (attribute_type (#0, :0))) AND (attribute_exists (#1))"),
+		ExpressionAttributeNames: map[string]string{
+			"#0": "deletedAt",
+			"#1": "pk",
+			"#2": "ttl",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":0": &types.AttributeValueMemberS{Value: "NULL"},
+			":1": &types.AttributeValueMemberS{Value: "2024-01-02T03:04:05Z"},
+			":2": &types.AttributeValueMemberN{Value: "1704251045"},
+		},
+		Key: map[string]types.AttributeValue{
+			"pk": &types.AttributeValueMemberS{Value: "someKey"},
+		},
+	}
+
+	suite.dynamodbClient.EXPECT().UpdateItem(&context, &updateItemInput).Return(&dynamodb.UpdateItemOutput{}, nil)
+
+	_, appErr := suite.softDeleteRepository.DeleteBySimplePrimaryKey(&context, primaryKey, common_models.DynamodbDeleteOptions{})
+
+	suite.NoError(appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestDeleteBySimplePrimaryKey_ShouldReturnNotFoundErrorWhenSoftDeletedItemIsMissing() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "pk",
+		Value:   "someKey",
+	}
+	expectedAppErr := common_errors.NewNotFoundError("item not found or already deleted")
+
+	suite.dynamodbClient.EXPECT().UpdateItem(&context, gomock.Any()).Return(nil, &types.ConditionalCheckFailedException{})
+
+	_, appErr := suite.softDeleteRepository.DeleteBySimplePrimaryKey(&context, primaryKey, common_models.DynamodbDeleteOptions{})
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestDeleteBySimplePrimaryKey_ShouldReturnForbiddenErrorWhenSoftDeleteConditionFails() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "pk",
+		Value:   "someKey",
+	}
+	condition := expression.Name("status").Equal(expression.Value("CLOSED"))
+	expectedAppErr := common_errors.NewForbiddenError("item does not satisfy delete condition")
+
+	suite.dynamodbClient.EXPECT().UpdateItem(&context, gomock.Any()).Return(nil, &types.ConditionalCheckFailedException{})
+
+	_, appErr := suite.softDeleteRepository.DeleteBySimplePrimaryKey(&context, primaryKey, common_models.DynamodbDeleteOptions{Condition: &condition})
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestDeleteBySimplePrimaryKey_ShouldDecompressOldValuesWhenSoftDelete() {
+	context := common_models.NewLambdaContext()
+	repository := common_repositories.NewDynamodbBaseRepository(suite.dynamodbClient, "someTable",
+		common_repositories.WithSoftDelete(common_models.DynamodbSoftDeleteConfig{}),
+		common_repositories.WithCompression(common_models.DynamodbCompressionConfig{
+			Attributes: []string{"payload"},
+			MinSize:    10,
+		}))
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "key1",
+		Value:   "foo",
+	}
+	payload := strings.Repeat("someValue", 20)
+	var storedItem map[string]types.AttributeValue
+	expectedItem := map[string]types.AttributeValue{
+		"key1":    &types.AttributeValueMemberS{Value: "foo"},
+		"payload": &types.AttributeValueMemberS{Value: payload},
+	}
+
+	suite.dynamodbClient.EXPECT().PutItem(&context, gomock.Any()).DoAndReturn(func(_ *common_models.LambdaContext, input *dynamodb.PutItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+		storedItem = input.Item
+		return &dynamodb.PutItemOutput{}, nil
+	})
+	suite.NoError(repository.Save(&context, map[string]string{"key1": "foo", "payload": payload}))
+	suite.IsType(&types.AttributeValueMemberB{}, storedItem["payload"])
+	suite.dynamodbClient.EXPECT().UpdateItem(&context, gomock.Any()).DoAndReturn(func(_ *common_models.LambdaContext, input *dynamodb.UpdateItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+		suite.Equal(types.ReturnValueAllOld, input.ReturnValues)
+		return &dynamodb.UpdateItemOutput{Attributes: storedItem}, nil
+	})
+
+	oldItem, appErr := repository.DeleteBySimplePrimaryKey(&context, primaryKey, common_models.DynamodbDeleteOptions{ReturnOldValues: true})
+
+	suite.NoError(appErr)
+	suite.Equal(expectedItem, oldItem)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestDeleteBySimplePrimaryKey_ShouldNotReturnExpiredOldValuesWhenSoftDelete() {
+	context := common_models.NewLambdaContext()
+	repository := common_repositories.NewDynamodbBaseRepository(suite.dynamodbClient, "someTable",
+		common_repositories.WithSoftDelete(common_models.DynamodbSoftDeleteConfig{
+			ExpirationAttribute: "purgeAt",
+		}),
+		common_repositories.WithTimeToLive(common_models.DynamodbTimeToLiveConfig{
+			Attribute:          "expiresAt",
+			IsFilteringExpired: true,
+			Clock: func() time.Time {
+				return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+			},
+		}))
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "key1",
+		Value:   "foo",
+	}
+	updateItemOutput := &dynamodb.UpdateItemOutput{
+		Attributes: map[string]types.AttributeValue{
+			"key1":      &types.AttributeValueMemberS{Value: "foo"},
+			"expiresAt": &types.AttributeValueMemberN{Value: "1704164645"},
+		},
+	}
+
+	suite.dynamodbClient.EXPECT().UpdateItem(&context, gomock.Any()).Return(updateItemOutput, nil)
+
+	oldItem, appErr := repository.DeleteBySimplePrimaryKey(&context, primaryKey, common_models.DynamodbDeleteOptions{ReturnOldValues: true})
+
+	suite.NoError(appErr)
+	suite.Nil(oldItem)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestFindBySimplePrimaryKey_ShouldHideSoftDeletedItem() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "pk",
+		Value:   "someKey",
+	}
+	deletedItem := map[string]types.AttributeValue{
+		"pk":        &types.AttributeValueMemberS{Value: "someKey"},
+		"deletedAt": &types.AttributeValueMemberS{Value: "2024-01-02T03:04:05Z"},
+	}
+
+	suite.dynamodbClient.EXPECT().GetItem(&context, gomock.Any()).Return(&dynamodb.GetItemOutput{Item: deletedItem}, nil).Times(2)
+
+	item, appErr := suite.softDeleteRepository.FindBySimplePrimaryKey(&context, primaryKey, false)
+	includedItem, includedAppErr := suite.softDeleteRepository.IncludingDeleted().FindBySimplePrimaryKey(&context, primaryKey, false)
+
+	suite.NoError(appErr)
+	suite.Empty(item)
+	suite.NoError(includedAppErr)
+	suite.Equal(deletedItem, includedItem)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestFindBySimplePrimaryKeyInReadTransaction_ShouldHideSoftDeletedItem() {
+	context := common_models.NewLambdaContext()
+	transactionManager := common_repositories.NewDynamodbTransactionManager(suite.dynamodbClient)
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "pk",
+		Value:   "someKey",
+	}
+	deletedItem := map[string]types.AttributeValue{
+		"pk":        &types.AttributeValueMemberS{Value: "someKey"},
+		"deletedAt": &types.AttributeValueMemberS{Value: "2024-01-02T03:04:05Z"},
+	}
+
+	suite.dynamodbClient.EXPECT().TransactGetItems(&context, gomock.Any()).Return(&dynamodb.TransactGetItemsOutput{
+		Responses: []types.ItemResponse{{Item: deletedItem}},
+	}, nil)
+
+	startErr := transactionManager.StartReadTransaction(&context)
+	handle, findErr := suite.softDeleteRepository.FindBySimplePrimaryKeyInReadTransaction(&context, primaryKey)
+	_, executeErr := transactionManager.ExecuteReadTransactionItems(&context)
+	item, itemErr := handle.Item()
+
+	suite.NoError(startErr)
+	suite.NoError(findErr)
+	suite.NoError(executeErr)
+	suite.NoError(itemErr)
+	suite.Empty(item)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestFindBySimplePrimaryKey_ShouldReturnItemWithNullDeletedAt() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "pk",
+		Value:   "someKey",
+	}
+	liveItem := map[string]types.AttributeValue{
+		"pk":        &types.AttributeValueMemberS{Value: "someKey"},
+		"deletedAt": &types.AttributeValueMemberNULL{Value: true},
+	}
+
+	suite.dynamodbClient.EXPECT().GetItem(&context, gomock.Any()).Return(&dynamodb.GetItemOutput{Item: liveItem}, nil)
+
+	item, appErr := suite.softDeleteRepository.FindBySimplePrimaryKey(&context, primaryKey, false)
+
+	suite.NoError(appErr)
+	suite.Equal(liveItem, item)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestQuery_ShouldFilterSoftDeletedItems() {
+	context := common_models.NewLambdaContext()
+	query := common_models.DynamodbQuery{
+		PartitionKey: common_models.DynamodbSimplePrimaryKey{KeyName: "pk", Value: "someKey"},
+	}
+	queryInput := dynamodb.QueryInput{
+		TableName:              aws.String("someTable"),
+		KeyConditionExpression: aws.String("#1 = :1"),
+		FilterExpression:       aws.String("(attribute_not_exists (#0)) OR (attribute_type (#0, :0))"),
+		ExpressionAttributeNames: map[string]string{
+			"#0": "deletedAt",
+			"#1": "pk",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":0": &types.AttributeValueMemberS{Value: "NULL"},
+			":1": &types.AttributeValueMemberS{Value: "someKey"},
+		},
+		ScanIndexForward: aws.Bool(true),
+		ConsistentRead:   aws.Bool(false),
+	}
+
+	suite.dynamodbClient.EXPECT().Query(&context, &queryInput).Return(&dynamodb.QueryOutput{}, nil)
+
+	_, appErr := suite.softDeleteRepository.Query(&context, query)
+
+	suite.NoError(appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestBatchDeleteBySimplePrimaryKeys_ShouldReturnInternalServerErrorWhenSoftDelete() {
+	context := common_models.NewLambdaContext()
+	expectedAppErr := common_errors.NewInternalServerError("batch deletes are not supported with soft delete")
+
+	appErr := suite.softDeleteRepository.BatchDeleteBySimplePrimaryKeys(&context, []common_models.DynamodbSimplePrimaryKey{{KeyName: "pk", Value: "someKey"}})
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestSave_ShouldStoreExpirationWhenEntityHasExpirationTime() {
+	context := common_models.NewLambdaContext()
+	entity := common_models.DynamodbEntity{
+		Item:           DummyItem{Key1: "foo", Key2: "bar"},
+		ExpirationTime: time.Hour,
+	}
+	putItemInput := dynamodb.PutItemInput{
+		TableName: aws.String("someTable"),
+		Item: map[string]types.AttributeValue{
+			"key1":      &types.AttributeValueMemberS{Value: "foo"},
+			"key2":      &types.AttributeValueMemberS{Value: "bar"},
+			"expiresAt": &types.AttributeValueMemberN{Value: "1700003600"},
+		},
+	}
+
+	suite.dynamodbClient.EXPECT().PutItem(&context, &putItemInput).Return(&dynamodb.PutItemOutput{}, nil)
+
+	appErr := suite.ttlRepository.Save(&context, entity)
+
+	suite.NoError(appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestSave_ShouldReturnInternalServerErrorWhenExpirationTimeWithoutTimeToLive() {
+	context := common_models.NewLambdaContext()
+	entity := common_models.DynamodbEntity{
+		Item:           DummyItem{Key1: "foo", Key2: "bar"},
+		ExpirationTime: time.Hour,
+	}
+	expectedAppErr := common_errors.NewInternalServerError("time to live is not enabled for this repository")
+
+	appErr := suite.baseRepository.Save(&context, entity)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestFindBySimplePrimaryKey_ShouldHideExpiredItem() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "pk",
+		Value:   "someKey",
+	}
+	expiredItem := map[string]types.AttributeValue{
+		"pk":        &types.AttributeValueMemberS{Value: "someKey"},
+		"expiresAt": &types.AttributeValueMemberN{Value: "1699999999"},
+	}
+
+	suite.dynamodbClient.EXPECT().GetItem(&context, gomock.Any()).Return(&dynamodb.GetItemOutput{Item: expiredItem}, nil)
+
+	item, appErr := suite.ttlRepository.FindBySimplePrimaryKey(&context, primaryKey, false)
+
+	suite.NoError(appErr)
+	suite.Empty(item)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestQuery_ShouldFilterExpiredItems() {
+	context := common_models.NewLambdaContext()
+	query := common_models.DynamodbQuery{
+		PartitionKey: common_models.DynamodbSimplePrimaryKey{KeyName: "pk", Value: "someKey"},
+	}
+	queryInput := dynamodb.QueryInput{
+		TableName:              aws.String("someTable"),
+		KeyConditionExpression: aws.String("#1 = :1"),
+		FilterExpression:       aws.String("(attribute_not_exists (#0)) OR (#0 > :0)"),
+		ExpressionAttributeNames: map[string]string{
+			"#0": "expiresAt",
+			"#1": "pk",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":0": &types.AttributeValueMemberN{Value: "1700000000"},
+			":1": &types.AttributeValueMemberS{Value: "someKey"},
+		},
+		ScanIndexForward: aws.Bool(true),
+		ConsistentRead:   aws.Bool(false),
+	}
+
+	suite.dynamodbClient.EXPECT().Query(&context, &queryInput).Return(&dynamodb.QueryOutput{}, nil)
+
+	_, appErr := suite.ttlRepository.Query(&context, query)
+
+	suite.NoError(appErr)
+}
+
+var offloadBlobExpiresAt = time.Date(2024, 1, 4, 3, 4, 5, 0, time.UTC)
+
+func offloadBlobKeyPrefix() string {
+	keyChecksum := sha256.Sum256([]byte(`{"key1":{"S":"foo"}}`))
+	return "blobs/someTable/" + hex.EncodeToString(keyChecksum[:]) + "/payload/"
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) expectPutBlob(context *common_models.LambdaContext, blobData []byte, blobKey *string) {
+	suite.blobStore.EXPECT().PutBlob(context, gomock.Any(), blobData).DoAndReturn(func(_ *common_models.LambdaContext, key string, _ []byte) error {
+		suite.True(strings.HasPrefix(key, offloadBlobKeyPrefix()))
+		*blobKey = key
+		return nil
+	})
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestSave_ShouldOffloadLargeAttributeToBlobStore() {
+	type largeItem struct {
+		Key1    string `dynamodbav:"key1"`
+		Payload string `dynamodbav:"payload"`
+	}
+	context := common_models.NewLambdaContext()
+	blobData := []byte(`{"payload":{"S":"abcdefghij"}}`)
+	var blobKey string
+
+	suite.expectPutBlob(&context, blobData, &blobKey)
+	suite.dynamodbClient.EXPECT().PutItem(&context, gomock.Any()).DoAndReturn(func(_ *common_models.LambdaContext, input *dynamodb.PutItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+		suite.Equal(map[string]types.AttributeValue{
+			"key1":                  &types.AttributeValueMemberS{Value: "foo"},
+			"__blobPointer#payload": &types.AttributeValueMemberS{Value: blobKey},
+		}, input.Item)
+		return &dynamodb.PutItemOutput{}, nil
+	})
+
+	appErr := suite.offloadRepository.Save(&context, largeItem{Key1: "foo", Payload: "abcdefghij"})
+
+	suite.NoError(appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestSave_ShouldExpireReplacedBlob() {
+	context := common_models.NewLambdaContext()
+	blobData := []byte(`{"payload":{"S":"abcdefghij"}}`)
+	var blobKey string
+	putItemOutput := &dynamodb.PutItemOutput{
+		Attributes: map[string]types.AttributeValue{
+			"key1":                  &types.AttributeValueMemberS{Value: "foo"},
+			"__blobPointer#payload": &types.AttributeValueMemberS{Value: "blobs/someTable/oldBlob"},
+		},
+	}
+
+	suite.expectPutBlob(&context, blobData, &blobKey)
+	suite.dynamodbClient.EXPECT().PutItem(&context, gomock.Any()).Return(putItemOutput, nil)
+	suite.blobStore.EXPECT().ExpireBlob(&context, "blobs/someTable/oldBlob", offloadBlobExpiresAt).Return(nil)
+
+	appErr := suite.offloadRepository.Save(&context, map[string]string{"key1": "foo", "payload": "abcdefghij"})
+
+	suite.NoError(appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestSave_ShouldStoreEachWriteUnderDifferentBlobKey() {
+	context := common_models.NewLambdaContext()
+	blobData := []byte(`{"payload":{"S":"abcdefghij"}}`)
+	var firstBlobKey, secondBlobKey string
+
+	suite.expectPutBlob(&context, blobData, &firstBlobKey)
+	suite.expectPutBlob(&context, blobData, &secondBlobKey)
+	suite.dynamodbClient.EXPECT().PutItem(&context, gomock.Any()).Return(&dynamodb.PutItemOutput{}, nil).Times(2)
+
+	suite.NoError(suite.offloadRepository.Save(&context, map[string]string{"key1": "foo", "payload": "abcdefghij"}))
+	suite.NoError(suite.offloadRepository.Save(&context, map[string]string{"key1": "foo", "payload": "abcdefghij"}))
+	suite.NotEqual(firstBlobKey, secondBlobKey)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestSave_ShouldKeepSmallAttributeInline() {
+	context := common_models.NewLambdaContext()
+	putItemInput := dynamodb.PutItemInput{
+		TableName: aws.String("someTable"),
+		Item: map[string]types.AttributeValue{
+			"key1":    &types.AttributeValueMemberS{Value: "foo"},
+			"payload": &types.AttributeValueMemberS{Value: "abc"},
+		},
+		ReturnValues: types.ReturnValueAllOld,
+	}
+
+	suite.dynamodbClient.EXPECT().PutItem(&context, &putItemInput).Return(&dynamodb.PutItemOutput{}, nil)
+
+	appErr := suite.offloadRepository.Save(&context, map[string]string{"key1": "foo", "payload": "abc"})
+
+	suite.NoError(appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestSave_ShouldReturnInternalServerErrorWhenBlobStoreFails() {
+	context := common_models.NewLambdaContext()
+	expectedAppErr := common_errors.NewInternalServerError("error while storing large attribute")
+
+	suite.blobStore.EXPECT().PutBlob(&context, gomock.Any(), gomock.Any()).Return(errors.New("some error"))
+
+	appErr := suite.offloadRepository.Save(&context, map[string]string{"key1": "foo", "payload": "abcdefghij"})
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestFindBySimplePrimaryKey_ShouldRehydrateOffloadedAttribute() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "key1",
+		Value:   "foo",
+	}
+	getItemOutput := &dynamodb.GetItemOutput{
+		Item: map[string]types.AttributeValue{
+			"key1":                  &types.AttributeValueMemberS{Value: "foo"},
+			"__blobPointer#payload": &types.AttributeValueMemberS{Value: "blobs/someTable/someHash"},
+		},
+	}
+	expectedItem := map[string]types.AttributeValue{
+		"key1":    &types.AttributeValueMemberS{Value: "foo"},
+		"payload": &types.AttributeValueMemberS{Value: "abcdefghij"},
+	}
+
+	suite.dynamodbClient.EXPECT().GetItem(&context, gomock.Any()).Return(getItemOutput, nil)
+	suite.blobStore.EXPECT().GetBlob(&context, "blobs/someTable/someHash").Return([]byte(`{"payload":{"S":"abcdefghij"}}`), nil)
+
+	item, appErr := suite.offloadRepository.FindBySimplePrimaryKey(&context, primaryKey, false)
+
+	suite.NoError(appErr)
+	suite.Equal(expectedItem, item)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestFindBySimplePrimaryKey_ShouldNotMistakeUserMapForBlobPointer() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "key1",
+		Value:   "foo",
+	}
+	getItemOutput := &dynamodb.GetItemOutput{
+		Item: map[string]types.AttributeValue{
+			"key1": &types.AttributeValueMemberS{Value: "foo"},
+			"payload": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+				"__blobPointer": &types.AttributeValueMemberS{Value: "userValue"},
+			}},
+		},
+	}
+	expectedItem := map[string]types.AttributeValue{
+		"key1": &types.AttributeValueMemberS{Value: "foo"},
+		"payload": &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			"__blobPointer": &types.AttributeValueMemberS{Value: "userValue"},
+		}},
+	}
+
+	suite.dynamodbClient.EXPECT().GetItem(&context, gomock.Any()).Return(getItemOutput, nil)
+
+	item, appErr := suite.offloadRepository.FindBySimplePrimaryKey(&context, primaryKey, false)
+
+	suite.NoError(appErr)
+	suite.Equal(expectedItem, item)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestDeleteBySimplePrimaryKey_ShouldExpireOffloadedBlobs() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "key1",
+		Value:   "foo",
+	}
+	deleteItemInput := dynamodb.DeleteItemInput{
+		TableName: aws.String("someTable"),
+		Key: map[string]types.AttributeValue{
+			"key1": &types.AttributeValueMemberS{Value: "foo"},
+		},
+		ReturnValues: types.ReturnValueAllOld,
+	}
+	deleteItemOutput := &dynamodb.DeleteItemOutput{
+		Attributes: map[string]types.AttributeValue{
+			"key1":                  &types.AttributeValueMemberS{Value: "foo"},
+			"__blobPointer#payload": &types.AttributeValueMemberS{Value: "blobs/someTable/someHash"},
+		},
+	}
+
+	suite.dynamodbClient.EXPECT().DeleteItem(&context, &deleteItemInput).Return(deleteItemOutput, nil)
+	suite.blobStore.EXPECT().ExpireBlob(&context, "blobs/someTable/someHash", offloadBlobExpiresAt).Return(errors.New("some error"))
+
+	oldItem, appErr := suite.offloadRepository.DeleteBySimplePrimaryKey(&context, primaryKey, common_models.DynamodbDeleteOptions{})
+
+	suite.NoError(appErr)
+	suite.Nil(oldItem)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestUpdateBySimplePrimaryKey_ShouldOffloadLargeSetValue() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "key1",
+		Value:   "foo",
+	}
+	update := common_models.DynamodbUpdate{
+		Operations: []common_models.DynamodbUpdateOperation{
+			{Action: common_models.DynamodbUpdateSet, AttributeName: "payload", Value: "abcdefghij"},
+		},
+	}
+	blobData := []byte(`{"payload":{"S":"abcdefghij"}}`)
+	var blobKey string
+	getItemOutput := &dynamodb.GetItemOutput{
+		Item: map[string]types.AttributeValue{
+			"__blobPointer#payload": &types.AttributeValueMemberS{Value: "blobs/someTable/oldBlob"},
+		},
+	}
+	expectedItem := map[string]types.AttributeValue{
+		"key1":    &types.AttributeValueMemberS{Value: "foo"},
+		"payload": &types.AttributeValueMemberS{Value: "abcdefghij"},
+	}
+
+	suite.expectPutBlob(&context, blobData, &blobKey)
+	suite.dynamodbClient.EXPECT().GetItem(&context, gomock.Any()).DoAndReturn(func(_ *common_models.LambdaContext, input *dynamodb.GetItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+		suite.True(*input.ConsistentRead)
+		suite.Equal(map[string]string{"#0": "__blobPointer#payload"}, input.ExpressionAttributeNames)
+		return getItemOutput, nil
+	})
+	suite.dynamodbClient.EXPECT().UpdateItem(&context, gomock.Any()).DoAndReturn(func(_ *common_models.LambdaContext, input *dynamodb.UpdateItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+		suite.Equal("REMOVE #0\nSET #1 = :0\n", *input.UpdateExpression)
+		suite.Equal(map[string]string{"#0": "payload", "#1": "__blobPointer#payload"}, input.ExpressionAttributeNames)
+		suite.Equal(&types.AttributeValueMemberS{Value: blobKey}, input.ExpressionAttributeValues[":0"])
+		return &dynamodb.UpdateItemOutput{
+			Attributes: map[string]types.AttributeValue{
+				"key1":                  &types.AttributeValueMemberS{Value: "foo"},
+				"__blobPointer#payload": &types.AttributeValueMemberS{Value: blobKey},
+			},
+		}, nil
+	})
+	suite.blobStore.EXPECT().ExpireBlob(&context, "blobs/someTable/oldBlob", offloadBlobExpiresAt).Return(nil)
+	suite.blobStore.EXPECT().GetBlob(&context, gomock.Any()).DoAndReturn(func(_ *common_models.LambdaContext, key string) ([]byte, error) {
+		suite.Equal(blobKey, key)
+		return blobData, nil
+	})
+
+	item, appErr := suite.offloadRepository.UpdateBySimplePrimaryKey(&context, primaryKey, update)
+
+	suite.NoError(appErr)
+	suite.Equal(expectedItem, item)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestUpdateBySimplePrimaryKey_ShouldReturnInternalServerErrorWhenAddingToOffloadedAttribute() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "key1",
+		Value:   "foo",
+	}
+	update := common_models.DynamodbUpdate{
+		Operations: []common_models.DynamodbUpdateOperation{
+			{Action: common_models.DynamodbUpdateAdd, AttributeName: "payload", Value: 1},
+		},
+	}
+	expectedAppErr := common_errors.NewInternalServerError("offloaded attribute payload only supports set and remove updates")
+
+	_, appErr := suite.offloadRepository.UpdateBySimplePrimaryKey(&context, primaryKey, update)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestPrepareStreamImage_ShouldSkipExpiredBlobInOldImage() {
+	context := common_models.NewLambdaContext()
+	image := map[string]types.AttributeValue{
+		"key1":                  &types.AttributeValueMemberS{Value: "foo"},
+		"__blobPointer#payload": &types.AttributeValueMemberS{Value: "blobs/someTable/oldBlob"},
+	}
+	expectedImage := map[string]types.AttributeValue{
+		"key1": &types.AttributeValueMemberS{Value: "foo"},
+	}
+
+	suite.blobStore.EXPECT().GetBlob(&context, "blobs/someTable/oldBlob").Return(nil, fmt.Errorf("some error: %w", common_models.ErrBlobNotFound))
+
+	appErr := suite.offloadRepository.PrepareStreamImage(&context, common_models.DynamodbStreamOldImage, image)
+
+	suite.NoError(appErr)
+	suite.Equal(expectedImage, image)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestPrepareStreamImage_ShouldReturnInternalServerErrorWhenNewImageBlobIsMissing() {
+	context := common_models.NewLambdaContext()
+	image := map[string]types.AttributeValue{
+		"key1":                  &types.AttributeValueMemberS{Value: "foo"},
+		"__blobPointer#payload": &types.AttributeValueMemberS{Value: "blobs/someTable/newBlob"},
+	}
+	expectedAppErr := common_errors.NewInternalServerError("error while loading large attribute")
+
+	suite.blobStore.EXPECT().GetBlob(&context, "blobs/someTable/newBlob").Return(nil, fmt.Errorf("some error: %w", common_models.ErrBlobNotFound))
+
+	appErr := suite.offloadRepository.PrepareStreamImage(&context, common_models.DynamodbStreamNewImage, image)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) saveEncryptedItem(context *common_models.LambdaContext, item EncryptedDummyItem) map[string]types.AttributeValue {
+	var storedItem map[string]types.AttributeValue
+	suite.dynamodbClient.EXPECT().PutItem(context, gomock.Any()).DoAndReturn(func(_ *common_models.LambdaContext, input *dynamodb.PutItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+		storedItem = input.Item
+		return &dynamodb.PutItemOutput{}, nil
+	})
+	suite.NoError(suite.encryptedRepository.Save(context, item))
+	return storedItem
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestSave_ShouldEncryptConfiguredAttributesAndDecryptOnRead() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "key1",
+		Value:   "foo",
+	}
+	storedItem := suite.saveEncryptedItem(&context, EncryptedDummyItem{Key1: "foo", Email: "someone@example.com"})
+	suite.IsType(&types.AttributeValueMemberB{}, storedItem["email"])
+	suite.Contains(storedItem, "__encryptedDataKey")
+	var actualItem EncryptedDummyItem
+
+	suite.dynamodbClient.EXPECT().GetItem(&context, gomock.Any()).Return(&dynamodb.GetItemOutput{Item: storedItem}, nil)
+
+	found, appErr := suite.encryptedRepository.FindBySimplePrimaryKeyInto(&context, primaryKey, false, &actualItem)
+
+	suite.NoError(appErr)
+	suite.True(found)
+	suite.Equal(EncryptedDummyItem{Key1: "foo", Email: "someone@example.com"}, actualItem)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestFindBySimplePrimaryKey_ShouldRejectCiphertextSwappedBetweenRows() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "key1",
+		Value:   "bar",
+	}
+	firstItem := suite.saveEncryptedItem(&context, EncryptedDummyItem{Key1: "foo", Email: "first@example.com"})
+	secondItem := suite.saveEncryptedItem(&context, EncryptedDummyItem{Key1: "bar", Email: "second@example.com"})
+	secondItem["email"] = firstItem["email"]
+	secondItem["__encryptedDataKey"] = firstItem["__encryptedDataKey"]
+	expectedAppErr := common_errors.NewInternalServerError("error while decrypting attribute email")
+
+	suite.dynamodbClient.EXPECT().GetItem(&context, gomock.Any()).Return(&dynamodb.GetItemOutput{Item: secondItem}, nil)
+
+	_, appErr := suite.encryptedRepository.FindBySimplePrimaryKey(&context, primaryKey, false)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestQuery_ShouldProjectDataKeyAndKeyAttributesWhenProjectingEncryptedAttribute() {
+	context := common_models.NewLambdaContext()
+	query := common_models.DynamodbQuery{
+		PartitionKey:        common_models.DynamodbSimplePrimaryKey{KeyName: "key1", Value: "foo"},
+		ProjectedAttributes: []string{"email"},
+	}
+	storedItem := suite.saveEncryptedItem(&context, EncryptedDummyItem{Key1: "foo", Email: "someone@example.com"})
+	var queryInput *dynamodb.QueryInput
+
+	suite.dynamodbClient.EXPECT().Query(&context, gomock.Any()).DoAndReturn(func(_ *common_models.LambdaContext, input *dynamodb.QueryInput, _ ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+		queryInput = input
+		return &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{storedItem}}, nil
+	})
+
+	result, appErr := suite.encryptedRepository.Query(&context, query)
+
+	suite.NoError(appErr)
+	suite.Equal("#1, #2, #0", *queryInput.ProjectionExpression)
+	suite.Equal(map[string]string{"#0": "key1", "#1": "email", "#2": "__encryptedDataKey"}, queryInput.ExpressionAttributeNames)
+	suite.Equal(&types.AttributeValueMemberS{Value: "someone@example.com"}, result.Items[0]["email"])
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestUpdateBySimplePrimaryKey_ShouldReturnInternalServerErrorWhenUpdatingEncryptedAttribute() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "key1",
+		Value:   "foo",
+	}
+	update := common_models.DynamodbUpdate{
+		Operations: []common_models.DynamodbUpdateOperation{
+			{Action: common_models.DynamodbUpdateSet, AttributeName: "email", Value: "plain@example.com"},
+		},
+	}
+	expectedAppErr := common_errors.NewInternalServerError("encrypted attribute email cannot be updated in place")
+
+	_, appErr := suite.encryptedRepository.UpdateBySimplePrimaryKey(&context, primaryKey, update)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestSave_ShouldCompressConfiguredAttributesAndDecompressOnRead() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "key1",
+		Value:   "foo",
+	}
+	item := map[string]string{"key1": "foo", "payload": strings.Repeat("someValue", 20)}
+	var storedItem map[string]types.AttributeValue
+
+	suite.dynamodbClient.EXPECT().PutItem(&context, gomock.Any()).DoAndReturn(func(_ *common_models.LambdaContext, input *dynamodb.PutItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+		storedItem = input.Item
+		return &dynamodb.PutItemOutput{}, nil
+	})
+
+	appErr := suite.compressedRepository.Save(&context, item)
+	suite.NoError(appErr)
+	compressedValue, isBinary := storedItem["payload"].(*types.AttributeValueMemberB)
+	suite.True(isBinary)
+	suite.Equal(common_helpers.GzipCompressionHeader, compressedValue.Value[0])
+
+	suite.dynamodbClient.EXPECT().GetItem(&context, gomock.Any()).Return(&dynamodb.GetItemOutput{Item: storedItem}, nil)
+
+	actualItem, appErr := suite.compressedRepository.FindBySimplePrimaryKey(&context, primaryKey, false)
+
+	suite.NoError(appErr)
+	suite.Equal(&types.AttributeValueMemberS{Value: item["payload"]}, actualItem["payload"])
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestFindBySimplePrimaryKeyInReadTransaction_ShouldDecompressConfiguredAttributes() {
+	context := common_models.NewLambdaContext()
+	transactionManager := common_repositories.NewDynamodbTransactionManager(suite.dynamodbClient)
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "key1",
+		Value:   "foo",
+	}
+	item := map[string]string{"key1": "foo", "payload": strings.Repeat("someValue", 20)}
+	var storedItem map[string]types.AttributeValue
+
+	suite.dynamodbClient.EXPECT().PutItem(&context, gomock.Any()).DoAndReturn(func(_ *common_models.LambdaContext, input *dynamodb.PutItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+		storedItem = input.Item
+		return &dynamodb.PutItemOutput{}, nil
+	})
+	suite.NoError(suite.compressedRepository.Save(&context, item))
+	suite.dynamodbClient.EXPECT().TransactGetItems(&context, gomock.Any()).Return(&dynamodb.TransactGetItemsOutput{
+		Responses: []types.ItemResponse{{Item: storedItem}},
+	}, nil)
+
+	startErr := transactionManager.StartReadTransaction(&context)
+	handle, findErr := suite.compressedRepository.FindBySimplePrimaryKeyInReadTransaction(&context, primaryKey)
+	responses, executeErr := transactionManager.ExecuteReadTransactionItems(&context)
+	actualItem, itemErr := handle.Item()
+
+	suite.NoError(startErr)
+	suite.NoError(findErr)
+	suite.NoError(executeErr)
+	suite.NoError(itemErr)
+	suite.Equal(&types.AttributeValueMemberS{Value: item["payload"]}, actualItem["payload"])
+	suite.Equal(&types.AttributeValueMemberS{Value: item["payload"]}, responses[0]["payload"])
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestFindBySimplePrimaryKey_ShouldReturnLegacyBinaryAttributeStartingWithCompressionHeader() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "key1",
+		Value:   "foo",
+	}
+	legacyItem := map[string]types.AttributeValue{
+		"key1":    &types.AttributeValueMemberS{Value: "foo"},
+		"payload": &types.AttributeValueMemberB{Value: []byte{common_helpers.GzipCompressionHeader, 0x00, 0x02}},
+	}
+
+	suite.dynamodbClient.EXPECT().GetItem(&context, gomock.Any()).Return(&dynamodb.GetItemOutput{Item: legacyItem}, nil)
+
+	actualItem, appErr := suite.compressedRepository.FindBySimplePrimaryKey(&context, primaryKey, false)
+
+	suite.NoError(appErr)
+	suite.Equal(legacyItem, actualItem)
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestFindBySimplePrimaryKey_ShouldDecompressAttributeWrittenWithZstd() {
+	context := common_models.NewLambdaContext()
+	zstdRepository := common_repositories.NewDynamodbBaseRepository(suite.dynamodbClient, "someTable", common_repositories.WithCompression(common_models.DynamodbCompressionConfig{
+		Codec:      common_helpers.NewZstdCodec(common_helpers.ZstdDefaultCompression),
+		Attributes: []string{"payload"},
+		MinSize:    10,
+	}))
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "key1",
+		Value:   "foo",
+	}
+	item := map[string]string{"key1": "foo", "payload": strings.Repeat("someValue", 20)}
+	var storedItem map[string]types.AttributeValue
+
+	suite.dynamodbClient.EXPECT().PutItem(&context, gomock.Any()).DoAndReturn(func(_ *common_models.LambdaContext, input *dynamodb.PutItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+		storedItem = input.Item
+		return &dynamodb.PutItemOutput{}, nil
+	})
+	suite.NoError(zstdRepository.Save(&context, item))
+	suite.Equal(common_helpers.ZstdCompressionHeader, storedItem["payload"].(*types.AttributeValueMemberB).Value[0])
+	suite.dynamodbClient.EXPECT().GetItem(&context, gomock.Any()).Return(&dynamodb.GetItemOutput{Item: storedItem}, nil)
+
+	actualItem, appErr := suite.compressedRepository.FindBySimplePrimaryKey(&context, primaryKey, false)
+
+	suite.NoError(appErr)
+	suite.Equal(&types.AttributeValueMemberS{Value: item["payload"]}, actualItem["payload"])
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestPrepareStreamImage_ShouldDecompressConfiguredAttributes() {
+	context := common_models.NewLambdaContext()
+	item := map[string]string{"key1": "foo", "payload": strings.Repeat("someValue", 20)}
+	var storedItem map[string]types.AttributeValue
+
+	suite.dynamodbClient.EXPECT().PutItem(&context, gomock.Any()).DoAndReturn(func(_ *common_models.LambdaContext, input *dynamodb.PutItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+		storedItem = input.Item
+		return &dynamodb.PutItemOutput{}, nil
+	})
+	suite.NoError(suite.compressedRepository.Save(&context, item))
+
+	appErr := suite.compressedRepository.PrepareStreamImage(&context, common_models.DynamodbStreamNewImage, storedItem)
+
+	suite.NoError(appErr)
+	suite.Equal(&types.AttributeValueMemberS{Value: item["payload"]}, storedItem["payload"])
+}
+
+func (suite *DynamodbBaseRepositoryTestSuite) TestFindBySimplePrimaryKey_ShouldReturnLegacyUncompressedAttribute() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "key1",
+		Value:   "foo",
+	}
+	legacyItem := map[string]types.AttributeValue{
+		"key1":    &types.AttributeValueMemberS{Value: "foo"},
+		"payload": &types.AttributeValueMemberS{Value: "someLegacyValue"},
+	}
+
+	suite.dynamodbClient.EXPECT().GetItem(&context, gomock.Any()).Return(&dynamodb.GetItemOutput{Item: legacyItem}, nil)
+
+	actualItem, appErr := suite.compressedRepository.FindBySimplePrimaryKey(&context, primaryKey, false)
+
+	suite.NoError(appErr)
+	suite.Equal(legacyItem, actualItem)
+}
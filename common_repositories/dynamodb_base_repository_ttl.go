@@ -0,0 +1,77 @@
+package common_repositories
+
+import (
+	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/Drathveloper/lambda_commons/v2/common_models"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"strconv"
+	"time"
+)
+
+const defaultTimeToLiveAttribute = "ttl"
+
+func newTimeToLiveConfig(config common_models.DynamodbTimeToLiveConfig) *common_models.DynamodbTimeToLiveConfig {
+	if config.Attribute == "" {
+		config.Attribute = defaultTimeToLiveAttribute
+	}
+	if config.Clock == nil {
+		config.Clock = time.Now
+	}
+	return &config
+}
+
+func (repository *dynamodbBaseRepository) marshalItem(item interface{}) (map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+	var expirationTime time.Duration
+	switch entity := item.(type) {
+	case common_models.DynamodbEntity:
+		item, expirationTime = entity.Item, entity.ExpirationTime
+	case *common_models.DynamodbEntity:
+		item, expirationTime = entity.Item, entity.ExpirationTime
+	}
+	itemAttributeValue, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return nil, common_errors.NewInternalServerError("error while marshaling item")
+	}
+	if expirationTime <= 0 {
+		return itemAttributeValue, nil
+	}
+	if repository.timeToLiveConfig == nil {
+		return nil, common_errors.NewInternalServerError("time to live is not enabled for this repository")
+	}
+	expiresAt := repository.timeToLiveConfig.Clock().Add(expirationTime).Unix()
+	itemAttributeValue[repository.timeToLiveConfig.Attribute] = &types.AttributeValueMemberN{Value: strconv.FormatInt(expiresAt, 10)}
+	return itemAttributeValue, nil
+}
+
+func (repository *dynamodbBaseRepository) isFilteringExpired() bool {
+	return repository.timeToLiveConfig != nil && repository.timeToLiveConfig.IsFilteringExpired
+}
+
+func (repository *dynamodbBaseRepository) isExpired(item map[string]types.AttributeValue) bool {
+	if !repository.isFilteringExpired() {
+		return false
+	}
+	value, isNumber := item[repository.timeToLiveConfig.Attribute].(*types.AttributeValueMemberN)
+	if !isNumber {
+		return false
+	}
+	expiresAt, err := strconv.ParseInt(value.Value, 10, 64)
+	if err != nil || expiresAt <= 0 {
+		return false
+	}
+	return expiresAt <= repository.timeToLiveConfig.Clock().Unix()
+}
+
+func (repository *dynamodbBaseRepository) applyExpirationToFilter(filter *expression.ConditionBuilder) *expression.ConditionBuilder {
+	if !repository.isFilteringExpired() {
+		return filter
+	}
+	name := expression.Name(repository.timeToLiveConfig.Attribute)
+	notExpired := expression.AttributeNotExists(name).Or(name.GreaterThan(expression.Value(repository.timeToLiveConfig.Clock().Unix())))
+	if filter != nil {
+		notExpired = notExpired.And(*filter)
+	}
+	return &notExpired
+}
@@ -0,0 +1,194 @@
+package common_repositories
+
+import (
+	"github.com/Drathveloper/lambda_commons/v2/common_constants"
+	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/Drathveloper/lambda_commons/v2/common_models"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"strconv"
+	"sync"
+)
+
+const (
+	defaultCounterValueAttribute = "counterValue"
+	defaultCounterBlockSize      = 1
+)
+
+type DynamodbCounterRepository[K common_models.DynamodbPrimaryKey] interface {
+	Increment(ctx *common_models.LambdaContext, counterKey K, delta int64) (int64, common_errors.GenericApplicationError)
+	ReserveBlock(ctx *common_models.LambdaContext, counterKey K, size int64) (common_models.DynamodbCounterBlock, common_errors.GenericApplicationError)
+	NextValue(ctx *common_models.LambdaContext, counterKey K) (int64, common_errors.GenericApplicationError)
+}
+
+type dynamodbCounterRepository[K common_models.DynamodbPrimaryKey] struct {
+	baseRepository DynamodbBaseRepository
+	valueAttribute string
+	blockSize      int64
+	blocks         map[string]*common_models.DynamodbCounterBlock
+	reservations   map[string]chan struct{}
+	mutex          sync.Mutex
+}
+
+func NewDynamodbCounterRepository[K common_models.DynamodbPrimaryKey](baseRepository DynamodbBaseRepository, config common_models.DynamodbCounterConfig) DynamodbCounterRepository[K] {
+	repository := &dynamodbCounterRepository[K]{
+		baseRepository: baseRepository,
+		valueAttribute: config.ValueAttribute,
+		blockSize:      config.BlockSize,
+		blocks:         make(map[string]*common_models.DynamodbCounterBlock, 0),
+		reservations:   make(map[string]chan struct{}, 0),
+	}
+	if repository.valueAttribute == "" {
+		repository.valueAttribute = defaultCounterValueAttribute
+	}
+	if repository.blockSize <= 0 {
+		repository.blockSize = defaultCounterBlockSize
+	}
+	return repository
+}
+
+func (repository *dynamodbCounterRepository[K]) Increment(ctx *common_models.LambdaContext, counterKey K, delta int64) (int64, common_errors.GenericApplicationError) {
+	if ctx.Exists(common_constants.WriteTransaction) {
+		return repository.incrementInTransaction(ctx, counterKey, delta)
+	}
+	update := common_models.DynamodbUpdate{
+		Operations: []common_models.DynamodbUpdateOperation{
+			{Action: common_models.DynamodbUpdateAdd, AttributeName: repository.valueAttribute, Value: delta},
+		},
+	}
+	attributes, appErr := repository.update(ctx, counterKey, update)
+	if appErr != nil {
+		return 0, appErr
+	}
+	return repository.readValue(attributes)
+}
+
+func (repository *dynamodbCounterRepository[K]) ReserveBlock(ctx *common_models.LambdaContext, counterKey K, size int64) (common_models.DynamodbCounterBlock, common_errors.GenericApplicationError) {
+	if size <= 0 {
+		return common_models.DynamodbCounterBlock{}, common_errors.NewInternalServerError("counter block size must be positive")
+	}
+	reservationCtx := common_models.NewLambdaContextFromContext(ctx)
+	if principal, exists := ctx.Get(common_constants.AuditPrincipal); exists {
+		reservationCtx.Set(common_constants.AuditPrincipal, principal)
+	}
+	last, appErr := repository.Increment(&reservationCtx, counterKey, size)
+	if appErr != nil {
+		return common_models.DynamodbCounterBlock{}, appErr
+	}
+	return common_models.DynamodbCounterBlock{First: last - size + 1, Last: last}, nil
+}
+
+// The mutex is never held while reserving. Only one caller reserves the next block of a counter; the others wait for
+// that reservation and retry, so an exhausted counter never blocks the remaining counters.
+func (repository *dynamodbCounterRepository[K]) NextValue(ctx *common_models.LambdaContext, counterKey K) (int64, common_errors.GenericApplicationError) {
+	canonicalKey, appErr := canonicalizeCounterKey(counterKey)
+	if appErr != nil {
+		return 0, appErr
+	}
+	for {
+		repository.mutex.Lock()
+		if block, exists := repository.blocks[canonicalKey]; exists && block.First <= block.Last {
+			value := block.First
+			block.First++
+			repository.mutex.Unlock()
+			return value, nil
+		}
+		if reservation, isReserving := repository.reservations[canonicalKey]; isReserving {
+			repository.mutex.Unlock()
+			select {
+			case <-reservation:
+				continue
+			case <-ctx.Done():
+				return 0, common_errors.NewInternalServerError("context cancelled while waiting for a counter block")
+			}
+		}
+		reservation := make(chan struct{})
+		repository.reservations[canonicalKey] = reservation
+		repository.mutex.Unlock()
+		reservedBlock, appErr := repository.ReserveBlock(ctx, counterKey, repository.blockSize)
+		repository.mutex.Lock()
+		delete(repository.reservations, canonicalKey)
+		if appErr == nil {
+			repository.blocks[canonicalKey] = &reservedBlock
+		}
+		close(reservation)
+		repository.mutex.Unlock()
+		if appErr != nil {
+			return 0, appErr
+		}
+	}
+}
+
+func (repository *dynamodbCounterRepository[K]) incrementInTransaction(ctx *common_models.LambdaContext, counterKey K, delta int64) (int64, common_errors.GenericApplicationError) {
+	item, appErr := repository.find(ctx, counterKey)
+	if appErr != nil {
+		return 0, appErr
+	}
+	valueName := expression.Name(repository.valueAttribute)
+	condition := expression.AttributeNotExists(valueName)
+	current := int64(0)
+	if _, exists := item[repository.valueAttribute]; exists {
+		current, appErr = repository.readValue(item)
+		if appErr != nil {
+			return 0, appErr
+		}
+		condition = valueName.Equal(expression.Value(current))
+	}
+	update := common_models.DynamodbUpdate{
+		Operations: []common_models.DynamodbUpdateOperation{
+			{Action: common_models.DynamodbUpdateAdd, AttributeName: repository.valueAttribute, Value: delta},
+		},
+		Condition: &condition,
+	}
+	if _, appErr := repository.update(ctx, counterKey, update); appErr != nil {
+		return 0, appErr
+	}
+	return current + delta, nil
+}
+
+func (repository *dynamodbCounterRepository[K]) find(ctx *common_models.LambdaContext, counterKey K) (map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+	switch typedKey := any(counterKey).(type) {
+	case common_models.DynamodbSimplePrimaryKey:
+		return repository.baseRepository.FindBySimplePrimaryKey(ctx, typedKey, true)
+	case common_models.DynamodbComplexPrimaryKey:
+		return repository.baseRepository.FindByComplexPrimaryKey(ctx, typedKey, true)
+	}
+	return nil, nil
+}
+
+func (repository *dynamodbCounterRepository[K]) update(ctx *common_models.LambdaContext, counterKey K, update common_models.DynamodbUpdate) (map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+	switch typedKey := any(counterKey).(type) {
+	case common_models.DynamodbSimplePrimaryKey:
+		return repository.baseRepository.UpdateBySimplePrimaryKey(ctx, typedKey, update)
+	case common_models.DynamodbComplexPrimaryKey:
+		return repository.baseRepository.UpdateByComplexPrimaryKey(ctx, typedKey, update)
+	}
+	return nil, nil
+}
+
+func (repository *dynamodbCounterRepository[K]) readValue(item map[string]types.AttributeValue) (int64, common_errors.GenericApplicationError) {
+	value, isNumber := item[repository.valueAttribute].(*types.AttributeValueMemberN)
+	if !isNumber {
+		return 0, common_errors.NewInternalServerError("counter value attribute must be a number")
+	}
+	counterValue, err := strconv.ParseInt(value.Value, 10, 64)
+	if err != nil {
+		return 0, common_errors.NewInternalServerError("counter value attribute must be an integer")
+	}
+	return counterValue, nil
+}
+
+func canonicalizeCounterKey[K common_models.DynamodbPrimaryKey](counterKey K) (string, common_errors.GenericApplicationError) {
+	var keyValues map[string]types.AttributeValue
+	var appErr common_errors.GenericApplicationError
+	switch typedKey := any(counterKey).(type) {
+	case common_models.DynamodbSimplePrimaryKey:
+		keyValues, appErr = marshalSimplePrimaryKey(typedKey)
+	case common_models.DynamodbComplexPrimaryKey:
+		keyValues, appErr = marshalComplexPrimaryKey(typedKey)
+	}
+	if appErr != nil {
+		return "", appErr
+	}
+	return canonicalizeKey(keyValues)
+}
@@ -0,0 +1,211 @@
+package common_repositories_test
+
+import (
+	"github.com/Drathveloper/lambda_commons/v2/common_constants"
+	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/Drathveloper/lambda_commons/v2/common_models"
+	"github.com/Drathveloper/lambda_commons/v2/common_repositories"
+	"github.com/Drathveloper/lambda_commons/v2/mocks"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type DynamodbCounterRepositoryTestSuite struct {
+	suite.Suite
+	dynamodbClient    *mocks.MockDynamodbClientAPI
+	counterRepository common_repositories.DynamodbCounterRepository[common_models.DynamodbSimplePrimaryKey]
+	counterKey        common_models.DynamodbSimplePrimaryKey
+}
+
+func TestDynamodbCounterRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(DynamodbCounterRepositoryTestSuite))
+}
+
+func (suite *DynamodbCounterRepositoryTestSuite) SetupTest() {
+	controller := gomock.NewController(suite.T())
+	suite.dynamodbClient = mocks.NewMockDynamodbClientAPI(controller)
+	baseRepository := common_repositories.NewDynamodbBaseRepository(suite.dynamodbClient, "someTable")
+	suite.counterRepository = common_repositories.NewDynamodbCounterRepository[common_models.DynamodbSimplePrimaryKey](baseRepository, common_models.DynamodbCounterConfig{
+		BlockSize: 3,
+	})
+	suite.counterKey = common_models.DynamodbSimplePrimaryKey{
+		KeyName: "pk",
+		Value:   "ORDER_NUMBER",
+	}
+}
+
+func (suite *DynamodbCounterRepositoryTestSuite) TestIncrement_ShouldReturnNewValue() {
+	context := common_models.NewLambdaContext()
+	updateItemInput := dynamodb.UpdateItemInput{
+		TableName:        aws.String("someTable"),
+		UpdateExpression: aws.String("ADD #0 :0\n"),
+		ExpressionAttributeNames: map[string]string{
+			"#0": "counterValue",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":0": &types.AttributeValueMemberN{Value: "2"},
+		},
+		Key: map[string]types.AttributeValue{
+			"pk": &types.AttributeValueMemberS{Value: "ORDER_NUMBER"},
+		},
+		ReturnValues: types.ReturnValueAllNew,
+	}
+	updateItemOutput := &dynamodb.UpdateItemOutput{
+		Attributes: map[string]types.AttributeValue{
+			"counterValue": &types.AttributeValueMemberN{Value: "42"},
+		},
+	}
+
+	suite.dynamodbClient.EXPECT().UpdateItem(&context, &updateItemInput).Return(updateItemOutput, nil)
+
+	value, appErr := suite.counterRepository.Increment(&context, suite.counterKey, 2)
+
+	suite.NoError(appErr)
+	suite.Equal(int64(42), value)
+}
+
+func (suite *DynamodbCounterRepositoryTestSuite) TestIncrement_ShouldAppendConditionalUpdateInsideWriteTransaction() {
+	context := common_models.NewLambdaContext()
+	context.Set(common_constants.WriteTransaction, dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{},
+	})
+	getItemOutput := &dynamodb.GetItemOutput{
+		Item: map[string]types.AttributeValue{
+			"pk":           &types.AttributeValueMemberS{Value: "ORDER_NUMBER"},
+			"counterValue": &types.AttributeValueMemberN{Value: "10"},
+		},
+	}
+	expectedUpdate := &types.Update{
+		TableName:           aws.String("someTable"),
+		UpdateExpression:    aws.String("ADD #0 :1\n"),
+		ConditionExpression: aws.String("#0 = :0"),
+		ExpressionAttributeNames: map[string]string{
+			"#0": "counterValue",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":0": &types.AttributeValueMemberN{Value: "10"},
+			":1": &types.AttributeValueMemberN{Value: "1"},
+		},
+		Key: map[string]types.AttributeValue{
+			"pk": &types.AttributeValueMemberS{Value: "ORDER_NUMBER"},
+		},
+	}
+
+	suite.dynamodbClient.EXPECT().GetItem(&context, gomock.Any()).Return(getItemOutput, nil)
+
+	value, appErr := suite.counterRepository.Increment(&context, suite.counterKey, 1)
+	transaction, _ := context.Get(common_constants.WriteTransaction)
+
+	suite.NoError(appErr)
+	suite.Equal(int64(11), value)
+	suite.Equal(expectedUpdate, transaction.(dynamodb.TransactWriteItemsInput).TransactItems[0].Update)
+}
+
+func (suite *DynamodbCounterRepositoryTestSuite) TestNextValue_ShouldHandOutReservedBlockLocally() {
+	context := common_models.NewLambdaContext()
+	firstOutput := &dynamodb.UpdateItemOutput{
+		Attributes: map[string]types.AttributeValue{
+			"counterValue": &types.AttributeValueMemberN{Value: "3"},
+		},
+	}
+	secondOutput := &dynamodb.UpdateItemOutput{
+		Attributes: map[string]types.AttributeValue{
+			"counterValue": &types.AttributeValueMemberN{Value: "6"},
+		},
+	}
+
+	gomock.InOrder(
+		suite.dynamodbClient.EXPECT().UpdateItem(gomock.Any(), gomock.Any()).Return(firstOutput, nil),
+		suite.dynamodbClient.EXPECT().UpdateItem(gomock.Any(), gomock.Any()).Return(secondOutput, nil),
+	)
+
+	values := make([]int64, 0, 4)
+	for index := 0; index < 4; index++ {
+		value, appErr := suite.counterRepository.NextValue(&context, suite.counterKey)
+		suite.NoError(appErr)
+		values = append(values, value)
+	}
+
+	suite.Equal([]int64{1, 2, 3, 4}, values)
+}
+
+func (suite *DynamodbCounterRepositoryTestSuite) TestNextValue_ShouldNotBlockOtherCountersWhileReserving() {
+	context := common_models.NewLambdaContext()
+	otherCounterKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "pk",
+		Value:   "INVOICE_NUMBER",
+	}
+	updateItemOutput := &dynamodb.UpdateItemOutput{
+		Attributes: map[string]types.AttributeValue{
+			"counterValue": &types.AttributeValueMemberN{Value: "3"},
+		},
+	}
+	isReserving := make(chan struct{})
+	release := make(chan struct{})
+	values := make(chan int64, 2)
+
+	suite.dynamodbClient.EXPECT().UpdateItem(gomock.Any(), gomock.Any()).DoAndReturn(func(_ *common_models.LambdaContext, input *dynamodb.UpdateItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+		if input.Key["pk"].(*types.AttributeValueMemberS).Value == "ORDER_NUMBER" {
+			close(isReserving)
+			<-release
+		}
+		return updateItemOutput, nil
+	}).Times(2)
+
+	for index := 0; index < 2; index++ {
+		go func() {
+			value, _ := suite.counterRepository.NextValue(&context, suite.counterKey)
+			values <- value
+		}()
+		if index == 0 {
+			<-isReserving
+		}
+	}
+	otherValue, appErr := suite.counterRepository.NextValue(&context, otherCounterKey)
+	close(release)
+
+	suite.NoError(appErr)
+	suite.Equal(int64(1), otherValue)
+	suite.ElementsMatch([]int64{1, 2}, []int64{<-values, <-values})
+}
+
+func (suite *DynamodbCounterRepositoryTestSuite) TestReserveBlock_ShouldReserveOutsideWriteTransaction() {
+	context := common_models.NewLambdaContext()
+	context.Set(common_constants.WriteTransaction, dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{},
+	})
+	updateItemOutput := &dynamodb.UpdateItemOutput{
+		Attributes: map[string]types.AttributeValue{
+			"counterValue": &types.AttributeValueMemberN{Value: "20"},
+		},
+	}
+
+	suite.dynamodbClient.EXPECT().UpdateItem(gomock.Any(), gomock.Any()).Return(updateItemOutput, nil)
+
+	block, appErr := suite.counterRepository.ReserveBlock(&context, suite.counterKey, 10)
+	transaction, _ := context.Get(common_constants.WriteTransaction)
+
+	suite.NoError(appErr)
+	suite.Equal(common_models.DynamodbCounterBlock{First: 11, Last: 20}, block)
+	suite.Empty(transaction.(dynamodb.TransactWriteItemsInput).TransactItems)
+}
+
+func (suite *DynamodbCounterRepositoryTestSuite) TestIncrement_ShouldReturnInternalServerErrorWhenValueIsNotNumber() {
+	context := common_models.NewLambdaContext()
+	updateItemOutput := &dynamodb.UpdateItemOutput{
+		Attributes: map[string]types.AttributeValue{
+			"counterValue": &types.AttributeValueMemberS{Value: "42"},
+		},
+	}
+	expectedAppErr := common_errors.NewInternalServerError("counter value attribute must be a number")
+
+	suite.dynamodbClient.EXPECT().UpdateItem(&context, gomock.Any()).Return(updateItemOutput, nil)
+
+	_, appErr := suite.counterRepository.Increment(&context, suite.counterKey, 1)
+
+	suite.Equal(expectedAppErr, appErr)
+}
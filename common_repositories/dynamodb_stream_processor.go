@@ -0,0 +1,134 @@
+package common_repositories
+
+import (
+	"context"
+	"fmt"
+	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/Drathveloper/lambda_commons/v2/common_helpers"
+	"github.com/Drathveloper/lambda_commons/v2/common_models"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+)
+
+const (
+	dynamodbTimeToLivePrincipal = "dynamodb.amazonaws.com"
+	dynamodbServiceIdentityType = "Service"
+)
+
+type DynamodbStreamProcessor[T any] interface {
+	Handle(ctx context.Context, event events.DynamoDBEvent) (events.DynamoDBEventResponse, error)
+	HandleWithContext(ctx *common_models.LambdaContext, event events.DynamoDBEvent) events.DynamoDBEventResponse
+}
+
+type dynamodbStreamProcessor[T any] struct {
+	handlers common_models.DynamodbStreamHandlers[T]
+}
+
+func NewDynamodbStreamProcessor[T any](handlers common_models.DynamodbStreamHandlers[T]) DynamodbStreamProcessor[T] {
+	return &dynamodbStreamProcessor[T]{
+		handlers: handlers,
+	}
+}
+
+func (processor *dynamodbStreamProcessor[T]) Handle(ctx context.Context, event events.DynamoDBEvent) (events.DynamoDBEventResponse, error) {
+	lambdaContext := common_models.NewLambdaContextFromContext(ctx)
+	return processor.HandleWithContext(&lambdaContext, event), nil
+}
+
+// Records of a shard are processed in order and processing stops at the first failure: Lambda checkpoints at the
+// lowest reported sequence number and retries everything after it, so handling later records would only duplicate work.
+func (processor *dynamodbStreamProcessor[T]) HandleWithContext(ctx *common_models.LambdaContext, event events.DynamoDBEvent) events.DynamoDBEventResponse {
+	response := events.DynamoDBEventResponse{
+		BatchItemFailures: make([]events.DynamoDBBatchItemFailure, 0),
+	}
+	for _, eventRecord := range event.Records {
+		if appErr := processor.handleRecord(ctx, eventRecord); appErr != nil {
+			response.BatchItemFailures = append(response.BatchItemFailures, events.DynamoDBBatchItemFailure{
+				ItemIdentifier: eventRecord.Change.SequenceNumber,
+			})
+			return response
+		}
+	}
+	return response
+}
+
+func (processor *dynamodbStreamProcessor[T]) handleRecord(ctx *common_models.LambdaContext, eventRecord events.DynamoDBEventRecord) common_errors.GenericApplicationError {
+	handler := processor.selectHandler(common_models.DynamodbStreamEventName(eventRecord.EventName))
+	if handler == nil {
+		return nil
+	}
+	record, appErr := decodeDynamodbStreamRecord[T](ctx, eventRecord, processor.handlers.ImageDecoder)
+	if appErr != nil {
+		return appErr
+	}
+	return handler(ctx, record)
+}
+
+func (processor *dynamodbStreamProcessor[T]) selectHandler(eventName common_models.DynamodbStreamEventName) func(ctx *common_models.LambdaContext, record common_models.DynamodbStreamRecord[T]) common_errors.GenericApplicationError {
+	switch eventName {
+	case common_models.DynamodbStreamInsert:
+		return processor.handlers.OnInsert
+	case common_models.DynamodbStreamModify:
+		return processor.handlers.OnModify
+	case common_models.DynamodbStreamRemove:
+		return processor.handlers.OnRemove
+	default:
+		return nil
+	}
+}
+
+// Images are decoded as stored; use a processor with an image decoder for tables with compressed, encrypted or
+// offloaded attributes.
+func DecodeDynamodbStreamRecord[T any](eventRecord events.DynamoDBEventRecord) (common_models.DynamodbStreamRecord[T], common_errors.GenericApplicationError) {
+	return decodeDynamodbStreamRecord[T](nil, eventRecord, nil)
+}
+
+func decodeDynamodbStreamRecord[T any](ctx *common_models.LambdaContext, eventRecord events.DynamoDBEventRecord, decoder common_models.DynamodbStreamImageDecoder) (common_models.DynamodbStreamRecord[T], common_errors.GenericApplicationError) {
+	record := common_models.DynamodbStreamRecord[T]{
+		EventID:            eventRecord.EventID,
+		EventName:          common_models.DynamodbStreamEventName(eventRecord.EventName),
+		EventSourceArn:     eventRecord.EventSourceArn,
+		SequenceNumber:     eventRecord.Change.SequenceNumber,
+		CreationTime:       eventRecord.Change.ApproximateCreationDateTime.Time,
+		IsTimeToLiveDelete: isTimeToLiveDelete(eventRecord),
+	}
+	keys, err := common_helpers.ConvertDynamodbStreamImage(eventRecord.Change.Keys)
+	if err != nil {
+		return record, common_errors.NewInternalServerError("error while converting stream record keys")
+	}
+	record.Keys = keys
+	var appErr common_errors.GenericApplicationError
+	if record.OldImage, appErr = decodeDynamodbStreamImage[T](ctx, eventRecord.Change.OldImage, common_models.DynamodbStreamOldImage, decoder); appErr != nil {
+		return record, appErr
+	}
+	if record.NewImage, appErr = decodeDynamodbStreamImage[T](ctx, eventRecord.Change.NewImage, common_models.DynamodbStreamNewImage, decoder); appErr != nil {
+		return record, appErr
+	}
+	return record, nil
+}
+
+func decodeDynamodbStreamImage[T any](ctx *common_models.LambdaContext, image map[string]events.DynamoDBAttributeValue, imageName common_models.DynamodbStreamImageName, decoder common_models.DynamodbStreamImageDecoder) (*T, common_errors.GenericApplicationError) {
+	if len(image) == 0 {
+		return nil, nil
+	}
+	attributes, err := common_helpers.ConvertDynamodbStreamImage(image)
+	if err != nil {
+		return nil, common_errors.NewInternalServerError(fmt.Sprintf("error while decoding stream record %s image", imageName))
+	}
+	if decoder != nil {
+		if appErr := decoder(ctx, imageName, attributes); appErr != nil {
+			return nil, appErr
+		}
+	}
+	item := new(T)
+	if err := attributevalue.UnmarshalMap(attributes, item); err != nil {
+		return nil, common_errors.NewInternalServerError(fmt.Sprintf("error while decoding stream record %s image", imageName))
+	}
+	return item, nil
+}
+
+func isTimeToLiveDelete(eventRecord events.DynamoDBEventRecord) bool {
+	return eventRecord.UserIdentity != nil &&
+		eventRecord.UserIdentity.Type == dynamodbServiceIdentityType &&
+		eventRecord.UserIdentity.PrincipalID == dynamodbTimeToLivePrincipal
+}
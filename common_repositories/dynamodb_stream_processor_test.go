@@ -0,0 +1,216 @@
+package common_repositories_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/Drathveloper/lambda_commons/v2/common_models"
+	"github.com/Drathveloper/lambda_commons/v2/common_repositories"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type streamItem struct {
+	ID      string            `dynamodbav:"id"`
+	Amount  int               `dynamodbav:"amount"`
+	Tags    []string          `dynamodbav:"tags,stringset"`
+	Details map[string]string `dynamodbav:"details"`
+}
+
+const streamEventJSON = `{
+	"Records": [
+		{
+			"eventID": "event-1",
+			"eventName": "INSERT",
+			"eventSourceARN": "arn:aws:dynamodb:eu-west-1:123456789012:table/items/stream/2021",
+			"dynamodb": {
+				"Keys": {"id": {"S": "item-1"}},
+				"NewImage": {"id": {"S": "item-1"}, "amount": {"N": "10"}, "tags": {"SS": ["a", "b"]}, "details": {"M": {"color": {"S": "red"}}}},
+				"SequenceNumber": "100"
+			}
+		},
+		{
+			"eventID": "event-2",
+			"eventName": "MODIFY",
+			"dynamodb": {
+				"Keys": {"id": {"S": "item-1"}},
+				"OldImage": {"id": {"S": "item-1"}, "amount": {"N": "10"}},
+				"NewImage": {"id": {"S": "item-1"}, "amount": {"N": "20"}},
+				"SequenceNumber": "200"
+			}
+		},
+		{
+			"eventID": "event-3",
+			"eventName": "REMOVE",
+			"userIdentity": {"type": "Service", "principalId": "dynamodb.amazonaws.com"},
+			"dynamodb": {
+				"Keys": {"id": {"S": "item-1"}},
+				"OldImage": {"id": {"S": "item-1"}, "amount": {"N": "20"}},
+				"SequenceNumber": "300"
+			}
+		}
+	]
+}`
+
+func parseStreamEvent(t *testing.T) events.DynamoDBEvent {
+	var event events.DynamoDBEvent
+	if err := json.Unmarshal([]byte(streamEventJSON), &event); err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
+
+func TestDecodeDynamodbStreamRecord_ShouldDecodeImagesAndKeys(t *testing.T) {
+	event := parseStreamEvent(t)
+
+	record, appErr := common_repositories.DecodeDynamodbStreamRecord[streamItem](event.Records[0])
+
+	assert.Nil(t, appErr)
+	assert.Equal(t, "event-1", record.EventID)
+	assert.Equal(t, common_models.DynamodbStreamInsert, record.EventName)
+	assert.Equal(t, "100", record.SequenceNumber)
+	assert.Equal(t, map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "item-1"}}, record.Keys)
+	assert.Nil(t, record.OldImage)
+	assert.Equal(t, &streamItem{ID: "item-1", Amount: 10, Tags: []string{"a", "b"}, Details: map[string]string{"color": "red"}}, record.NewImage)
+	assert.False(t, record.IsTimeToLiveDelete)
+}
+
+func TestDecodeDynamodbStreamRecord_ShouldDetectTimeToLiveDeletes(t *testing.T) {
+	event := parseStreamEvent(t)
+
+	record, appErr := common_repositories.DecodeDynamodbStreamRecord[streamItem](event.Records[2])
+
+	assert.Nil(t, appErr)
+	assert.True(t, record.IsTimeToLiveDelete)
+	assert.Equal(t, &streamItem{ID: "item-1", Amount: 20}, record.OldImage)
+	assert.Nil(t, record.NewImage)
+}
+
+func TestDecodeDynamodbStreamRecord_ShouldReturnErrorWhenImageDoesNotMatchType(t *testing.T) {
+	eventRecord := events.DynamoDBEventRecord{
+		EventName: "INSERT",
+		Change: events.DynamoDBStreamRecord{
+			NewImage: map[string]events.DynamoDBAttributeValue{"amount": events.NewStringAttribute("not a number")},
+		},
+	}
+
+	_, appErr := common_repositories.DecodeDynamodbStreamRecord[streamItem](eventRecord)
+
+	assert.Equal(t, common_errors.NewInternalServerError("error while decoding stream record new image"), appErr)
+}
+
+func TestDynamodbStreamProcessor_ShouldDispatchRecordsByEventName(t *testing.T) {
+	event := parseStreamEvent(t)
+	dispatched := make([]string, 0)
+	processor := common_repositories.NewDynamodbStreamProcessor[streamItem](common_models.DynamodbStreamHandlers[streamItem]{
+		OnInsert: func(ctx *common_models.LambdaContext, record common_models.DynamodbStreamRecord[streamItem]) common_errors.GenericApplicationError {
+			dispatched = append(dispatched, "insert:"+record.NewImage.ID)
+			return nil
+		},
+		OnModify: func(ctx *common_models.LambdaContext, record common_models.DynamodbStreamRecord[streamItem]) common_errors.GenericApplicationError {
+			assert.Equal(t, 10, record.OldImage.Amount)
+			assert.Equal(t, 20, record.NewImage.Amount)
+			dispatched = append(dispatched, "modify:"+record.NewImage.ID)
+			return nil
+		},
+		OnRemove: func(ctx *common_models.LambdaContext, record common_models.DynamodbStreamRecord[streamItem]) common_errors.GenericApplicationError {
+			dispatched = append(dispatched, "remove:"+record.OldImage.ID)
+			return nil
+		},
+	})
+
+	response, err := processor.Handle(context.Background(), event)
+
+	assert.NoError(t, err)
+	assert.Empty(t, response.BatchItemFailures)
+	assert.Equal(t, []string{"insert:item-1", "modify:item-1", "remove:item-1"}, dispatched)
+}
+
+func TestDynamodbStreamProcessor_ShouldSkipRecordsWithoutHandler(t *testing.T) {
+	event := parseStreamEvent(t)
+	dispatched := 0
+	processor := common_repositories.NewDynamodbStreamProcessor[streamItem](common_models.DynamodbStreamHandlers[streamItem]{
+		OnModify: func(ctx *common_models.LambdaContext, record common_models.DynamodbStreamRecord[streamItem]) common_errors.GenericApplicationError {
+			dispatched++
+			return nil
+		},
+	})
+
+	response, err := processor.Handle(context.Background(), event)
+
+	assert.NoError(t, err)
+	assert.Empty(t, response.BatchItemFailures)
+	assert.Equal(t, 1, dispatched)
+}
+
+func TestDynamodbStreamProcessor_ShouldReportFirstFailureAndStopProcessing(t *testing.T) {
+	event := parseStreamEvent(t)
+	removeCalled := false
+	processor := common_repositories.NewDynamodbStreamProcessor[streamItem](common_models.DynamodbStreamHandlers[streamItem]{
+		OnInsert: func(ctx *common_models.LambdaContext, record common_models.DynamodbStreamRecord[streamItem]) common_errors.GenericApplicationError {
+			return nil
+		},
+		OnModify: func(ctx *common_models.LambdaContext, record common_models.DynamodbStreamRecord[streamItem]) common_errors.GenericApplicationError {
+			return common_errors.NewInternalServerError("downstream failure")
+		},
+		OnRemove: func(ctx *common_models.LambdaContext, record common_models.DynamodbStreamRecord[streamItem]) common_errors.GenericApplicationError {
+			removeCalled = true
+			return nil
+		},
+	})
+	expected := events.DynamoDBEventResponse{
+		BatchItemFailures: []events.DynamoDBBatchItemFailure{{ItemIdentifier: "200"}},
+	}
+
+	response, err := processor.Handle(context.Background(), event)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, response)
+	assert.False(t, removeCalled)
+}
+
+func TestDynamodbStreamProcessor_ShouldRunImageDecoderBeforeDecoding(t *testing.T) {
+	event := parseStreamEvent(t)
+	amounts := make([]int, 0)
+	imageNames := make([]common_models.DynamodbStreamImageName, 0)
+	processor := common_repositories.NewDynamodbStreamProcessor[streamItem](common_models.DynamodbStreamHandlers[streamItem]{
+		ImageDecoder: func(ctx *common_models.LambdaContext, imageName common_models.DynamodbStreamImageName, image map[string]types.AttributeValue) common_errors.GenericApplicationError {
+			imageNames = append(imageNames, imageName)
+			image["amount"] = &types.AttributeValueMemberN{Value: "99"}
+			return nil
+		},
+		OnInsert: func(ctx *common_models.LambdaContext, record common_models.DynamodbStreamRecord[streamItem]) common_errors.GenericApplicationError {
+			amounts = append(amounts, record.NewImage.Amount)
+			return nil
+		},
+	})
+
+	response, err := processor.Handle(context.Background(), event)
+
+	assert.NoError(t, err)
+	assert.Empty(t, response.BatchItemFailures)
+	assert.Equal(t, []int{99}, amounts)
+	assert.Contains(t, imageNames, common_models.DynamodbStreamNewImage)
+}
+
+func TestDynamodbStreamProcessor_ShouldReportRecordWhenImageDecoderFails(t *testing.T) {
+	event := parseStreamEvent(t)
+	processor := common_repositories.NewDynamodbStreamProcessor[streamItem](common_models.DynamodbStreamHandlers[streamItem]{
+		ImageDecoder: func(ctx *common_models.LambdaContext, imageName common_models.DynamodbStreamImageName, image map[string]types.AttributeValue) common_errors.GenericApplicationError {
+			return common_errors.NewInternalServerError("error while decrypting attribute email")
+		},
+		OnInsert: func(ctx *common_models.LambdaContext, record common_models.DynamodbStreamRecord[streamItem]) common_errors.GenericApplicationError {
+			return nil
+		},
+	})
+	expected := events.DynamoDBEventResponse{
+		BatchItemFailures: []events.DynamoDBBatchItemFailure{{ItemIdentifier: "100"}},
+	}
+
+	response, err := processor.Handle(context.Background(), event)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, response)
+}
@@ -1,9 +1,11 @@
 package common_repositories
 
 import (
+	"compress/gzip"
 	"encoding/json"
 	"fmt"
 	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/Drathveloper/lambda_commons/v2/common_helpers"
 	"github.com/Drathveloper/lambda_commons/v2/common_models"
 	"github.com/go-redis/redis/v8"
 	"time"
@@ -17,15 +19,21 @@ type RedisBaseRepository interface {
 }
 
 type redisBaseRepository struct {
-	client    *redis.Client
-	namespace string
+	client             *redis.Client
+	namespace          string
+	compressionCodec   common_models.CompressionCodec
+	compressionMinSize int
 }
 
-func NewRedisBaseRepository(client *redis.Client, namespace string) RedisBaseRepository {
-	return &redisBaseRepository{
+func NewRedisBaseRepository(client *redis.Client, namespace string, options ...RedisBaseRepositoryOption) RedisBaseRepository {
+	repository := &redisBaseRepository{
 		client:    client,
 		namespace: namespace,
 	}
+	for _, option := range options {
+		option(repository)
+	}
+	return repository
 }
 
 func (repository *redisBaseRepository) Save(ctx *common_models.LambdaContext, redisEntity common_models.RedisEntity) common_errors.GenericApplicationError {
@@ -34,6 +42,12 @@ func (repository *redisBaseRepository) Save(ctx *common_models.LambdaContext, re
 	if err != nil {
 		return common_errors.NewInternalServerError("error while marshaling value")
 	}
+	if repository.compressionCodec != nil && len(marshaledValue) >= repository.compressionMinSize {
+		marshaledValue, err = common_helpers.CompressWithHeader(repository.compressionCodec, marshaledValue)
+		if err != nil {
+			return common_errors.NewInternalServerError("error while compressing value")
+		}
+	}
 	err = repository.client.Set(ctx, namespacedKey, marshaledValue, redisEntity.ExpirationTime).Err()
 	if err != nil {
 		return common_errors.NewInternalServerError(fmt.Sprintf("error while saving redis key: %s", namespacedKey))
@@ -50,7 +64,11 @@ func (repository *redisBaseRepository) FindKey(ctx *common_models.LambdaContext,
 		}
 		return false, common_errors.NewInternalServerError(fmt.Sprintf("error while reading redis key: %s", namespacedKey))
 	}
-	err = json.Unmarshal([]byte(result), &value)
+	data, _, err := common_helpers.DecompressWithHeader([]byte(result), repository.compressionCodec, common_helpers.NewGzipCodec(gzip.DefaultCompression), common_helpers.NewZstdCodec(common_helpers.ZstdDefaultCompression))
+	if err != nil {
+		return true, common_errors.NewInternalServerError("error while decompressing result")
+	}
+	err = json.Unmarshal(data, &value)
 	if err != nil {
 		return true, common_errors.NewInternalServerError(fmt.Sprintf("error while unmarshalling result"))
 	}
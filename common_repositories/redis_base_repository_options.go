@@ -0,0 +1,12 @@
+package common_repositories
+
+import "github.com/Drathveloper/lambda_commons/v2/common_models"
+
+type RedisBaseRepositoryOption func(repository *redisBaseRepository)
+
+func WithRedisCompression(codec common_models.CompressionCodec, minSize int) RedisBaseRepositoryOption {
+	return func(repository *redisBaseRepository) {
+		repository.compressionCodec = codec
+		repository.compressionMinSize = minSize
+	}
+}
@@ -1,10 +1,12 @@
 package common_repositories_test
 
 import (
+	"compress/gzip"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/Drathveloper/lambda_commons/v2/common_helpers"
 	"github.com/Drathveloper/lambda_commons/v2/common_models"
 	"github.com/Drathveloper/lambda_commons/v2/common_repositories"
 	"github.com/go-redis/redismock/v8"
@@ -211,3 +213,39 @@ func (suite *RedisBaseRepositoryTestSuite) TestDeleteKeyShouldReturnErrorWhenDel
 
 	suite.Assert().Equal(expectedErr, err)
 }
+
+func (suite *RedisBaseRepositoryTestSuite) TestSaveShouldCompressValueWhenCompressionEnabled() {
+	redisClient, redisClientMock := redismock.NewClientMock()
+	codec := common_helpers.NewGzipCodec(gzip.DefaultCompression)
+	repository := common_repositories.NewRedisBaseRepository(redisClient, suite.namespace, common_repositories.WithRedisCompression(codec, 1))
+	ctx := common_models.NewLambdaContext()
+	namespacedKey := fmt.Sprintf("%s:%s", suite.namespace, suite.key)
+	redisEntity := common_models.RedisEntity{
+		Key:            suite.key,
+		Value:          DummyValue{Field: "value"},
+		ExpirationTime: time.Hour,
+	}
+	marshaledValue, _ := json.Marshal(redisEntity.Value)
+	compressedValue, _ := common_helpers.CompressWithHeader(codec, marshaledValue)
+	redisClientMock.ExpectSet(namespacedKey, compressedValue, time.Hour).SetVal("")
+
+	err := repository.Save(&ctx, redisEntity)
+
+	suite.NoError(err)
+	suite.NoError(redisClientMock.ExpectationsWereMet())
+}
+
+func (suite *RedisBaseRepositoryTestSuite) TestFindKeyShouldDecompressCompressedValue() {
+	ctx := common_models.NewLambdaContext()
+	namespacedKey := fmt.Sprintf("%s:%s", suite.namespace, suite.key)
+	compressedValue, _ := common_helpers.CompressWithHeader(common_helpers.NewGzipCodec(gzip.DefaultCompression), []byte(`{"field":"value"}`))
+	var value DummyValue
+
+	suite.client.ExpectGet(namespacedKey).SetVal(string(compressedValue))
+
+	exists, err := suite.baseRepository.FindKey(&ctx, suite.key, &value)
+
+	suite.NoError(err)
+	suite.True(exists)
+	suite.Equal("value", value.Field)
+}
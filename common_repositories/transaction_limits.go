@@ -0,0 +1,94 @@
+package common_repositories
+
+import (
+	"fmt"
+	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/Drathveloper/lambda_commons/v2/common_helpers"
+	"github.com/Drathveloper/lambda_commons/v2/common_models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	maxTransactionItems       = 100
+	maxTransactionPayloadSize = 4 * 1024 * 1024
+	maxTransactionItemSize    = 400 * 1024
+)
+
+type transactionPart struct {
+	firstItemIndex int
+	itemCount      int
+}
+
+func validateWriteTransactionLimits(transactItems []types.TransactWriteItem, transactWriteItem types.TransactWriteItem, itemMetadata common_models.DynamodbTransactWriteItemMetadata, isBestEffort bool) common_errors.GenericApplicationError {
+	index := len(transactItems)
+	itemSize := estimateTransactWriteItemSize(transactWriteItem)
+	if itemSize > maxTransactionItemSize {
+		message := fmt.Sprintf("%s exceeds the maximum item size of %d bytes", describeTransactionItem(index, itemMetadata.Label), maxTransactionItemSize)
+		return common_errors.NewTransactionLimitExceededError(message, index, itemMetadata.Label)
+	}
+	if isBestEffort {
+		return nil
+	}
+	if index+1 > maxTransactionItems {
+		message := fmt.Sprintf("write transaction cannot contain more than %d items, rejected %s", maxTransactionItems, describeTransactionItem(index, itemMetadata.Label))
+		return common_errors.NewTransactionLimitExceededError(message, index, itemMetadata.Label)
+	}
+	payloadSize := itemSize
+	for _, transactItem := range transactItems {
+		payloadSize += estimateTransactWriteItemSize(transactItem)
+	}
+	if payloadSize > maxTransactionPayloadSize {
+		message := fmt.Sprintf("write transaction payload cannot exceed %d bytes, rejected %s", maxTransactionPayloadSize, describeTransactionItem(index, itemMetadata.Label))
+		return common_errors.NewTransactionLimitExceededError(message, index, itemMetadata.Label)
+	}
+	return nil
+}
+
+func splitWriteTransaction(transactItems []types.TransactWriteItem) []transactionPart {
+	parts := make([]transactionPart, 0)
+	current := transactionPart{}
+	currentSize := 0
+	for index, transactItem := range transactItems {
+		itemSize := estimateTransactWriteItemSize(transactItem)
+		if current.itemCount > 0 && (current.itemCount >= maxTransactionItems || currentSize+itemSize > maxTransactionPayloadSize) {
+			parts = append(parts, current)
+			current = transactionPart{firstItemIndex: index}
+			currentSize = 0
+		}
+		current.itemCount++
+		currentSize += itemSize
+	}
+	if current.itemCount > 0 {
+		parts = append(parts, current)
+	}
+	return parts
+}
+
+func estimateTransactWriteItemSize(transactWriteItem types.TransactWriteItem) int {
+	switch {
+	case transactWriteItem.Put != nil:
+		put := transactWriteItem.Put
+		return estimateWriteRequestSize(put.TableName, put.Item, put.ConditionExpression, nil, put.ExpressionAttributeNames, put.ExpressionAttributeValues)
+	case transactWriteItem.Update != nil:
+		update := transactWriteItem.Update
+		return estimateWriteRequestSize(update.TableName, update.Key, update.ConditionExpression, update.UpdateExpression, update.ExpressionAttributeNames, update.ExpressionAttributeValues)
+	case transactWriteItem.Delete != nil:
+		deleteRequest := transactWriteItem.Delete
+		return estimateWriteRequestSize(deleteRequest.TableName, deleteRequest.Key, deleteRequest.ConditionExpression, nil, deleteRequest.ExpressionAttributeNames, deleteRequest.ExpressionAttributeValues)
+	case transactWriteItem.ConditionCheck != nil:
+		conditionCheck := transactWriteItem.ConditionCheck
+		return estimateWriteRequestSize(conditionCheck.TableName, conditionCheck.Key, conditionCheck.ConditionExpression, nil, conditionCheck.ExpressionAttributeNames, conditionCheck.ExpressionAttributeValues)
+	default:
+		return 0
+	}
+}
+
+func estimateWriteRequestSize(tableName *string, item map[string]types.AttributeValue, conditionExpression *string, updateExpression *string, names map[string]string, values map[string]types.AttributeValue) int {
+	size := len(aws.ToString(tableName)) + len(aws.ToString(conditionExpression)) + len(aws.ToString(updateExpression))
+	size += common_helpers.EstimateAttributeValueMapSize(item) + common_helpers.EstimateAttributeValueMapSize(values)
+	for alias, name := range names {
+		size += len(alias) + len(name)
+	}
+	return size
+}
@@ -7,25 +7,41 @@ import (
 	"github.com/Drathveloper/lambda_commons/v2/common_errors"
 	"github.com/Drathveloper/lambda_commons/v2/common_helpers"
 	"github.com/Drathveloper/lambda_commons/v2/common_models"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
 )
 
+const maxClientRequestTokenLength = 36
+
 type DynamodbTransactionManager interface {
 	StartReadTransaction(ctx *common_models.LambdaContext) common_errors.GenericApplicationError
 	ExecuteReadTransaction(ctx *common_models.LambdaContext) (map[string]types.AttributeValue, common_errors.GenericApplicationError)
+	ExecuteReadTransactionItems(ctx *common_models.LambdaContext) ([]map[string]types.AttributeValue, common_errors.GenericApplicationError)
 	StartWriteTransaction(ctx *common_models.LambdaContext) common_errors.GenericApplicationError
+	StartWriteTransactionWithToken(ctx *common_models.LambdaContext, clientRequestToken string) common_errors.GenericApplicationError
+	StartBestEffortWriteTransaction(ctx *common_models.LambdaContext) common_errors.GenericApplicationError
 	ExecuteWriteTransaction(ctx *common_models.LambdaContext) common_errors.GenericApplicationError
+	ExecuteBestEffortWriteTransaction(ctx *common_models.LambdaContext) (common_models.DynamodbBestEffortWriteReport, common_errors.GenericApplicationError)
+	RunInReadTransaction(ctx *common_models.LambdaContext, operation func(ctx *common_models.LambdaContext) common_errors.GenericApplicationError) common_errors.GenericApplicationError
+	RunInWriteTransaction(ctx *common_models.LambdaContext, operation func(ctx *common_models.LambdaContext) common_errors.GenericApplicationError) common_errors.GenericApplicationError
 }
 
 type dynamodbTransactionalRepository struct {
-	client common_models.DynamodbClientAPI
+	client      common_models.DynamodbClientAPI
+	retryPolicy common_models.RetryPolicy
 }
 
-func NewDynamodbTransactionManager(client common_models.DynamodbClientAPI) DynamodbTransactionManager {
-	return &dynamodbTransactionalRepository{
-		client: client,
+func NewDynamodbTransactionManager(client common_models.DynamodbClientAPI, options ...DynamodbTransactionManagerOption) DynamodbTransactionManager {
+	repository := &dynamodbTransactionalRepository{
+		client:      client,
+		retryPolicy: common_helpers.NewDefaultRetryPolicy(),
+	}
+	for _, option := range options {
+		option(repository)
 	}
+	return repository
 }
 
 func (repository *dynamodbTransactionalRepository) StartReadTransaction(ctx *common_models.LambdaContext) common_errors.GenericApplicationError {
@@ -40,58 +56,425 @@ func (repository *dynamodbTransactionalRepository) StartReadTransaction(ctx *com
 	return nil
 }
 
+// Deprecated: items read from the same table overwrite each other in the merged map, use
+// ExecuteReadTransactionItems or the handles returned by the repositories instead. Plain finders called inside the
+// read transaction still enqueue their reads for this method and return an empty item.
 func (repository *dynamodbTransactionalRepository) ExecuteReadTransaction(ctx *common_models.LambdaContext) (map[string]types.AttributeValue, common_errors.GenericApplicationError) {
-	input, exists := ctx.Get(common_constants.ReadTransaction)
-	defer ctx.Set(common_constants.ReadTransaction, nil)
-	if !exists {
-		return nil, common_errors.NewInternalServerError("there is no read transaction in progress")
-	}
-	transactionInput := input.(dynamodb.TransactGetItemsInput)
-	transactionOutput, err := repository.client.TransactGetItems(ctx, &transactionInput)
-	if err != nil {
-		return nil, repository.handleTransactionError(err)
+	transactionInput, responses, appErr := repository.executeReadTransaction(ctx)
+	if appErr != nil {
+		return nil, appErr
 	}
 	tableNames := make([]string, 0)
 	for _, request := range transactionInput.TransactItems {
 		tableNames = append(tableNames, *request.Get.TableName)
 	}
-	return common_helpers.MergeDynamoDBResponsesIntoAttributeValueMap(tableNames, transactionOutput.Responses)
+	return common_helpers.MergeDynamoDBResponsesIntoAttributeValueMap(tableNames, responses)
+}
+
+func (repository *dynamodbTransactionalRepository) ExecuteReadTransactionItems(ctx *common_models.LambdaContext) ([]map[string]types.AttributeValue, common_errors.GenericApplicationError) {
+	transactionInput, responses, appErr := repository.executeReadTransaction(ctx)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if len(responses) != len(transactionInput.TransactItems) {
+		return nil, common_errors.NewInternalServerError("the number of item responses must be the same than the number of requested items")
+	}
+	return itemsFromResponses(responses), nil
+}
+
+func (repository *dynamodbTransactionalRepository) executeReadTransaction(ctx *common_models.LambdaContext) (dynamodb.TransactGetItemsInput, []types.ItemResponse, common_errors.GenericApplicationError) {
+	input, _ := ctx.Get(common_constants.ReadTransaction)
+	resultsInput, _ := ctx.Get(common_constants.ReadTransactionResults)
+	rollbackErr := getRollbackOnlyError(ctx, common_constants.ReadTransactionRollbackOnly)
+	defer discardReadTransaction(ctx)
+	transactionInput, exists := input.(dynamodb.TransactGetItemsInput)
+	if !exists {
+		return dynamodb.TransactGetItemsInput{}, nil, common_errors.NewInternalServerError("there is no read transaction in progress")
+	}
+	if rollbackErr != nil {
+		return dynamodb.TransactGetItemsInput{}, nil, rollbackErr
+	}
+	if len(transactionInput.TransactItems) == 0 {
+		return transactionInput, []types.ItemResponse{}, nil
+	}
+	var transactionOutput *dynamodb.TransactGetItemsOutput
+	appErr := repository.executeWithRetries(ctx, nil, 0, func() error {
+		var err error
+		transactionOutput, err = repository.client.TransactGetItems(ctx, &transactionInput)
+		return err
+	})
+	if appErr != nil {
+		return dynamodb.TransactGetItemsInput{}, nil, appErr
+	}
+	responses := transactionOutput.Responses
+	if results, ok := resultsInput.(*common_models.DynamodbReadTransactionResults); ok {
+		items := itemsFromResponses(responses)
+		if appErr := results.Complete(items); appErr != nil {
+			return dynamodb.TransactGetItemsInput{}, nil, appErr
+		}
+		for index := range responses {
+			responses[index].Item = items[index]
+		}
+	}
+	return transactionInput, responses, nil
 }
 
 func (repository *dynamodbTransactionalRepository) StartWriteTransaction(ctx *common_models.LambdaContext) common_errors.GenericApplicationError {
 	if ctx.Exists(common_constants.WriteTransaction) {
 		return common_errors.NewInternalServerError("there is already a write transaction in progress in this scope")
 	}
+	return repository.StartWriteTransactionWithToken(ctx, deriveClientRequestToken(ctx))
+}
+
+func (repository *dynamodbTransactionalRepository) StartWriteTransactionWithToken(ctx *common_models.LambdaContext, clientRequestToken string) common_errors.GenericApplicationError {
+	if ctx.Exists(common_constants.WriteTransaction) {
+		return common_errors.NewInternalServerError("there is already a write transaction in progress in this scope")
+	}
+	if len(clientRequestToken) > maxClientRequestTokenLength {
+		return common_errors.NewInternalServerError("client request token must not exceed 36 characters")
+	}
 	transactWriteItems := make([]types.TransactWriteItem, 0)
 	transactionInput := dynamodb.TransactWriteItemsInput{
 		TransactItems: transactWriteItems,
 	}
+	if clientRequestToken != "" {
+		transactionInput.ClientRequestToken = aws.String(clientRequestToken)
+	}
 	ctx.Set(common_constants.WriteTransaction, transactionInput)
 	return nil
 }
 
+func (repository *dynamodbTransactionalRepository) StartBestEffortWriteTransaction(ctx *common_models.LambdaContext) common_errors.GenericApplicationError {
+	if appErr := repository.StartWriteTransaction(ctx); appErr != nil {
+		return appErr
+	}
+	ctx.Set(common_constants.WriteTransactionBestEffort, true)
+	return nil
+}
+
 func (repository *dynamodbTransactionalRepository) ExecuteWriteTransaction(ctx *common_models.LambdaContext) common_errors.GenericApplicationError {
-	input, exists := ctx.Get(common_constants.WriteTransaction)
-	defer ctx.Set(common_constants.WriteTransaction, nil)
+	if isBestEffortWriteTransaction(ctx) {
+		_, appErr := repository.ExecuteBestEffortWriteTransaction(ctx)
+		return appErr
+	}
+	input, _ := ctx.Get(common_constants.WriteTransaction)
+	itemsMetadata := getWriteTransactionItemsMetadata(ctx)
+	rollbackErr := getRollbackOnlyError(ctx, common_constants.WriteTransactionRollbackOnly)
+	defer discardWriteTransaction(ctx)
+	transactionInput, exists := input.(dynamodb.TransactWriteItemsInput)
 	if !exists {
 		return common_errors.NewInternalServerError("there is no write transaction in progress")
 	}
-	transactionInput := input.(dynamodb.TransactWriteItemsInput)
-	_, err := repository.client.TransactWriteItems(ctx, &transactionInput)
-	if err != nil {
-		return repository.handleTransactionError(err)
+	if rollbackErr != nil {
+		return rollbackErr
+	}
+	if len(transactionInput.TransactItems) == 0 {
+		return nil
+	}
+	return repository.executeWithRetries(ctx, itemsMetadata, 0, func() error {
+		_, err := repository.client.TransactWriteItems(ctx, &transactionInput)
+		return err
+	})
+}
+
+func (repository *dynamodbTransactionalRepository) ExecuteBestEffortWriteTransaction(ctx *common_models.LambdaContext) (common_models.DynamodbBestEffortWriteReport, common_errors.GenericApplicationError) {
+	input, _ := ctx.Get(common_constants.WriteTransaction)
+	itemsMetadata := getWriteTransactionItemsMetadata(ctx)
+	rollbackErr := getRollbackOnlyError(ctx, common_constants.WriteTransactionRollbackOnly)
+	defer discardWriteTransaction(ctx)
+	transactionInput, exists := input.(dynamodb.TransactWriteItemsInput)
+	if !exists {
+		return common_models.DynamodbBestEffortWriteReport{}, common_errors.NewInternalServerError("there is no write transaction in progress")
+	}
+	if rollbackErr != nil {
+		return common_models.DynamodbBestEffortWriteReport{}, rollbackErr
+	}
+	report := common_models.DynamodbBestEffortWriteReport{
+		Parts: make([]common_models.DynamodbTransactionPartReport, 0),
+	}
+	var firstAppErr common_errors.GenericApplicationError
+	for partIndex, part := range splitWriteTransaction(transactionInput.TransactItems) {
+		lastItemIndex := part.firstItemIndex + part.itemCount
+		partInput := dynamodb.TransactWriteItemsInput{
+			TransactItems: transactionInput.TransactItems[part.firstItemIndex:lastItemIndex],
+		}
+		if transactionInput.ClientRequestToken != nil {
+			partToken := fmt.Sprintf("%s#%d", aws.ToString(transactionInput.ClientRequestToken), partIndex)
+			partInput.ClientRequestToken = aws.String(uuid.NewSHA1(uuid.NameSpaceOID, []byte(partToken)).String())
+		}
+		partMetadata := make([]common_models.DynamodbTransactWriteItemMetadata, 0, part.itemCount)
+		for index := part.firstItemIndex; index < lastItemIndex; index++ {
+			partMetadata = append(partMetadata, getTransactionItemMetadata(itemsMetadata, index))
+		}
+		appErr := repository.executeWithRetries(ctx, partMetadata, part.firstItemIndex, func() error {
+			_, err := repository.client.TransactWriteItems(ctx, &partInput)
+			return err
+		})
+		if appErr != nil && firstAppErr == nil {
+			firstAppErr = appErr
+		}
+		report.Parts = append(report.Parts, common_models.DynamodbTransactionPartReport{
+			FirstItemIndex: part.firstItemIndex,
+			ItemCount:      part.itemCount,
+			IsCommitted:    appErr == nil,
+			Error:          appErr,
+		})
+	}
+	return report, firstAppErr
+}
+
+func (repository *dynamodbTransactionalRepository) RunInReadTransaction(ctx *common_models.LambdaContext, operation func(ctx *common_models.LambdaContext) common_errors.GenericApplicationError) common_errors.GenericApplicationError {
+	input, _ := ctx.Get(common_constants.ReadTransaction)
+	if transactionInput, exists := input.(dynamodb.TransactGetItemsInput); exists {
+		savepoint := len(transactionInput.TransactItems)
+		if appErr := operation(ctx); appErr != nil {
+			rollbackReadTransactionToSavepoint(ctx, savepoint)
+			markRollbackOnly(ctx, common_constants.ReadTransactionRollbackOnly, appErr)
+			return appErr
+		}
+		return nil
+	}
+	if appErr := repository.StartReadTransaction(ctx); appErr != nil {
+		return appErr
+	}
+	defer discardReadTransaction(ctx)
+	if appErr := operation(ctx); appErr != nil {
+		return appErr
+	}
+	_, appErr := repository.ExecuteReadTransactionItems(ctx)
+	return appErr
+}
+
+func (repository *dynamodbTransactionalRepository) RunInWriteTransaction(ctx *common_models.LambdaContext, operation func(ctx *common_models.LambdaContext) common_errors.GenericApplicationError) common_errors.GenericApplicationError {
+	input, _ := ctx.Get(common_constants.WriteTransaction)
+	if transactionInput, exists := input.(dynamodb.TransactWriteItemsInput); exists {
+		savepoint := len(transactionInput.TransactItems)
+		if appErr := operation(ctx); appErr != nil {
+			rollbackWriteTransactionToSavepoint(ctx, savepoint)
+			markRollbackOnly(ctx, common_constants.WriteTransactionRollbackOnly, appErr)
+			return appErr
+		}
+		return nil
+	}
+	if appErr := repository.StartWriteTransaction(ctx); appErr != nil {
+		return appErr
+	}
+	defer discardWriteTransaction(ctx)
+	if appErr := operation(ctx); appErr != nil {
+		return appErr
+	}
+	return repository.ExecuteWriteTransaction(ctx)
+}
+
+func (repository *dynamodbTransactionalRepository) executeWithRetries(ctx *common_models.LambdaContext, itemsMetadata []common_models.DynamodbTransactWriteItemMetadata, firstItemIndex int, operation func() error) common_errors.GenericApplicationError {
+	for attempt := 0; ; attempt++ {
+		err := operation()
+		if err == nil {
+			return nil
+		}
+		appErr := repository.handleTransactionError(err, itemsMetadata, firstItemIndex)
+		if !isRetryableTransactionError(appErr) || !common_helpers.WaitForRetry(ctx, repository.retryPolicy, attempt) {
+			return appErr
+		}
 	}
-	return nil
 }
 
-func (repository *dynamodbTransactionalRepository) handleTransactionError(err error) common_errors.GenericApplicationError {
+func (repository *dynamodbTransactionalRepository) handleTransactionError(err error, itemsMetadata []common_models.DynamodbTransactWriteItemMetadata, firstItemIndex int) common_errors.GenericApplicationError {
+	var idempotencyErr *types.IdempotentParameterMismatchException
+	if errors.As(err, &idempotencyErr) {
+		return common_errors.NewIdempotentParameterMismatchError("client request token was already used with different transaction items")
+	}
 	var dynamodbErr *types.TransactionCanceledException
 	if errors.As(err, &dynamodbErr) {
-		for _, reason := range dynamodbErr.CancellationReasons {
-			if common_constants.ConditionalCheckFailed == *reason.Code {
-				return common_errors.NewForbiddenError(fmt.Sprintf("conditional check failed: %s", *reason.Message))
+		for partItemIndex, reason := range dynamodbErr.CancellationReasons {
+			code := aws.ToString(reason.Code)
+			index := firstItemIndex + partItemIndex
+			if common_constants.ConditionalCheckFailed == code {
+				itemMetadata := getTransactionItemMetadata(itemsMetadata, partItemIndex)
+				itemDescription := describeTransactionItem(index, itemMetadata.Label)
+				if itemMetadata.IsVersionCheck {
+					message := fmt.Sprintf("item version is stale on %s: %s", itemDescription, aws.ToString(reason.Message))
+					return common_errors.NewTransactionStaleVersionError(message, index, itemMetadata.Label)
+				}
+				message := fmt.Sprintf("conditional check failed on %s: %s", itemDescription, aws.ToString(reason.Message))
+				return common_errors.NewTransactionConditionFailedError(message, index, itemMetadata.Label)
+			}
+		}
+		for partItemIndex, reason := range dynamodbErr.CancellationReasons {
+			code := aws.ToString(reason.Code)
+			index := firstItemIndex + partItemIndex
+			itemMetadata := getTransactionItemMetadata(itemsMetadata, partItemIndex)
+			itemDescription := describeTransactionItem(index, itemMetadata.Label)
+			switch code {
+			case common_constants.TransactionConflict:
+				message := fmt.Sprintf("transaction conflict on %s: %s", itemDescription, aws.ToString(reason.Message))
+				return common_errors.NewTransactionConflictError(message, index, itemMetadata.Label)
+			case common_constants.ThrottlingError, common_constants.ProvisionedThroughputExceeded:
+				message := fmt.Sprintf("transaction throttled on %s: %s", itemDescription, aws.ToString(reason.Message))
+				return common_errors.NewTransactionThrottledError(message, code, index, itemMetadata.Label)
 			}
 		}
 	}
 	return common_errors.NewInternalServerError("generic error performing transaction")
 }
+
+func isRetryableTransactionError(appErr common_errors.GenericApplicationError) bool {
+	switch appErr.(type) {
+	case common_errors.TransactionConflictError, common_errors.TransactionThrottledError:
+		return true
+	default:
+		return false
+	}
+}
+
+func getTransactionItemMetadata(itemsMetadata []common_models.DynamodbTransactWriteItemMetadata, index int) common_models.DynamodbTransactWriteItemMetadata {
+	if index < len(itemsMetadata) {
+		return itemsMetadata[index]
+	}
+	return common_models.DynamodbTransactWriteItemMetadata{}
+}
+
+// Derived tokens only depend on the request id, the scope and the transaction sequence, so a retried invocation sends
+// the same token even when it rebuilds different items; the mismatch is then reported as a 422 instead of committing
+// the transaction twice.
+func deriveClientRequestToken(ctx *common_models.LambdaContext) string {
+	requestIDInput, _ := ctx.Get(common_constants.LambdaRequestID)
+	requestID, _ := requestIDInput.(string)
+	if requestID == "" {
+		return ""
+	}
+	if scopeInput, _ := ctx.Get(common_constants.WriteTransactionScope); scopeInput != nil {
+		requestID = fmt.Sprintf("%s#%v", requestID, scopeInput)
+	}
+	sequenceInput, _ := ctx.Get(common_constants.WriteTransactionSequence)
+	sequence, _ := sequenceInput.(int)
+	ctx.Set(common_constants.WriteTransactionSequence, sequence+1)
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(fmt.Sprintf("%s#%d", requestID, sequence))).String()
+}
+
+func describeTransactionItem(index int, label string) string {
+	if label == "" {
+		return fmt.Sprintf("transaction item %d", index)
+	}
+	return fmt.Sprintf("transaction item %d (%s)", index, label)
+}
+
+func appendToReadTransaction(ctx *common_models.LambdaContext, transactGetItem types.TransactGetItem, preparer common_models.DynamodbReadItemPreparer) (common_models.DynamodbReadHandle, bool) {
+	input, _ := ctx.Get(common_constants.ReadTransaction)
+	transactionInput, exists := input.(dynamodb.TransactGetItemsInput)
+	if !exists {
+		return common_models.DynamodbReadHandle{}, false
+	}
+	resultsInput, _ := ctx.Get(common_constants.ReadTransactionResults)
+	results, resultsExist := resultsInput.(*common_models.DynamodbReadTransactionResults)
+	if !resultsExist {
+		results = common_models.NewDynamodbReadTransactionResults()
+		ctx.Set(common_constants.ReadTransactionResults, results)
+	}
+	handle := results.NewHandle(len(transactionInput.TransactItems), preparer)
+	transactionInput.TransactItems = append(transactionInput.TransactItems, transactGetItem)
+	ctx.Set(common_constants.ReadTransaction, transactionInput)
+	return handle, true
+}
+
+func itemsFromResponses(responses []types.ItemResponse) []map[string]types.AttributeValue {
+	items := make([]map[string]types.AttributeValue, 0, len(responses))
+	for _, response := range responses {
+		items = append(items, response.Item)
+	}
+	return items
+}
+
+func appendToWriteTransaction(ctx *common_models.LambdaContext, transactWriteItem types.TransactWriteItem, itemMetadata common_models.DynamodbTransactWriteItemMetadata) (bool, common_errors.GenericApplicationError) {
+	input, _ := ctx.Get(common_constants.WriteTransaction)
+	transactionInput, exists := input.(dynamodb.TransactWriteItemsInput)
+	if !exists {
+		return false, nil
+	}
+	if appErr := validateWriteTransactionLimits(transactionInput.TransactItems, transactWriteItem, itemMetadata, isBestEffortWriteTransaction(ctx)); appErr != nil {
+		return true, appErr
+	}
+	itemsMetadata := getWriteTransactionItemsMetadata(ctx)
+	if len(itemsMetadata) > len(transactionInput.TransactItems) {
+		itemsMetadata = itemsMetadata[:len(transactionInput.TransactItems)]
+	}
+	for len(itemsMetadata) < len(transactionInput.TransactItems) {
+		itemsMetadata = append(itemsMetadata, common_models.DynamodbTransactWriteItemMetadata{})
+	}
+	transactionInput.TransactItems = append(transactionInput.TransactItems, transactWriteItem)
+	ctx.Set(common_constants.WriteTransaction, transactionInput)
+	ctx.Set(common_constants.WriteTransactionItems, append(itemsMetadata, itemMetadata))
+	return true, nil
+}
+
+func rollbackReadTransactionToSavepoint(ctx *common_models.LambdaContext, savepoint int) {
+	input, _ := ctx.Get(common_constants.ReadTransaction)
+	transactionInput, exists := input.(dynamodb.TransactGetItemsInput)
+	if !exists || savepoint >= len(transactionInput.TransactItems) {
+		return
+	}
+	transactionInput.TransactItems = transactionInput.TransactItems[:savepoint]
+	ctx.Set(common_constants.ReadTransaction, transactionInput)
+	resultsInput, _ := ctx.Get(common_constants.ReadTransactionResults)
+	if results, ok := resultsInput.(*common_models.DynamodbReadTransactionResults); ok {
+		results.DiscardFrom(savepoint)
+	}
+}
+
+func rollbackWriteTransactionToSavepoint(ctx *common_models.LambdaContext, savepoint int) {
+	input, _ := ctx.Get(common_constants.WriteTransaction)
+	transactionInput, exists := input.(dynamodb.TransactWriteItemsInput)
+	if !exists || savepoint >= len(transactionInput.TransactItems) {
+		return
+	}
+	transactionInput.TransactItems = transactionInput.TransactItems[:savepoint]
+	ctx.Set(common_constants.WriteTransaction, transactionInput)
+	if itemsMetadata := getWriteTransactionItemsMetadata(ctx); len(itemsMetadata) > savepoint {
+		ctx.Set(common_constants.WriteTransactionItems, itemsMetadata[:savepoint])
+	}
+}
+
+func discardReadTransaction(ctx *common_models.LambdaContext) {
+	ctx.Set(common_constants.ReadTransaction, nil)
+	ctx.Set(common_constants.ReadTransactionResults, nil)
+	ctx.Set(common_constants.ReadTransactionRollbackOnly, nil)
+}
+
+func discardWriteTransaction(ctx *common_models.LambdaContext) {
+	ctx.Set(common_constants.WriteTransaction, nil)
+	ctx.Set(common_constants.WriteTransactionItems, nil)
+	ctx.Set(common_constants.WriteTransactionBestEffort, nil)
+	ctx.Set(common_constants.WriteTransactionRollbackOnly, nil)
+}
+
+// A joined scope that fails marks the whole transaction rollback-only, so the outermost scope cannot commit the
+// remaining items even when the inner error was handled by the caller.
+func markRollbackOnly(ctx *common_models.LambdaContext, key string, appErr common_errors.GenericApplicationError) {
+	if !ctx.Exists(key) {
+		ctx.Set(key, appErr)
+	}
+}
+
+func getRollbackOnlyError(ctx *common_models.LambdaContext, key string) common_errors.GenericApplicationError {
+	input, _ := ctx.Get(key)
+	appErr, _ := input.(common_errors.GenericApplicationError)
+	return appErr
+}
+
+func isInWriteTransaction(ctx *common_models.LambdaContext) bool {
+	input, _ := ctx.Get(common_constants.WriteTransaction)
+	_, exists := input.(dynamodb.TransactWriteItemsInput)
+	return exists
+}
+
+func isBestEffortWriteTransaction(ctx *common_models.LambdaContext) bool {
+	input, _ := ctx.Get(common_constants.WriteTransactionBestEffort)
+	isBestEffort, _ := input.(bool)
+	return isBestEffort
+}
+
+func getWriteTransactionItemsMetadata(ctx *common_models.LambdaContext) []common_models.DynamodbTransactWriteItemMetadata {
+	input, _ := ctx.Get(common_constants.WriteTransactionItems)
+	itemsMetadata, _ := input.([]common_models.DynamodbTransactWriteItemMetadata)
+	return itemsMetadata
+}
@@ -0,0 +1,11 @@
+package common_repositories
+
+import "github.com/Drathveloper/lambda_commons/v2/common_models"
+
+type DynamodbTransactionManagerOption func(repository *dynamodbTransactionalRepository)
+
+func WithTransactionRetryPolicy(policy common_models.RetryPolicy) DynamodbTransactionManagerOption {
+	return func(repository *dynamodbTransactionalRepository) {
+		repository.retryPolicy = policy
+	}
+}
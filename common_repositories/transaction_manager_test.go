@@ -1,18 +1,21 @@
 package common_repositories_test
 
 import (
+	"context"
 	"errors"
 	"github.com/Drathveloper/lambda_commons/v2/common_constants"
 	"github.com/Drathveloper/lambda_commons/v2/common_errors"
 	"github.com/Drathveloper/lambda_commons/v2/common_models"
 	"github.com/Drathveloper/lambda_commons/v2/common_repositories"
 	"github.com/Drathveloper/lambda_commons/v2/mocks"
+	"github.com/aws/aws-lambda-go/lambdacontext"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/suite"
 	"testing"
+	"time"
 )
 
 type DynamodbTransactionManagerTestSuite struct {
@@ -98,6 +101,42 @@ func (suite *DynamodbTransactionManagerTestSuite) TestExecuteReadTransaction_Sho
 	suite.Equal(expectedItems, response)
 }
 
+func (suite *DynamodbTransactionManagerTestSuite) TestExecuteReadTransactionItems_ShouldReturnItemsInRequestOrder() {
+	context := common_models.NewLambdaContext()
+	transactionInput := dynamodb.TransactGetItemsInput{
+		TransactItems: []types.TransactGetItem{
+			{Get: &types.Get{TableName: aws.String("someTable")}},
+			{Get: &types.Get{TableName: aws.String("someTable")}},
+		},
+	}
+	firstItem := map[string]types.AttributeValue{
+		"someKey": &types.AttributeValueMemberS{
+			Value: "firstValue",
+		},
+	}
+	secondItem := map[string]types.AttributeValue{
+		"someKey": &types.AttributeValueMemberS{
+			Value: "secondValue",
+		},
+	}
+	transactionOutput := dynamodb.TransactGetItemsOutput{
+		Responses: []types.ItemResponse{
+			{Item: firstItem},
+			{Item: secondItem},
+		},
+	}
+	context.Set(common_constants.ReadTransaction, transactionInput)
+	expectedItems := []map[string]types.AttributeValue{firstItem, secondItem}
+
+	suite.dynamodbClient.EXPECT().TransactGetItems(&context, &transactionInput).Return(&transactionOutput, nil)
+
+	items, appErr := suite.transactionManager.ExecuteReadTransactionItems(&context)
+
+	suite.NoError(appErr)
+	suite.Equal(expectedItems, items)
+	suite.False(context.Exists(common_constants.ReadTransaction))
+}
+
 func (suite *DynamodbTransactionManagerTestSuite) TestExecuteReadTransaction_ShouldReturnInternalServerErrorWhenTransactionFailed() {
 	context := common_models.NewLambdaContext()
 	transactionInput := dynamodb.TransactGetItemsInput{
@@ -211,3 +250,504 @@ func (suite *DynamodbTransactionManagerTestSuite) TestExecuteWriteTransaction_Sh
 
 	suite.Equal(expectedAppErr, appErr)
 }
+
+func (suite *DynamodbTransactionManagerTestSuite) TestExecuteWriteTransaction_ShouldReturnPreconditionFailedErrorWhenVersionCheckFailed() {
+	context := common_models.NewLambdaContext()
+	transactionInput := dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{
+				Put: &types.Put{
+					TableName: aws.String("someTable"),
+				},
+			},
+			{
+				Put: &types.Put{
+					TableName: aws.String("someTable"),
+				},
+			},
+		},
+	}
+	context.Set(common_constants.WriteTransaction, transactionInput)
+	context.Set(common_constants.WriteTransactionItems, []common_models.DynamodbTransactWriteItemMetadata{
+		{},
+		{IsVersionCheck: true},
+	})
+	cause := &types.TransactionCanceledException{
+		CancellationReasons: []types.CancellationReason{
+			{Code: aws.String("None")},
+			{Code: aws.String("ConditionalCheckFailed"), Message: aws.String("The conditional request failed")},
+		},
+	}
+	expectedAppErr := common_errors.NewTransactionStaleVersionError("item version is stale on transaction item 1: The conditional request failed", 1, "")
+	suite.dynamodbClient.EXPECT().TransactWriteItems(&context, &transactionInput).Return(nil, cause)
+
+	appErr := suite.transactionManager.ExecuteWriteTransaction(&context)
+
+	suite.Equal(expectedAppErr, appErr)
+	suite.False(context.Exists(common_constants.WriteTransactionItems))
+}
+
+func (suite *DynamodbTransactionManagerTestSuite) TestExecuteWriteTransaction_ShouldReturnForbiddenErrorWhenConditionalCheckFailed() {
+	context := common_models.NewLambdaContext()
+	transactionInput := dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{
+				Put: &types.Put{
+					TableName: aws.String("someTable"),
+				},
+			},
+		},
+	}
+	context.Set(common_constants.WriteTransaction, transactionInput)
+	cause := &types.TransactionCanceledException{
+		CancellationReasons: []types.CancellationReason{
+			{Code: aws.String("ConditionalCheckFailed"), Message: aws.String("The conditional request failed")},
+		},
+	}
+	expectedAppErr := common_errors.NewTransactionConditionFailedError("conditional check failed on transaction item 0: The conditional request failed", 0, "")
+	suite.dynamodbClient.EXPECT().TransactWriteItems(&context, &transactionInput).Return(nil, cause)
+
+	appErr := suite.transactionManager.ExecuteWriteTransaction(&context)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbTransactionManagerTestSuite) TestExecuteWriteTransaction_ShouldReportLabelOfFailedConditionCheck() {
+	context := common_models.NewLambdaContext()
+	transactionInput := dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{
+				Put: &types.Put{
+					TableName: aws.String("someTable"),
+				},
+			},
+			{
+				ConditionCheck: &types.ConditionCheck{
+					TableName: aws.String("accountTable"),
+				},
+			},
+		},
+	}
+	context.Set(common_constants.WriteTransaction, transactionInput)
+	context.Set(common_constants.WriteTransactionItems, []common_models.DynamodbTransactWriteItemMetadata{
+		{},
+		{Label: "account-active"},
+	})
+	cause := &types.TransactionCanceledException{
+		CancellationReasons: []types.CancellationReason{
+			{Code: aws.String("None")},
+			{Code: aws.String("ConditionalCheckFailed"), Message: aws.String("The conditional request failed")},
+		},
+	}
+	suite.dynamodbClient.EXPECT().TransactWriteItems(&context, &transactionInput).Return(nil, cause)
+
+	appErr := suite.transactionManager.ExecuteWriteTransaction(&context)
+	transactionErr, isTransactionErr := appErr.(common_errors.TransactionError)
+
+	suite.True(isTransactionErr)
+	suite.Equal(403, transactionErr.HttpStatus())
+	suite.Equal("conditional check failed on transaction item 1 (account-active): The conditional request failed", transactionErr.Error())
+	suite.Equal("ConditionalCheckFailed", transactionErr.Reason())
+	suite.Equal(1, transactionErr.ItemIndex())
+	suite.Equal("account-active", transactionErr.ItemLabel())
+}
+
+func (suite *DynamodbTransactionManagerTestSuite) TestStartWriteTransactionWithToken_ShouldSetClientRequestToken() {
+	lambdaContext := common_models.NewLambdaContext()
+	expectedTransactionInput := dynamodb.TransactWriteItemsInput{
+		TransactItems:      []types.TransactWriteItem{},
+		ClientRequestToken: aws.String("someToken"),
+	}
+
+	appErr := suite.transactionManager.StartWriteTransactionWithToken(&lambdaContext, "someToken")
+	actualTransactionInput, _ := lambdaContext.Get(common_constants.WriteTransaction)
+
+	suite.NoError(appErr)
+	suite.Equal(expectedTransactionInput, actualTransactionInput)
+}
+
+func (suite *DynamodbTransactionManagerTestSuite) TestStartWriteTransactionWithToken_ShouldReturnInternalServerErrorWhenTokenIsTooLong() {
+	lambdaContext := common_models.NewLambdaContext()
+	expectedAppErr := common_errors.NewInternalServerError("client request token must not exceed 36 characters")
+
+	appErr := suite.transactionManager.StartWriteTransactionWithToken(&lambdaContext, "0123456789012345678901234567890123456")
+
+	suite.Equal(expectedAppErr, appErr)
+	suite.False(lambdaContext.Exists(common_constants.WriteTransaction))
+}
+
+func (suite *DynamodbTransactionManagerTestSuite) TestStartWriteTransaction_ShouldDeriveClientRequestTokenFromLambdaRequestID() {
+	parent := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{
+		AwsRequestID: "someRequestId",
+	})
+	firstContext := common_models.NewLambdaContextFromContext(parent)
+	retriedContext := common_models.NewLambdaContextFromContext(parent)
+
+	firstErr := suite.transactionManager.StartWriteTransaction(&firstContext)
+	firstInput, _ := firstContext.Get(common_constants.WriteTransaction)
+	firstContext.Set(common_constants.WriteTransaction, nil)
+	secondErr := suite.transactionManager.StartWriteTransaction(&firstContext)
+	secondInput, _ := firstContext.Get(common_constants.WriteTransaction)
+	retriedErr := suite.transactionManager.StartWriteTransaction(&retriedContext)
+	retriedInput, _ := retriedContext.Get(common_constants.WriteTransaction)
+	firstToken := aws.ToString(firstInput.(dynamodb.TransactWriteItemsInput).ClientRequestToken)
+	secondToken := aws.ToString(secondInput.(dynamodb.TransactWriteItemsInput).ClientRequestToken)
+	retriedToken := aws.ToString(retriedInput.(dynamodb.TransactWriteItemsInput).ClientRequestToken)
+
+	suite.NoError(firstErr)
+	suite.NoError(secondErr)
+	suite.NoError(retriedErr)
+	suite.Len(firstToken, 36)
+	suite.NotEqual(firstToken, secondToken)
+	suite.Equal(firstToken, retriedToken)
+}
+
+func (suite *DynamodbTransactionManagerTestSuite) executeDerivedTokenWriteTransaction(lambdaContext *common_models.LambdaContext, updatedAt string) string {
+	var clientRequestToken string
+	suite.NoError(suite.transactionManager.StartWriteTransaction(lambdaContext))
+	transactionInput, _ := lambdaContext.Get(common_constants.WriteTransaction)
+	writeTransactionInput := transactionInput.(dynamodb.TransactWriteItemsInput)
+	writeTransactionInput.TransactItems = []types.TransactWriteItem{
+		{
+			Put: &types.Put{
+				TableName: aws.String("someTable"),
+				Item: map[string]types.AttributeValue{
+					"key1":      &types.AttributeValueMemberS{Value: "foo"},
+					"updatedAt": &types.AttributeValueMemberS{Value: updatedAt},
+				},
+			},
+		},
+	}
+	lambdaContext.Set(common_constants.WriteTransaction, writeTransactionInput)
+	suite.dynamodbClient.EXPECT().TransactWriteItems(lambdaContext, gomock.Any()).DoAndReturn(func(_ *common_models.LambdaContext, input *dynamodb.TransactWriteItemsInput, _ ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+		clientRequestToken = aws.ToString(input.ClientRequestToken)
+		return &dynamodb.TransactWriteItemsOutput{}, nil
+	})
+	suite.NoError(suite.transactionManager.ExecuteWriteTransaction(lambdaContext))
+	return clientRequestToken
+}
+
+func (suite *DynamodbTransactionManagerTestSuite) TestExecuteWriteTransaction_ShouldSendSameClientRequestTokenWhenRetryRebuildsItems() {
+	parent := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{
+		AwsRequestID: "someRequestId",
+	})
+	firstContext := common_models.NewLambdaContextFromContext(parent)
+	retriedContext := common_models.NewLambdaContextFromContext(parent)
+
+	firstToken := suite.executeDerivedTokenWriteTransaction(&firstContext, "2024-01-01T00:00:00Z")
+	retriedToken := suite.executeDerivedTokenWriteTransaction(&retriedContext, "2024-01-01T00:00:01Z")
+
+	suite.Len(firstToken, 36)
+	suite.Equal(firstToken, retriedToken)
+}
+
+func (suite *DynamodbTransactionManagerTestSuite) TestExecuteWriteTransaction_ShouldReturnUnprocessableEntityErrorWhenIdempotentParameterMismatch() {
+	lambdaContext := common_models.NewLambdaContext()
+	transactionInput := dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{
+				Put: &types.Put{
+					TableName: aws.String("someTable"),
+				},
+			},
+		},
+		ClientRequestToken: aws.String("someToken"),
+	}
+	lambdaContext.Set(common_constants.WriteTransaction, transactionInput)
+	cause := &types.IdempotentParameterMismatchException{}
+	expectedAppErr := common_errors.NewIdempotentParameterMismatchError("client request token was already used with different transaction items")
+	suite.dynamodbClient.EXPECT().TransactWriteItems(&lambdaContext, &transactionInput).Return(nil, cause)
+
+	appErr := suite.transactionManager.ExecuteWriteTransaction(&lambdaContext)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbTransactionManagerTestSuite) TestExecuteWriteTransaction_ShouldRetryWhenTransactionConflict() {
+	lambdaContext := common_models.NewLambdaContext()
+	transactionManager := common_repositories.NewDynamodbTransactionManager(suite.dynamodbClient, common_repositories.WithTransactionRetryPolicy(common_models.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    time.Millisecond,
+	}))
+	transactionInput := dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{
+				Put: &types.Put{
+					TableName: aws.String("someTable"),
+				},
+			},
+		},
+	}
+	lambdaContext.Set(common_constants.WriteTransaction, transactionInput)
+	cause := &types.TransactionCanceledException{
+		CancellationReasons: []types.CancellationReason{
+			{Code: aws.String("TransactionConflict"), Message: aws.String("Transaction is ongoing for the item")},
+		},
+	}
+	gomock.InOrder(
+		suite.dynamodbClient.EXPECT().TransactWriteItems(&lambdaContext, &transactionInput).Return(nil, cause),
+		suite.dynamodbClient.EXPECT().TransactWriteItems(&lambdaContext, &transactionInput).Return(&dynamodb.TransactWriteItemsOutput{}, nil),
+	)
+
+	appErr := transactionManager.ExecuteWriteTransaction(&lambdaContext)
+
+	suite.NoError(appErr)
+}
+
+func (suite *DynamodbTransactionManagerTestSuite) TestExecuteWriteTransaction_ShouldReturnConflictErrorWhenRetriesAreExhausted() {
+	lambdaContext := common_models.NewLambdaContext()
+	transactionManager := common_repositories.NewDynamodbTransactionManager(suite.dynamodbClient, common_repositories.WithTransactionRetryPolicy(common_models.RetryPolicy{
+		MaxAttempts: 2,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    time.Millisecond,
+	}))
+	transactionInput := dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{
+				Put: &types.Put{
+					TableName: aws.String("someTable"),
+				},
+			},
+		},
+	}
+	lambdaContext.Set(common_constants.WriteTransaction, transactionInput)
+	cause := &types.TransactionCanceledException{
+		CancellationReasons: []types.CancellationReason{
+			{Code: aws.String("TransactionConflict"), Message: aws.String("Transaction is ongoing for the item")},
+		},
+	}
+	expectedAppErr := common_errors.NewTransactionConflictError("transaction conflict on transaction item 0: Transaction is ongoing for the item", 0, "")
+	suite.dynamodbClient.EXPECT().TransactWriteItems(&lambdaContext, &transactionInput).Return(nil, cause).Times(2)
+
+	appErr := transactionManager.ExecuteWriteTransaction(&lambdaContext)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbTransactionManagerTestSuite) TestExecuteReadTransactionItems_ShouldReturnServiceUnavailableErrorWhenThrottled() {
+	lambdaContext := common_models.NewLambdaContext()
+	transactionManager := common_repositories.NewDynamodbTransactionManager(suite.dynamodbClient, common_repositories.WithTransactionRetryPolicy(common_models.RetryPolicy{
+		MaxAttempts: 1,
+	}))
+	transactionInput := dynamodb.TransactGetItemsInput{
+		TransactItems: []types.TransactGetItem{
+			{Get: &types.Get{TableName: aws.String("someTable")}},
+		},
+	}
+	lambdaContext.Set(common_constants.ReadTransaction, transactionInput)
+	cause := &types.TransactionCanceledException{
+		CancellationReasons: []types.CancellationReason{
+			{Code: aws.String("ThrottlingError"), Message: aws.String("Throughput exceeds the current capacity")},
+		},
+	}
+	expectedAppErr := common_errors.NewTransactionThrottledError("transaction throttled on transaction item 0: Throughput exceeds the current capacity", "ThrottlingError", 0, "")
+	suite.dynamodbClient.EXPECT().TransactGetItems(&lambdaContext, &transactionInput).Return(nil, cause)
+
+	_, appErr := transactionManager.ExecuteReadTransactionItems(&lambdaContext)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *DynamodbTransactionManagerTestSuite) TestExecuteBestEffortWriteTransaction_ShouldSplitTransactionAndReportCommittedParts() {
+	lambdaContext := common_models.NewLambdaContext()
+	startErr := suite.transactionManager.StartBestEffortWriteTransaction(&lambdaContext)
+	transactItems := make([]types.TransactWriteItem, 0)
+	for index := 0; index < 150; index++ {
+		transactItems = append(transactItems, types.TransactWriteItem{
+			Put: &types.Put{
+				TableName: aws.String("someTable"),
+			},
+		})
+	}
+	lambdaContext.Set(common_constants.WriteTransaction, dynamodb.TransactWriteItemsInput{
+		TransactItems: transactItems,
+	})
+	cancellationReasons := make([]types.CancellationReason, 50)
+	cancellationReasons[3] = types.CancellationReason{Code: aws.String("ConditionalCheckFailed"), Message: aws.String("The conditional request failed")}
+	cause := &types.TransactionCanceledException{
+		CancellationReasons: cancellationReasons,
+	}
+	firstPartInput := &dynamodb.TransactWriteItemsInput{TransactItems: transactItems[:100]}
+	secondPartInput := &dynamodb.TransactWriteItemsInput{TransactItems: transactItems[100:]}
+	expectedAppErr := common_errors.NewTransactionConditionFailedError("conditional check failed on transaction item 103: The conditional request failed", 103, "")
+	expectedReport := common_models.DynamodbBestEffortWriteReport{
+		Parts: []common_models.DynamodbTransactionPartReport{
+			{FirstItemIndex: 0, ItemCount: 100, IsCommitted: true},
+			{FirstItemIndex: 100, ItemCount: 50, IsCommitted: false, Error: expectedAppErr},
+		},
+	}
+	gomock.InOrder(
+		suite.dynamodbClient.EXPECT().TransactWriteItems(&lambdaContext, firstPartInput).Return(&dynamodb.TransactWriteItemsOutput{}, nil),
+		suite.dynamodbClient.EXPECT().TransactWriteItems(&lambdaContext, secondPartInput).Return(nil, cause),
+	)
+
+	report, appErr := suite.transactionManager.ExecuteBestEffortWriteTransaction(&lambdaContext)
+
+	suite.NoError(startErr)
+	suite.Equal(expectedAppErr, appErr)
+	suite.Equal(expectedReport, report)
+	suite.False(report.IsFullyCommitted())
+	suite.False(lambdaContext.Exists(common_constants.WriteTransactionBestEffort))
+}
+
+func (suite *DynamodbTransactionManagerTestSuite) TestRunInWriteTransaction_ShouldJoinInnerScopes() {
+	lambdaContext := common_models.NewLambdaContext()
+	baseRepository := common_repositories.NewDynamodbBaseRepository(suite.dynamodbClient, "someTable")
+	expectedTransactionInput := &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{
+				Put: &types.Put{
+					TableName: aws.String("someTable"),
+					Item: map[string]types.AttributeValue{
+						"key1": &types.AttributeValueMemberS{Value: "outer"},
+						"key2": &types.AttributeValueMemberS{Value: "bar"},
+					},
+				},
+			},
+			{
+				Put: &types.Put{
+					TableName: aws.String("someTable"),
+					Item: map[string]types.AttributeValue{
+						"key1": &types.AttributeValueMemberS{Value: "inner"},
+						"key2": &types.AttributeValueMemberS{Value: "bar"},
+					},
+				},
+			},
+		},
+	}
+	suite.dynamodbClient.EXPECT().TransactWriteItems(&lambdaContext, expectedTransactionInput).Return(&dynamodb.TransactWriteItemsOutput{}, nil)
+
+	appErr := suite.transactionManager.RunInWriteTransaction(&lambdaContext, func(ctx *common_models.LambdaContext) common_errors.GenericApplicationError {
+		if appErr := baseRepository.Save(ctx, DummyItem{Key1: "outer", Key2: "bar"}); appErr != nil {
+			return appErr
+		}
+		return suite.transactionManager.RunInWriteTransaction(ctx, func(ctx *common_models.LambdaContext) common_errors.GenericApplicationError {
+			return baseRepository.Save(ctx, DummyItem{Key1: "inner", Key2: "bar"})
+		})
+	})
+
+	suite.NoError(appErr)
+	suite.False(lambdaContext.Exists(common_constants.WriteTransaction))
+}
+
+func (suite *DynamodbTransactionManagerTestSuite) TestRunInWriteTransaction_ShouldNotCommitWhenSwallowedInnerScopeFailed() {
+	lambdaContext := common_models.NewLambdaContext()
+	baseRepository := common_repositories.NewDynamodbBaseRepository(suite.dynamodbClient, "someTable")
+	innerErr := common_errors.NewForbiddenError("someErr")
+
+	var failedScopeErr common_errors.GenericApplicationError
+	appErr := suite.transactionManager.RunInWriteTransaction(&lambdaContext, func(ctx *common_models.LambdaContext) common_errors.GenericApplicationError {
+		if appErr := baseRepository.Save(ctx, DummyItem{Key1: "outer", Key2: "bar"}); appErr != nil {
+			return appErr
+		}
+		failedScopeErr = suite.transactionManager.RunInWriteTransaction(ctx, func(ctx *common_models.LambdaContext) common_errors.GenericApplicationError {
+			if appErr := baseRepository.Save(ctx, DummyItem{Key1: "failed", Key2: "bar"}); appErr != nil {
+				return appErr
+			}
+			return innerErr
+		})
+		return nil
+	})
+
+	suite.Equal(innerErr, failedScopeErr)
+	suite.Equal(innerErr, appErr)
+	suite.False(lambdaContext.Exists(common_constants.WriteTransaction))
+	suite.False(lambdaContext.Exists(common_constants.WriteTransactionRollbackOnly))
+}
+
+func (suite *DynamodbTransactionManagerTestSuite) TestRunInWriteTransaction_ShouldSkipExecutionWhenNothingWasEnqueued() {
+	lambdaContext := common_models.NewLambdaContext()
+
+	appErr := suite.transactionManager.RunInWriteTransaction(&lambdaContext, func(ctx *common_models.LambdaContext) common_errors.GenericApplicationError {
+		return nil
+	})
+
+	suite.NoError(appErr)
+	suite.False(lambdaContext.Exists(common_constants.WriteTransaction))
+}
+
+func (suite *DynamodbTransactionManagerTestSuite) TestRunInWriteTransaction_ShouldDiscardTransactionWhenOutermostScopeFails() {
+	lambdaContext := common_models.NewLambdaContext()
+	baseRepository := common_repositories.NewDynamodbBaseRepository(suite.dynamodbClient, "someTable")
+	expectedAppErr := common_errors.NewForbiddenError("someErr")
+
+	appErr := suite.transactionManager.RunInWriteTransaction(&lambdaContext, func(ctx *common_models.LambdaContext) common_errors.GenericApplicationError {
+		if appErr := baseRepository.Save(ctx, DummyItem{Key1: "foo", Key2: "bar"}); appErr != nil {
+			return appErr
+		}
+		return expectedAppErr
+	})
+
+	suite.Equal(expectedAppErr, appErr)
+	suite.False(lambdaContext.Exists(common_constants.WriteTransaction))
+	suite.False(lambdaContext.Exists(common_constants.WriteTransactionItems))
+}
+
+func (suite *DynamodbTransactionManagerTestSuite) TestRunInReadTransaction_ShouldFillHandlesOfJoinedScopes() {
+	lambdaContext := common_models.NewLambdaContext()
+	baseRepository := common_repositories.NewDynamodbBaseRepository(suite.dynamodbClient, "someTable")
+	outerItem := map[string]types.AttributeValue{
+		"someKey": &types.AttributeValueMemberS{Value: "outer"},
+	}
+	innerItem := map[string]types.AttributeValue{
+		"someKey": &types.AttributeValueMemberS{Value: "inner"},
+	}
+	suite.dynamodbClient.EXPECT().TransactGetItems(&lambdaContext, gomock.Any()).DoAndReturn(
+		func(_ *common_models.LambdaContext, input *dynamodb.TransactGetItemsInput, _ ...func(*dynamodb.Options)) (*dynamodb.TransactGetItemsOutput, error) {
+			suite.Len(input.TransactItems, 2)
+			return &dynamodb.TransactGetItemsOutput{
+				Responses: []types.ItemResponse{{Item: outerItem}, {Item: innerItem}},
+			}, nil
+		})
+
+	var outerHandle, innerHandle common_models.DynamodbReadHandle
+	appErr := suite.transactionManager.RunInReadTransaction(&lambdaContext, func(ctx *common_models.LambdaContext) common_errors.GenericApplicationError {
+		var appErr common_errors.GenericApplicationError
+		outerHandle, appErr = baseRepository.FindBySimplePrimaryKeyInReadTransaction(ctx, common_models.DynamodbSimplePrimaryKey{KeyName: "someKey", Value: "outer"})
+		if appErr != nil {
+			return appErr
+		}
+		return suite.transactionManager.RunInReadTransaction(ctx, func(ctx *common_models.LambdaContext) common_errors.GenericApplicationError {
+			innerHandle, appErr = baseRepository.FindBySimplePrimaryKeyInReadTransaction(ctx, common_models.DynamodbSimplePrimaryKey{KeyName: "someKey", Value: "inner"})
+			return appErr
+		})
+	})
+	actualOuterItem, outerErr := outerHandle.Item()
+	actualInnerItem, innerErr := innerHandle.Item()
+
+	suite.NoError(appErr)
+	suite.NoError(outerErr)
+	suite.NoError(innerErr)
+	suite.Equal(outerItem, actualOuterItem)
+	suite.Equal(innerItem, actualInnerItem)
+	suite.False(lambdaContext.Exists(common_constants.ReadTransaction))
+}
+
+func (suite *DynamodbTransactionManagerTestSuite) TestRunInReadTransaction_ShouldNotExecuteWhenSwallowedInnerScopeFailed() {
+	lambdaContext := common_models.NewLambdaContext()
+	baseRepository := common_repositories.NewDynamodbBaseRepository(suite.dynamodbClient, "someTable")
+	innerErr := common_errors.NewNotFoundError("someErr")
+
+	var outerHandle, discardedHandle common_models.DynamodbReadHandle
+	appErr := suite.transactionManager.RunInReadTransaction(&lambdaContext, func(ctx *common_models.LambdaContext) common_errors.GenericApplicationError {
+		var appErr common_errors.GenericApplicationError
+		outerHandle, appErr = baseRepository.FindBySimplePrimaryKeyInReadTransaction(ctx, common_models.DynamodbSimplePrimaryKey{KeyName: "someKey", Value: "outer"})
+		if appErr != nil {
+			return appErr
+		}
+		_ = suite.transactionManager.RunInReadTransaction(ctx, func(ctx *common_models.LambdaContext) common_errors.GenericApplicationError {
+			discardedHandle, _ = baseRepository.FindBySimplePrimaryKeyInReadTransaction(ctx, common_models.DynamodbSimplePrimaryKey{KeyName: "someKey", Value: "discarded"})
+			return innerErr
+		})
+		return nil
+	})
+	_, outerErr := outerHandle.Item()
+	_, discardedErr := discardedHandle.Item()
+
+	suite.Equal(innerErr, appErr)
+	suite.Equal(common_errors.NewInternalServerError("read transaction has not been executed yet"), outerErr)
+	suite.Equal(common_errors.NewInternalServerError("read was discarded by a failed transaction scope"), discardedErr)
+	suite.False(lambdaContext.Exists(common_constants.ReadTransaction))
+	suite.False(lambdaContext.Exists(common_constants.ReadTransactionRollbackOnly))
+}
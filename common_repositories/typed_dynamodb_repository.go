@@ -0,0 +1,187 @@
+package common_repositories
+
+import (
+	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/Drathveloper/lambda_commons/v2/common_models"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"time"
+)
+
+type TypedDynamodbRepository[T any, K common_models.DynamodbPrimaryKey] interface {
+	FindByKey(ctx *common_models.LambdaContext, primaryKey K, isConsistentRead bool) (T, bool, common_errors.GenericApplicationError)
+	SaveIfNotPresent(ctx *common_models.LambdaContext, primaryKey K, item T) common_errors.GenericApplicationError
+	Save(ctx *common_models.LambdaContext, item T, expirationTime time.Duration) common_errors.GenericApplicationError
+	Update(ctx *common_models.LambdaContext, primaryKey K, update common_models.DynamodbUpdate) (T, common_errors.GenericApplicationError)
+	Delete(ctx *common_models.LambdaContext, primaryKey K, options common_models.DynamodbDeleteOptions) (T, bool, common_errors.GenericApplicationError)
+	Query(ctx *common_models.LambdaContext, query common_models.DynamodbQuery) ([]T, string, common_errors.GenericApplicationError)
+	Scan(ctx *common_models.LambdaContext, scan common_models.DynamodbScan, callback func(ctx *common_models.LambdaContext, item T) common_errors.GenericApplicationError) (common_models.DynamodbScanResult, common_errors.GenericApplicationError)
+	BatchGet(ctx *common_models.LambdaContext, primaryKeys []K, isConsistentRead bool) ([]T, common_errors.GenericApplicationError)
+	BatchSave(ctx *common_models.LambdaContext, items []T) common_errors.GenericApplicationError
+	BatchDelete(ctx *common_models.LambdaContext, primaryKeys []K) common_errors.GenericApplicationError
+	Base() DynamodbBaseRepository
+}
+
+type typedDynamodbRepository[T any, K common_models.DynamodbPrimaryKey] struct {
+	baseRepository DynamodbBaseRepository
+}
+
+func NewTypedDynamodbRepository[T any, K common_models.DynamodbPrimaryKey](baseRepository DynamodbBaseRepository) TypedDynamodbRepository[T, K] {
+	return &typedDynamodbRepository[T, K]{
+		baseRepository: baseRepository,
+	}
+}
+
+func (repository *typedDynamodbRepository[T, K]) FindByKey(ctx *common_models.LambdaContext, primaryKey K, isConsistentRead bool) (T, bool, common_errors.GenericApplicationError) {
+	var item T
+	var found bool
+	var appErr common_errors.GenericApplicationError
+	switch typedKey := any(primaryKey).(type) {
+	case common_models.DynamodbSimplePrimaryKey:
+		found, appErr = repository.baseRepository.FindBySimplePrimaryKeyInto(ctx, typedKey, isConsistentRead, &item)
+	case common_models.DynamodbComplexPrimaryKey:
+		found, appErr = repository.baseRepository.FindByComplexPrimaryKeyInto(ctx, typedKey, isConsistentRead, &item)
+	}
+	return item, found, appErr
+}
+
+func (repository *typedDynamodbRepository[T, K]) SaveIfNotPresent(ctx *common_models.LambdaContext, primaryKey K, item T) common_errors.GenericApplicationError {
+	switch typedKey := any(primaryKey).(type) {
+	case common_models.DynamodbSimplePrimaryKey:
+		return repository.baseRepository.SaveIfNotPresentWithSimplePrimaryKey(ctx, typedKey, item)
+	case common_models.DynamodbComplexPrimaryKey:
+		return repository.baseRepository.SaveIfNotPresentWithComplexPrimaryKey(ctx, typedKey, item)
+	}
+	return nil
+}
+
+// A zero expiration time saves the item without a time to live attribute.
+func (repository *typedDynamodbRepository[T, K]) Save(ctx *common_models.LambdaContext, item T, expirationTime time.Duration) common_errors.GenericApplicationError {
+	dynamodbEntity := common_models.DynamodbEntity{
+		Item:           item,
+		ExpirationTime: expirationTime,
+	}
+	return repository.baseRepository.Save(ctx, dynamodbEntity)
+}
+
+func (repository *typedDynamodbRepository[T, K]) Update(ctx *common_models.LambdaContext, primaryKey K, update common_models.DynamodbUpdate) (T, common_errors.GenericApplicationError) {
+	var attributes map[string]types.AttributeValue
+	var appErr common_errors.GenericApplicationError
+	switch typedKey := any(primaryKey).(type) {
+	case common_models.DynamodbSimplePrimaryKey:
+		attributes, appErr = repository.baseRepository.UpdateBySimplePrimaryKey(ctx, typedKey, update)
+	case common_models.DynamodbComplexPrimaryKey:
+		attributes, appErr = repository.baseRepository.UpdateByComplexPrimaryKey(ctx, typedKey, update)
+	}
+	var item T
+	if appErr != nil {
+		return item, appErr
+	}
+	if len(attributes) == 0 {
+		return item, nil
+	}
+	return item, repository.baseRepository.DecodeItem(attributes, &item)
+}
+
+func (repository *typedDynamodbRepository[T, K]) Delete(ctx *common_models.LambdaContext, primaryKey K, options common_models.DynamodbDeleteOptions) (T, bool, common_errors.GenericApplicationError) {
+	var attributes map[string]types.AttributeValue
+	var appErr common_errors.GenericApplicationError
+	switch typedKey := any(primaryKey).(type) {
+	case common_models.DynamodbSimplePrimaryKey:
+		attributes, appErr = repository.baseRepository.DeleteBySimplePrimaryKey(ctx, typedKey, options)
+	case common_models.DynamodbComplexPrimaryKey:
+		attributes, appErr = repository.baseRepository.DeleteByComplexPrimaryKey(ctx, typedKey, options)
+	}
+	var item T
+	if appErr != nil || len(attributes) == 0 {
+		return item, false, appErr
+	}
+	if appErr := repository.baseRepository.DecodeItem(attributes, &item); appErr != nil {
+		return item, false, appErr
+	}
+	return item, true, nil
+}
+
+func (repository *typedDynamodbRepository[T, K]) Query(ctx *common_models.LambdaContext, query common_models.DynamodbQuery) ([]T, string, common_errors.GenericApplicationError) {
+	result, appErr := repository.baseRepository.Query(ctx, query)
+	if appErr != nil {
+		return nil, "", appErr
+	}
+	items, appErr := decodeTypedItems[T](repository.baseRepository, result.Items)
+	if appErr != nil {
+		return nil, "", appErr
+	}
+	return items, result.ContinuationToken, nil
+}
+
+func (repository *typedDynamodbRepository[T, K]) Scan(ctx *common_models.LambdaContext, scan common_models.DynamodbScan, callback func(ctx *common_models.LambdaContext, item T) common_errors.GenericApplicationError) (common_models.DynamodbScanResult, common_errors.GenericApplicationError) {
+	return repository.baseRepository.Scan(ctx, scan, func(ctx *common_models.LambdaContext, attributes map[string]types.AttributeValue) common_errors.GenericApplicationError {
+		var item T
+		if appErr := repository.baseRepository.DecodeItem(attributes, &item); appErr != nil {
+			return appErr
+		}
+		return callback(ctx, item)
+	})
+}
+
+func (repository *typedDynamodbRepository[T, K]) BatchGet(ctx *common_models.LambdaContext, primaryKeys []K, isConsistentRead bool) ([]T, common_errors.GenericApplicationError) {
+	attributeItems := make([]map[string]types.AttributeValue, 0, len(primaryKeys))
+	switch typedKeys := any(primaryKeys).(type) {
+	case []common_models.DynamodbSimplePrimaryKey:
+		results, appErr := repository.baseRepository.BatchGetBySimplePrimaryKeys(ctx, typedKeys, isConsistentRead)
+		if appErr != nil {
+			return nil, appErr
+		}
+		for _, primaryKey := range typedKeys {
+			if attributes, exists := results[primaryKey]; exists {
+				attributeItems = append(attributeItems, attributes)
+				delete(results, primaryKey)
+			}
+		}
+	case []common_models.DynamodbComplexPrimaryKey:
+		results, appErr := repository.baseRepository.BatchGetByComplexPrimaryKeys(ctx, typedKeys, isConsistentRead)
+		if appErr != nil {
+			return nil, appErr
+		}
+		for _, primaryKey := range typedKeys {
+			if attributes, exists := results[primaryKey]; exists {
+				attributeItems = append(attributeItems, attributes)
+				delete(results, primaryKey)
+			}
+		}
+	}
+	return decodeTypedItems[T](repository.baseRepository, attributeItems)
+}
+
+func (repository *typedDynamodbRepository[T, K]) BatchSave(ctx *common_models.LambdaContext, items []T) common_errors.GenericApplicationError {
+	untypedItems := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		untypedItems = append(untypedItems, item)
+	}
+	return repository.baseRepository.BatchSave(ctx, untypedItems)
+}
+
+func (repository *typedDynamodbRepository[T, K]) BatchDelete(ctx *common_models.LambdaContext, primaryKeys []K) common_errors.GenericApplicationError {
+	switch typedKeys := any(primaryKeys).(type) {
+	case []common_models.DynamodbSimplePrimaryKey:
+		return repository.baseRepository.BatchDeleteBySimplePrimaryKeys(ctx, typedKeys)
+	case []common_models.DynamodbComplexPrimaryKey:
+		return repository.baseRepository.BatchDeleteByComplexPrimaryKeys(ctx, typedKeys)
+	}
+	return nil
+}
+
+func (repository *typedDynamodbRepository[T, K]) Base() DynamodbBaseRepository {
+	return repository.baseRepository
+}
+
+func decodeTypedItems[T any](baseRepository DynamodbBaseRepository, attributeItems []map[string]types.AttributeValue) ([]T, common_errors.GenericApplicationError) {
+	items := make([]T, 0, len(attributeItems))
+	for _, attributes := range attributeItems {
+		var item T
+		if appErr := baseRepository.DecodeItem(attributes, &item); appErr != nil {
+			return nil, appErr
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
@@ -0,0 +1,209 @@
+package common_repositories_test
+
+import (
+	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/Drathveloper/lambda_commons/v2/common_models"
+	"github.com/Drathveloper/lambda_commons/v2/common_repositories"
+	"github.com/Drathveloper/lambda_commons/v2/mocks"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type TypedDynamodbRepositoryTestSuite struct {
+	suite.Suite
+	dynamodbClient    *mocks.MockDynamodbClientAPI
+	simpleRepository  common_repositories.TypedDynamodbRepository[DummyItem, common_models.DynamodbSimplePrimaryKey]
+	complexRepository common_repositories.TypedDynamodbRepository[DummyItem, common_models.DynamodbComplexPrimaryKey]
+}
+
+func TestTypedDynamodbRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(TypedDynamodbRepositoryTestSuite))
+}
+
+func (suite *TypedDynamodbRepositoryTestSuite) SetupTest() {
+	controller := gomock.NewController(suite.T())
+	suite.dynamodbClient = mocks.NewMockDynamodbClientAPI(controller)
+	baseRepository := common_repositories.NewDynamodbBaseRepository(suite.dynamodbClient, "someTable")
+	suite.simpleRepository = common_repositories.NewTypedDynamodbRepository[DummyItem, common_models.DynamodbSimplePrimaryKey](baseRepository)
+	suite.complexRepository = common_repositories.NewTypedDynamodbRepository[DummyItem, common_models.DynamodbComplexPrimaryKey](baseRepository)
+}
+
+func (suite *TypedDynamodbRepositoryTestSuite) TestFindByKey_ShouldDecodeItem() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "key1",
+		Value:   "foo",
+	}
+	getItemOutput := &dynamodb.GetItemOutput{
+		Item: map[string]types.AttributeValue{
+			"key1": &types.AttributeValueMemberS{Value: "foo"},
+			"key2": &types.AttributeValueMemberS{Value: "bar"},
+		},
+	}
+	suite.dynamodbClient.EXPECT().GetItem(&context, gomock.Any()).Return(getItemOutput, nil)
+
+	item, found, appErr := suite.simpleRepository.FindByKey(&context, primaryKey, false)
+
+	suite.NoError(appErr)
+	suite.True(found)
+	suite.Equal(DummyItem{Key1: "foo", Key2: "bar"}, item)
+}
+
+func (suite *TypedDynamodbRepositoryTestSuite) TestFindByKey_ShouldReturnNotFoundWhenItemIsAbsent() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbComplexPrimaryKey{
+		PartitionKey: common_models.DynamodbSimplePrimaryKey{KeyName: "key1", Value: "foo"},
+		SortKey:      common_models.DynamodbSimplePrimaryKey{KeyName: "key2", Value: "bar"},
+	}
+	suite.dynamodbClient.EXPECT().GetItem(&context, gomock.Any()).Return(&dynamodb.GetItemOutput{}, nil)
+
+	item, found, appErr := suite.complexRepository.FindByKey(&context, primaryKey, true)
+
+	suite.NoError(appErr)
+	suite.False(found)
+	suite.Equal(DummyItem{}, item)
+}
+
+func (suite *TypedDynamodbRepositoryTestSuite) TestSave_ShouldStoreExpirationWhenExpirationTimeIsSet() {
+	context := common_models.NewLambdaContext()
+	baseRepository := common_repositories.NewDynamodbBaseRepository(suite.dynamodbClient, "someTable", common_repositories.WithTimeToLive(common_models.DynamodbTimeToLiveConfig{
+		Attribute: "expiresAt",
+		Clock: func() time.Time {
+			return time.Unix(1700000000, 0)
+		},
+	}))
+	repository := common_repositories.NewTypedDynamodbRepository[DummyItem, common_models.DynamodbSimplePrimaryKey](baseRepository)
+	putItemInput := dynamodb.PutItemInput{
+		TableName: aws.String("someTable"),
+		Item: map[string]types.AttributeValue{
+			"key1":      &types.AttributeValueMemberS{Value: "foo"},
+			"key2":      &types.AttributeValueMemberS{Value: "bar"},
+			"expiresAt": &types.AttributeValueMemberN{Value: "1700003600"},
+		},
+	}
+	suite.dynamodbClient.EXPECT().PutItem(&context, &putItemInput).Return(&dynamodb.PutItemOutput{}, nil)
+
+	appErr := repository.Save(&context, DummyItem{Key1: "foo", Key2: "bar"}, time.Hour)
+
+	suite.NoError(appErr)
+}
+
+func (suite *TypedDynamodbRepositoryTestSuite) TestSave_ShouldNotStoreExpirationWhenExpirationTimeIsZero() {
+	context := common_models.NewLambdaContext()
+	putItemInput := dynamodb.PutItemInput{
+		TableName: aws.String("someTable"),
+		Item: map[string]types.AttributeValue{
+			"key1": &types.AttributeValueMemberS{Value: "foo"},
+			"key2": &types.AttributeValueMemberS{Value: "bar"},
+		},
+	}
+	suite.dynamodbClient.EXPECT().PutItem(&context, &putItemInput).Return(&dynamodb.PutItemOutput{}, nil)
+
+	appErr := suite.simpleRepository.Save(&context, DummyItem{Key1: "foo", Key2: "bar"}, 0)
+
+	suite.NoError(appErr)
+}
+
+func (suite *TypedDynamodbRepositoryTestSuite) TestUpdate_ShouldDecodeUpdatedItem() {
+	context := common_models.NewLambdaContext()
+	primaryKey := common_models.DynamodbSimplePrimaryKey{
+		KeyName: "key1",
+		Value:   "foo",
+	}
+	update := common_models.DynamodbUpdate{
+		Operations: []common_models.DynamodbUpdateOperation{
+			{Action: common_models.DynamodbUpdateSet, AttributeName: "key2", Value: "baz"},
+		},
+	}
+	updateItemOutput := &dynamodb.UpdateItemOutput{
+		Attributes: map[string]types.AttributeValue{
+			"key1": &types.AttributeValueMemberS{Value: "foo"},
+			"key2": &types.AttributeValueMemberS{Value: "baz"},
+		},
+	}
+	suite.dynamodbClient.EXPECT().UpdateItem(&context, gomock.Any()).Return(updateItemOutput, nil)
+
+	item, appErr := suite.simpleRepository.Update(&context, primaryKey, update)
+
+	suite.NoError(appErr)
+	suite.Equal(DummyItem{Key1: "foo", Key2: "baz"}, item)
+}
+
+func (suite *TypedDynamodbRepositoryTestSuite) TestQuery_ShouldReturnInternalServerErrorWhenItemCannotBeDecoded() {
+	context := common_models.NewLambdaContext()
+	query := common_models.DynamodbQuery{
+		PartitionKey: common_models.DynamodbSimplePrimaryKey{KeyName: "key1", Value: "foo"},
+	}
+	queryOutput := &dynamodb.QueryOutput{
+		Items: []map[string]types.AttributeValue{
+			{"key2": &types.AttributeValueMemberL{Value: []types.AttributeValue{}}},
+		},
+	}
+	suite.dynamodbClient.EXPECT().Query(&context, gomock.Any()).Return(queryOutput, nil)
+	expectedAppErr := common_errors.NewInternalServerError("error while decoding item")
+
+	_, _, appErr := suite.simpleRepository.Query(&context, query)
+
+	suite.Equal(expectedAppErr, appErr)
+}
+
+func (suite *TypedDynamodbRepositoryTestSuite) TestScan_ShouldDecodeItemsBeforeCallback() {
+	context := common_models.NewLambdaContext()
+	scanOutput := &dynamodb.ScanOutput{
+		Items: []map[string]types.AttributeValue{
+			{
+				"key1": &types.AttributeValueMemberS{Value: "foo"},
+				"key2": &types.AttributeValueMemberS{Value: "bar"},
+			},
+		},
+	}
+	suite.dynamodbClient.EXPECT().Scan(gomock.Any(), gomock.Any()).Return(scanOutput, nil)
+	items := make([]DummyItem, 0)
+
+	result, appErr := suite.simpleRepository.Scan(&context, common_models.DynamodbScan{}, func(ctx *common_models.LambdaContext, item DummyItem) common_errors.GenericApplicationError {
+		items = append(items, item)
+		return nil
+	})
+
+	suite.NoError(appErr)
+	suite.True(result.IsCompleted)
+	suite.Equal([]DummyItem{{Key1: "foo", Key2: "bar"}}, items)
+}
+
+func (suite *TypedDynamodbRepositoryTestSuite) TestBatchGet_ShouldReturnItemsInRequestedOrder() {
+	context := common_models.NewLambdaContext()
+	primaryKeys := []common_models.DynamodbSimplePrimaryKey{
+		{KeyName: "key1", Value: "first"},
+		{KeyName: "key1", Value: "missing"},
+		{KeyName: "key1", Value: "second"},
+	}
+	batchGetItemOutput := &dynamodb.BatchGetItemOutput{
+		Responses: map[string][]map[string]types.AttributeValue{
+			"someTable": {
+				{
+					"key1": &types.AttributeValueMemberS{Value: "second"},
+					"key2": &types.AttributeValueMemberS{Value: "bar"},
+				},
+				{
+					"key1": &types.AttributeValueMemberS{Value: "first"},
+					"key2": &types.AttributeValueMemberS{Value: "foo"},
+				},
+			},
+		},
+	}
+	suite.dynamodbClient.EXPECT().BatchGetItem(&context, gomock.Any()).Return(batchGetItemOutput, nil)
+	expectedItems := []DummyItem{
+		{Key1: "first", Key2: "foo"},
+		{Key1: "second", Key2: "bar"},
+	}
+
+	items, appErr := suite.simpleRepository.BatchGet(&context, primaryKeys, false)
+
+	suite.NoError(appErr)
+	suite.Equal(expectedItems, items)
+}
@@ -0,0 +1,51 @@
+package common_repositories
+
+import (
+	"github.com/Drathveloper/lambda_commons/v2/common_errors"
+	"github.com/Drathveloper/lambda_commons/v2/common_models"
+	"time"
+)
+
+type TypedRedisRepository[T any] interface {
+	Save(ctx *common_models.LambdaContext, key string, value T, expirationTime time.Duration) common_errors.GenericApplicationError
+	FindKey(ctx *common_models.LambdaContext, key string) (T, bool, common_errors.GenericApplicationError)
+	GetTTL(ctx *common_models.LambdaContext, key string) (bool, time.Duration, common_errors.GenericApplicationError)
+	DeleteKey(ctx *common_models.LambdaContext, key string) common_errors.GenericApplicationError
+}
+
+type typedRedisRepository[T any] struct {
+	baseRepository RedisBaseRepository
+}
+
+func NewTypedRedisRepository[T any](baseRepository RedisBaseRepository) TypedRedisRepository[T] {
+	return &typedRedisRepository[T]{
+		baseRepository: baseRepository,
+	}
+}
+
+func (repository *typedRedisRepository[T]) Save(ctx *common_models.LambdaContext, key string, value T, expirationTime time.Duration) common_errors.GenericApplicationError {
+	redisEntity := common_models.RedisEntity{
+		Key:            key,
+		Value:          value,
+		ExpirationTime: expirationTime,
+	}
+	return repository.baseRepository.Save(ctx, redisEntity)
+}
+
+func (repository *typedRedisRepository[T]) FindKey(ctx *common_models.LambdaContext, key string) (T, bool, common_errors.GenericApplicationError) {
+	var value T
+	found, appErr := repository.baseRepository.FindKey(ctx, key, &value)
+	if appErr != nil {
+		var empty T
+		return empty, found, appErr
+	}
+	return value, found, nil
+}
+
+func (repository *typedRedisRepository[T]) GetTTL(ctx *common_models.LambdaContext, key string) (bool, time.Duration, common_errors.GenericApplicationError) {
+	return repository.baseRepository.GetTTL(ctx, key)
+}
+
+func (repository *typedRedisRepository[T]) DeleteKey(ctx *common_models.LambdaContext, key string) common_errors.GenericApplicationError {
+	return repository.baseRepository.DeleteKey(ctx, key)
+}
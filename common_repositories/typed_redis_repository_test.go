@@ -0,0 +1,66 @@
+package common_repositories_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Drathveloper/lambda_commons/v2/common_models"
+	"github.com/Drathveloper/lambda_commons/v2/common_repositories"
+	"github.com/go-redis/redismock/v8"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type TypedRedisRepositoryTestSuite struct {
+	suite.Suite
+	namespace       string
+	key             string
+	client          redismock.ClientMock
+	typedRepository common_repositories.TypedRedisRepository[DummyValue]
+}
+
+func TestTypedRedisRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(TypedRedisRepositoryTestSuite))
+}
+
+func (suite *TypedRedisRepositoryTestSuite) SetupTest() {
+	redisClient, redisClientMock := redismock.NewClientMock()
+	suite.client = redisClientMock
+	suite.namespace = "dummy-namespace"
+	suite.key = "xx"
+	suite.typedRepository = common_repositories.NewTypedRedisRepository[DummyValue](common_repositories.NewRedisBaseRepository(redisClient, suite.namespace))
+}
+
+func (suite *TypedRedisRepositoryTestSuite) TestSaveShouldSucceed() {
+	ctx := common_models.NewLambdaContext()
+	namespacedKey := fmt.Sprintf("%s:%s", suite.namespace, suite.key)
+	suite.client.ExpectSet(namespacedKey, []byte(`{"field":"value"}`), time.Hour).SetVal("")
+
+	err := suite.typedRepository.Save(&ctx, suite.key, DummyValue{Field: "value"}, time.Hour)
+
+	suite.NoError(err)
+}
+
+func (suite *TypedRedisRepositoryTestSuite) TestFindKeyShouldSucceed() {
+	ctx := common_models.NewLambdaContext()
+	namespacedKey := fmt.Sprintf("%s:%s", suite.namespace, suite.key)
+	suite.client.ExpectGet(namespacedKey).SetVal(`{"field":"value"}`)
+
+	value, exists, err := suite.typedRepository.FindKey(&ctx, suite.key)
+
+	suite.NoError(err)
+	suite.True(exists)
+	suite.Equal(DummyValue{Field: "value"}, value)
+}
+
+func (suite *TypedRedisRepositoryTestSuite) TestFindKeyShouldReturnFalseWhenRedisKeyDoesNotExist() {
+	ctx := common_models.NewLambdaContext()
+	namespacedKey := fmt.Sprintf("%s:%s", suite.namespace, suite.key)
+	suite.client.ExpectGet(namespacedKey).SetErr(errors.New("redis: nil"))
+
+	value, exists, err := suite.typedRepository.FindKey(&ctx, suite.key)
+
+	suite.NoError(err)
+	suite.False(exists)
+	suite.Equal(DummyValue{}, value)
+}